@@ -4,6 +4,9 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package main
 
 import (
+	// embed the timezone database for schedule timezones in minimal images
+	_ "time/tzdata"
+
 	"github.com/MyelinBots/pigeonbot-go/internal/commands"
 )
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,16 +10,21 @@ import (
 )
 
 type Config struct {
-	AppConfig  AppConfig  `env:"APPCONFIG"`
-	IRCConfig  IRCConfig  `env:"IRCCONFIG"`
-	DBConfig   DBConfig   `env:"DBCONFIG"`
-	GameConfig GameConfig `env:"GAMECONFIG"`
+	AppConfig         AppConfig         `env:"APPCONFIG"`
+	IRCConfig         IRCConfig         `env:"IRCCONFIG"`
+	DBConfig          DBConfig          `env:"DBCONFIG"`
+	GameConfig        GameConfig        `env:"GAMECONFIG"`
+	PermissionsConfig PermissionsConfig `env:"PERMISSIONSCONFIG"`
+	// Networks runs the bot on several IRC networks; when empty IRCConfig is the only network
+	Networks []NetworkConfig
 }
 
 type AppConfig struct {
 	APPName string `default:"pigeonbot"`
 	Version string `default:"x.x.x" env:"VERSION"`
 	Port    int    `default:"8080" env:"APP_PORT"`
+	// ShutdownTimeout is how long, in seconds, saving and quitting may take on shutdown
+	ShutdownTimeout int `default:"10" env:"SHUTDOWN_TIMEOUT"`
 }
 
 type IRCConfig struct {
@@ -32,9 +38,227 @@ type IRCConfig struct {
 	Network          string `env:"NETWORK"`
 	NickservCommand  string `env:"NICKSERV_COMMAND" default:"PRIVMSG NickServ IDENTIFY %s"`
 	NickservPassword string `env:"NICKSERV_PASSWORD" default:""`
+	QuitMessage      string `env:"QUIT_MESSAGE" default:"🕊️ ~ coo coo ~ flying away ~ 🕊️"`
+	// SASLMechanism is "plain" or "external"; empty skips SASL and uses NickServ.
+	// PLAIN falls back to Nick and NickservPassword when no username/password is set.
+	SASLMechanism string `env:"SASL_MECHANISM"`
+	SASLUsername  string `env:"SASL_USERNAME"`
+	SASLPassword  string `env:"SASL_PASSWORD"`
+	// TLS settings; certificates are verified unless TLSSkipVerify is set
+	TLSCAFile     string `env:"TLS_CA_FILE"` // PEM bundle trusted instead of the system roots
+	TLSServerName string `env:"TLS_SERVER_NAME"`
+	TLSSkipVerify bool   `env:"TLS_SKIP_VERIFY"`
+	TLSCertFile   string `env:"TLS_CERT_FILE"` // client certificate, e.g. for SASL EXTERNAL
+	TLSKeyFile    string `env:"TLS_KEY_FILE"`
+	// reconnect delays in seconds; the delay doubles after each failed attempt
+	ReconnectMin int `env:"RECONNECT_MIN" default:"2"`
+	ReconnectMax int `env:"RECONNECT_MAX" default:"300"`
+	// flood protection: up to FloodBurst lines at once, then one every FloodInterval milliseconds
+	FloodBurst    int `env:"FLOOD_BURST" default:"5"`
+	FloodInterval int `env:"FLOOD_INTERVAL" default:"1000"`
+}
+
+// NetworkConfig is one entry of Config.Networks. Empty fields fall back to
+// IRCConfig and GameConfig, so shared settings only need to be set once.
+// It has no env tags on purpose: configor would apply them to every entry.
+type NetworkConfig struct {
+	Network          string
+	Host             string
+	Port             int
+	SSL              *bool
+	Nick             string
+	RealName         string
+	Channels         []string
+	NickservCommand  string
+	NickservPassword string
+	QuitMessage      string
+	SASLMechanism    string
+	SASLUsername     string
+	SASLPassword     string
+	TLSCAFile        string
+	TLSServerName    string
+	TLSSkipVerify    *bool
+	TLSCertFile      string
+	TLSKeyFile       string
+	FloodBurst       int
+	FloodInterval    int
+	ReconnectMin     int
+	ReconnectMax     int
+	// game settings for this network
+	Interval          int
+	PigeonsFile       string
+	MessagesFile      string
+	Schedule          ScheduleOverride
+	ChannelSchedules  map[string]ScheduleConfig
+	ScorePools        map[string]string
+	SeasonDays        *int // 0 turns seasons off on this network only
+	SeasonKeepPercent *int
+	Persistence       string
+	SaveDelay         int
+}
+
+// Network is a resolved network: its connection settings and game config
+type Network struct {
+	IRC  IRCConfig
+	Game GameConfig
+}
+
+// ResolveNetworks applies each Networks entry over the shared config. Without
+// entries the single network from IRCConfig is returned.
+func (c Config) ResolveNetworks() ([]Network, error) {
+	if len(c.Networks) == 0 {
+		return []Network{{IRC: c.IRCConfig, Game: c.GameConfig}}, nil
+	}
+
+	networks := make([]Network, 0, len(c.Networks))
+	seen := make(map[string]bool, len(c.Networks))
+	for i, n := range c.Networks {
+		irc := c.IRCConfig
+		overlay(&irc.Network, n.Network)
+		overlay(&irc.Host, n.Host)
+		overlay(&irc.Nick, n.Nick)
+		overlay(&irc.RealName, n.RealName)
+		overlay(&irc.NickservCommand, n.NickservCommand)
+		overlay(&irc.NickservPassword, n.NickservPassword)
+		overlay(&irc.QuitMessage, n.QuitMessage)
+		overlay(&irc.SASLMechanism, n.SASLMechanism)
+		overlay(&irc.SASLUsername, n.SASLUsername)
+		overlay(&irc.SASLPassword, n.SASLPassword)
+		overlay(&irc.TLSCAFile, n.TLSCAFile)
+		overlay(&irc.TLSServerName, n.TLSServerName)
+		overlay(&irc.TLSCertFile, n.TLSCertFile)
+		overlay(&irc.TLSKeyFile, n.TLSKeyFile)
+		if n.Port != 0 {
+			irc.Port = n.Port
+		}
+		if n.FloodBurst != 0 {
+			irc.FloodBurst = n.FloodBurst
+		}
+		if n.FloodInterval != 0 {
+			irc.FloodInterval = n.FloodInterval
+		}
+		if n.ReconnectMin != 0 {
+			irc.ReconnectMin = n.ReconnectMin
+		}
+		if n.ReconnectMax != 0 {
+			irc.ReconnectMax = n.ReconnectMax
+		}
+		if n.SSL != nil {
+			irc.SSL = *n.SSL
+		}
+		if n.TLSSkipVerify != nil {
+			irc.TLSSkipVerify = *n.TLSSkipVerify
+		}
+		if len(n.Channels) > 0 {
+			irc.Channels = n.Channels
+			irc.ChannelsString = strings.Join(n.Channels, ",")
+		}
+
+		if irc.Network == "" || irc.Host == "" {
+			return nil, fmt.Errorf("networks[%d]: network and host are required", i)
+		}
+		if seen[strings.ToLower(irc.Network)] {
+			return nil, fmt.Errorf("networks[%d]: network %q is listed twice", i, irc.Network)
+		}
+		seen[strings.ToLower(irc.Network)] = true
+
+		game := c.GameConfig
+		if n.Interval != 0 {
+			game.Interval = n.Interval
+		}
+		if n.PigeonsFile != "" {
+			catalogue, err := LoadPigeonCatalogue(resolvePath(n.PigeonsFile))
+			if err != nil {
+				return nil, fmt.Errorf("networks[%d]: loading pigeon catalogue: %w", i, err)
+			}
+			game.PigeonsFile = n.PigeonsFile
+			game.Pigeons = catalogue
+		}
+		if n.MessagesFile != "" {
+			messages, err := LoadMessageCatalogue(resolvePath(n.MessagesFile))
+			if err != nil {
+				return nil, fmt.Errorf("networks[%d]: loading messages: %w", i, err)
+			}
+			game.MessagesFile = n.MessagesFile
+			game.Messages = messages
+		}
+		game.Schedule = n.Schedule.apply(game.Schedule)
+		if len(n.ChannelSchedules) > 0 {
+			schedules := make(map[string]ScheduleConfig, len(c.GameConfig.ChannelSchedules)+len(n.ChannelSchedules))
+			for name, s := range c.GameConfig.ChannelSchedules {
+				schedules[name] = s
+			}
+			for name, s := range n.ChannelSchedules {
+				schedules[name] = s
+			}
+			game.ChannelSchedules = schedules
+		}
+		if len(n.ScorePools) > 0 {
+			pools := make(map[string]string, len(c.GameConfig.ScorePools)+len(n.ScorePools))
+			for name, pool := range c.GameConfig.ScorePools {
+				pools[name] = pool
+			}
+			for name, pool := range n.ScorePools {
+				pools[name] = pool
+			}
+			game.ScorePools = pools
+		}
+		if n.SeasonDays != nil {
+			game.SeasonDays = *n.SeasonDays
+		}
+		if n.SeasonKeepPercent != nil {
+			game.SeasonKeepPercent = *n.SeasonKeepPercent
+		}
+		overlay(&game.Persistence, n.Persistence)
+		if n.SaveDelay != 0 {
+			game.SaveDelay = n.SaveDelay
+		}
+
+		networks = append(networks, Network{IRC: irc, Game: game})
+	}
+	return networks, nil
+}
+
+func overlay(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// Redacted returns a copy of c with passwords and TLS key paths masked, for logging
+func (c Config) Redacted() Config {
+	redact(&c.IRCConfig.NickservPassword, &c.IRCConfig.SASLPassword, &c.IRCConfig.TLSKeyFile)
+	redact(&c.DBConfig.Password)
+	c.Networks = append([]NetworkConfig(nil), c.Networks...)
+	for i := range c.Networks {
+		n := &c.Networks[i]
+		redact(&n.NickservPassword, &n.SASLPassword, &n.TLSKeyFile)
+	}
+	return c
+}
+
+func redact(values ...*string) {
+	for _, v := range values {
+		if *v != "" {
+			*v = "[redacted]"
+		}
+	}
+}
+
+// PermissionsConfig lists host masks (nick!ident@host, * and ? wildcards)
+// that are trusted regardless of channel modes
+type PermissionsConfig struct {
+	OwnersString  string `env:"OWNERS"`
+	Owners        []string
+	TrustedString string `env:"TRUSTED"` // may use operator commands in every channel
+	Trusted       []string
 }
 
 type DBConfig struct {
+	// Driver is "postgres" or "sqlite". SQLite keeps everything in the file
+	// at Path and ignores the connection settings below.
+	Driver   string `default:"postgres" env:"DBDRIVER"`
+	Path     string `default:"pigeonbot.db" env:"DBPATH"`
 	Host     string `default:"localhost" env:"DBHOST"`
 	DataBase string `default:"pigeon" env:"DBNAME"`
 	User     string `default:"postgres" env:"DBUSERNAME"`
@@ -44,7 +268,166 @@ type DBConfig struct {
 }
 
 type GameConfig struct {
-	Interval int `env:"INTERVAL" default:"10"`
+	Interval    int    `env:"INTERVAL" default:"10"`
+	PigeonsFile string `env:"PIGEONS_FILE" default:"config/pigeons.json"`
+	Pigeons     PigeonCatalogue
+	// MessagesFile overrides the built-in message templates
+	MessagesFile string `env:"MESSAGES_FILE" default:"config/messages.json"`
+	Messages     MessageCatalogue
+	Schedule     ScheduleConfig
+	// ChannelSchedules overrides the non-zero schedule fields per channel
+	ChannelSchedules map[string]ScheduleConfig
+	// SeasonDays is the length of a season; 0 turns seasons off. When a season
+	// ends, scores keep SeasonKeepPercent percent of their value (0 resets them).
+	SeasonDays        int `env:"SEASON_DAYS" default:"0"`
+	SeasonKeepPercent int `env:"SEASON_KEEP_PERCENT" default:"0"`
+	// ScorePools maps a channel to a shared score pool. Channels in the same
+	// pool share their players and points, stored under the pool name.
+	ScorePools map[string]string
+	// Persistence is when scores are written to the database: "immediate"
+	// before a shot is announced, "debounced" once no score has changed for
+	// SaveDelay milliseconds, or "batch" every SaveDelay milliseconds. Writes
+	// that fail are retried until the database is back.
+	Persistence string `env:"PERSISTENCE" default:"immediate"`
+	SaveDelay   int    `env:"SAVE_DELAY" default:"2000"`
+}
+
+// ScorePoolFor returns the score pool channel plays in; without one it is the channel itself
+func (g GameConfig) ScorePoolFor(channel string) string {
+	for name, pool := range g.ScorePools {
+		if strings.EqualFold(name, channel) && strings.TrimSpace(pool) != "" {
+			return strings.TrimSpace(pool)
+		}
+	}
+	return channel
+}
+
+// ScheduleConfig controls when pigeons spawn. Intervals are in seconds;
+// when MinInterval and MaxInterval are unset GameConfig.Interval is used.
+type ScheduleConfig struct {
+	MinInterval int    `env:"MIN_INTERVAL"`
+	MaxInterval int    `env:"MAX_INTERVAL"`
+	QuietHours  string `env:"QUIET_HOURS"` // e.g. "23:00-07:00,13:00-14:00"
+	Timezone    string `env:"TIMEZONE"`
+	// a channel with ActiveMessages messages within ActivityWindow seconds
+	// spawns ActiveFactor times as often; one silent for IdleAfter seconds
+	// waits IdleFactor times as long
+	ActiveMessages int     `env:"ACTIVE_MESSAGES" default:"10"`
+	ActivityWindow int     `env:"ACTIVITY_WINDOW" default:"300"`
+	ActiveFactor   float64 `env:"ACTIVE_FACTOR" default:"0.5"`
+	IdleAfter      int     `env:"IDLE_AFTER" default:"1800"`
+	IdleFactor     float64 `env:"IDLE_FACTOR" default:"2"`
+}
+
+// ScheduleOverride is a ScheduleConfig whose zero fields are left unchanged.
+// It has no tags, so configor does not fill in defaults for the fields a
+// network entry leaves out.
+type ScheduleOverride struct {
+	MinInterval    int
+	MaxInterval    int
+	QuietHours     string
+	Timezone       string
+	ActiveMessages int
+	ActivityWindow int
+	ActiveFactor   float64
+	IdleAfter      int
+	IdleFactor     float64
+}
+
+// ScheduleFor returns the schedule for channel with its overrides applied
+func (g GameConfig) ScheduleFor(channel string) ScheduleConfig {
+	s := g.Schedule
+	for name, o := range g.ChannelSchedules {
+		if strings.EqualFold(name, channel) {
+			s = ScheduleOverride(o).apply(s)
+		}
+	}
+	return s
+}
+
+// apply returns s with o's non-zero fields set
+func (o ScheduleOverride) apply(s ScheduleConfig) ScheduleConfig {
+	if o.MinInterval != 0 {
+		s.MinInterval = o.MinInterval
+	}
+	if o.MaxInterval != 0 {
+		s.MaxInterval = o.MaxInterval
+	}
+	if o.QuietHours != "" {
+		s.QuietHours = o.QuietHours
+	}
+	if o.Timezone != "" {
+		s.Timezone = o.Timezone
+	}
+	if o.ActiveMessages != 0 {
+		s.ActiveMessages = o.ActiveMessages
+	}
+	if o.ActivityWindow != 0 {
+		s.ActivityWindow = o.ActivityWindow
+	}
+	if o.ActiveFactor != 0 {
+		s.ActiveFactor = o.ActiveFactor
+	}
+	if o.IdleAfter != 0 {
+		s.IdleAfter = o.IdleAfter
+	}
+	if o.IdleFactor != 0 {
+		s.IdleFactor = o.IdleFactor
+	}
+	return s
+}
+
+// PigeonCatalogue lists the pigeon species a game can spawn.
+// Channels overrides the list for individual channels.
+type PigeonCatalogue struct {
+	Pigeons  []PigeonConfig
+	Channels map[string][]PigeonConfig
+}
+
+type PigeonConfig struct {
+	Type    string
+	Points  int
+	Success int  // hit chance in percent
+	Weight  *int // relative spawn weight; unset is 1 and 0 never spawns on its own
+	EggsMin int
+	EggsMax int
+	// eggs that crack when collected, clamped to the eggs laid
+	CrackMin int
+	CrackMax int
+}
+
+// ForChannel returns the pigeons for channel, falling back to the shared list
+func (c PigeonCatalogue) ForChannel(channel string) []PigeonConfig {
+	for name, pigeons := range c.Channels {
+		if strings.EqualFold(name, channel) && len(pigeons) > 0 {
+			return pigeons
+		}
+	}
+	return c.Pigeons
+}
+
+// MessageCatalogue overrides message templates by message ID, in every
+// locale. Channels overrides them further for individual channels.
+type MessageCatalogue struct {
+	Messages map[string]string
+	Channels map[string]map[string]string
+}
+
+// ForChannel returns the overrides for channel: the shared ones with the channel's on top
+func (c MessageCatalogue) ForChannel(channel string) map[string]string {
+	merged := make(map[string]string, len(c.Messages))
+	for id, template := range c.Messages {
+		merged[id] = template
+	}
+	for name, messages := range c.Channels {
+		if !strings.EqualFold(name, channel) {
+			continue
+		}
+		for id, template := range messages {
+			merged[id] = template
+		}
+	}
+	return merged
 }
 
 // findProjectRoot walks up from the current directory looking for go.mod
@@ -67,17 +450,101 @@ func findProjectRoot() string {
 	}
 }
 
-func LoadConfigOrPanic() Config {
-	var config = Config{}
-
-	configPath := "config/config.dev.json"
+// resolvePath makes a relative path relative to the project root when there is one
+func resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
 	if root := findProjectRoot(); root != "" {
-		configPath = filepath.Join(root, configPath)
+		return filepath.Join(root, path)
+	}
+	return path
+}
+
+// LoadPigeonCatalogue reads a pigeon catalogue file (JSON, YAML or TOML).
+// A missing file yields an empty catalogue so the built-in pigeons are used.
+func LoadPigeonCatalogue(path string) (PigeonCatalogue, error) {
+	catalogue := PigeonCatalogue{}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return catalogue, nil
+		}
+		return catalogue, err
 	}
 
-	configor.Load(&config, configPath)
+	if err := configor.Load(&catalogue, path); err != nil {
+		return catalogue, err
+	}
+	if err := checkWeights(catalogue.Pigeons); err != nil {
+		return catalogue, err
+	}
+	for _, pigeons := range catalogue.Channels {
+		if err := checkWeights(pigeons); err != nil {
+			return catalogue, err
+		}
+	}
+	return catalogue, nil
+}
+
+// checkWeights rejects negative spawn weights
+func checkWeights(pigeons []PigeonConfig) error {
+	for _, p := range pigeons {
+		if p.Weight != nil && *p.Weight < 0 {
+			return fmt.Errorf("pigeon %q: weight must not be negative", p.Type)
+		}
+	}
+	return nil
+}
+
+// LoadMessageCatalogue reads message overrides (JSON, YAML or TOML). A
+// missing file yields no overrides.
+func LoadMessageCatalogue(path string) (MessageCatalogue, error) {
+	catalogue := MessageCatalogue{}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return catalogue, nil
+		}
+		return catalogue, err
+	}
+
+	err := configor.Load(&catalogue, path)
+	return catalogue, err
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func LoadConfigOrPanic() Config {
+	var config = Config{}
+
+	configor.Load(&config, resolvePath("config/config.dev.json"))
 
 	config.IRCConfig.Channels = strings.Split(config.IRCConfig.ChannelsString, ",")
+	config.PermissionsConfig.Owners = append(config.PermissionsConfig.Owners, splitList(config.PermissionsConfig.OwnersString)...)
+	config.PermissionsConfig.Trusted = append(config.PermissionsConfig.Trusted, splitList(config.PermissionsConfig.TrustedString)...)
+
+	if config.GameConfig.PigeonsFile != "" {
+		catalogue, err := LoadPigeonCatalogue(resolvePath(config.GameConfig.PigeonsFile))
+		if err != nil {
+			panic(fmt.Sprintf("failed to load pigeon catalogue: %v", err))
+		}
+		config.GameConfig.Pigeons = catalogue
+	}
+	if config.GameConfig.MessagesFile != "" {
+		messages, err := LoadMessageCatalogue(resolvePath(config.GameConfig.MessagesFile))
+		if err != nil {
+			panic(fmt.Sprintf("failed to load messages: %v", err))
+		}
+		config.GameConfig.Messages = messages
+	}
 
 	return config
 }
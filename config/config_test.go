@@ -1,11 +1,15 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/jinzhu/configor"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfigStructs(t *testing.T) {
@@ -151,3 +155,279 @@ func TestIRCConfigChannels(t *testing.T) {
 		assert.Equal(t, []string{"#a", "#b", "#c"}, cfg.Channels)
 	})
 }
+
+func TestLoadPigeonCatalogue(t *testing.T) {
+	t.Run("missing file yields empty catalogue", func(t *testing.T) {
+		catalogue, err := config.LoadPigeonCatalogue(filepath.Join(t.TempDir(), "nope.json"))
+		require.NoError(t, err)
+		assert.Empty(t, catalogue.Pigeons)
+		assert.Empty(t, catalogue.Channels)
+	})
+
+	t.Run("loads pigeons and channel overrides", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pigeons.json")
+		err := os.WriteFile(path, []byte(`{
+			"pigeons": [
+				{"type": "boss", "points": 100, "success": 25, "weight": 2, "eggsMin": 5, "eggsMax": 5, "crackMin": 1, "crackMax": 5}
+			],
+			"channels": {
+				"#Secret": [{"type": "golden", "points": 1000, "success": 5}]
+			}
+		}`), 0o600)
+		require.NoError(t, err)
+
+		catalogue, err := config.LoadPigeonCatalogue(path)
+		require.NoError(t, err)
+
+		require.Len(t, catalogue.Pigeons, 1)
+		two := 2
+		assert.Equal(t, config.PigeonConfig{
+			Type: "boss", Points: 100, Success: 25, Weight: &two,
+			EggsMin: 5, EggsMax: 5, CrackMin: 1, CrackMax: 5,
+		}, catalogue.Pigeons[0])
+
+		assert.Equal(t, "golden", catalogue.ForChannel("#secret")[0].Type)
+		assert.Equal(t, "boss", catalogue.ForChannel("#other")[0].Type)
+	})
+
+	t.Run("negative weights are rejected", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pigeons.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{
+			"pigeons": [{"type": "boss", "weight": 0}],
+			"channels": {"#secret": [{"type": "golden", "weight": -1}]}
+		}`), 0o600))
+
+		_, err := config.LoadPigeonCatalogue(path)
+		assert.ErrorContains(t, err, `pigeon "golden"`)
+	})
+
+	t.Run("invalid file returns error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pigeons.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"pigeons": [`), 0o600))
+
+		_, err := config.LoadPigeonCatalogue(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadMessageCatalogue(t *testing.T) {
+	t.Run("missing file yields no overrides", func(t *testing.T) {
+		catalogue, err := config.LoadMessageCatalogue(filepath.Join(t.TempDir(), "nope.json"))
+		require.NoError(t, err)
+		assert.Empty(t, catalogue.ForChannel("#pigeons"))
+	})
+
+	t.Run("channel overrides win", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "messages.json")
+		err := os.WriteFile(path, []byte(`{
+			"messages": {"bef": "no frens", "shoot.miss": "%s missed"},
+			"channels": {"#Pigeons": {"bef": "never frens"}}
+		}`), 0o600)
+		require.NoError(t, err)
+
+		catalogue, err := config.LoadMessageCatalogue(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"bef": "never frens", "shoot.miss": "%s missed"}, catalogue.ForChannel("#pigeons"))
+		assert.Equal(t, map[string]string{"bef": "no frens", "shoot.miss": "%s missed"}, catalogue.ForChannel("#other"))
+	})
+}
+
+func TestGameConfig_ScheduleFor(t *testing.T) {
+	cfg := config.GameConfig{
+		Schedule: config.ScheduleConfig{
+			MinInterval:    60,
+			MaxInterval:    120,
+			QuietHours:     "23:00-07:00",
+			ActiveMessages: 10,
+			ActiveFactor:   0.5,
+		},
+		ChannelSchedules: map[string]config.ScheduleConfig{
+			"#Night": {QuietHours: "08:00-20:00", Timezone: "Asia/Bangkok"},
+		},
+	}
+
+	night := cfg.ScheduleFor("#night")
+	assert.Equal(t, "08:00-20:00", night.QuietHours)
+	assert.Equal(t, "Asia/Bangkok", night.Timezone)
+	// unset fields are inherited
+	assert.Equal(t, 60, night.MinInterval)
+	assert.Equal(t, 120, night.MaxInterval)
+	assert.Equal(t, 0.5, night.ActiveFactor)
+
+	assert.Equal(t, cfg.Schedule, cfg.ScheduleFor("#lobby"))
+}
+
+func TestScheduleConfig_Defaults(t *testing.T) {
+	var s config.ScheduleConfig
+	require.NoError(t, configor.New(&configor.Config{}).Load(&s))
+
+	// quiet channels slow down unless configured otherwise
+	assert.Equal(t, 2.0, s.IdleFactor)
+	assert.Equal(t, 1800, s.IdleAfter)
+}
+
+func TestGameConfig_ScorePoolFor(t *testing.T) {
+	cfg := config.GameConfig{
+		ScorePools: map[string]string{
+			"#OffTopic": "#pigeons",
+			"#blank":    " ",
+		},
+	}
+
+	assert.Equal(t, "#pigeons", cfg.ScorePoolFor("#offtopic"))
+	assert.Equal(t, "#lobby", cfg.ScorePoolFor("#lobby"))
+	assert.Equal(t, "#blank", cfg.ScorePoolFor("#blank"), "an empty pool is ignored")
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := config.Config{
+		IRCConfig: config.IRCConfig{
+			Nick:             "pigeonbot",
+			NickservPassword: "nickserv",
+			SASLPassword:     "sasl",
+			TLSCertFile:      "client.crt",
+			TLSKeyFile:       "client.key",
+		},
+		DBConfig: config.DBConfig{User: "postgres", Password: "db"},
+		Networks: []config.NetworkConfig{{Network: "other", SASLPassword: "other-sasl"}},
+	}
+
+	redacted := cfg.Redacted()
+	assert.Equal(t, "[redacted]", redacted.IRCConfig.NickservPassword)
+	assert.Equal(t, "[redacted]", redacted.IRCConfig.SASLPassword)
+	assert.Equal(t, "[redacted]", redacted.IRCConfig.TLSKeyFile)
+	assert.Equal(t, "[redacted]", redacted.DBConfig.Password)
+	assert.Equal(t, "[redacted]", redacted.Networks[0].SASLPassword)
+	assert.Equal(t, "client.crt", redacted.IRCConfig.TLSCertFile)
+	assert.Equal(t, "postgres", redacted.DBConfig.User)
+	// the original is left alone
+	assert.Equal(t, "other-sasl", cfg.Networks[0].SASLPassword)
+	assert.Equal(t, "db", cfg.DBConfig.Password)
+}
+
+func TestConfig_ResolveNetworks(t *testing.T) {
+	base := config.Config{
+		IRCConfig: config.IRCConfig{
+			Host:             "irc.example.net",
+			Port:             6697,
+			SSL:              true,
+			Nick:             "Pigeon",
+			Network:          "Example",
+			Channels:         []string{"#pigeons"},
+			NickservPassword: "shared",
+		},
+		GameConfig: config.GameConfig{Interval: 10},
+	}
+
+	t.Run("single network without entries", func(t *testing.T) {
+		networks, err := base.ResolveNetworks()
+		require.NoError(t, err)
+		require.Len(t, networks, 1)
+		assert.Equal(t, base.IRCConfig, networks[0].IRC)
+		assert.Equal(t, base.GameConfig, networks[0].Game)
+	})
+
+	t.Run("entries override the shared settings", func(t *testing.T) {
+		noSSL := false
+		cfg := base
+		cfg.Networks = []config.NetworkConfig{
+			{Network: "Libera", Host: "irc.libera.chat", Channels: []string{"#a", "#b"}, Interval: 30},
+			{Network: "Ours", Host: "irc.ours.net", Port: 6667, SSL: &noSSL, Nick: "Piggy", FloodBurst: 10},
+		}
+
+		networks, err := cfg.ResolveNetworks()
+		require.NoError(t, err)
+		require.Len(t, networks, 2)
+
+		libera := networks[0]
+		assert.Equal(t, "irc.libera.chat", libera.IRC.Host)
+		assert.Equal(t, 6697, libera.IRC.Port)
+		assert.True(t, libera.IRC.SSL)
+		assert.Equal(t, "Pigeon", libera.IRC.Nick)
+		assert.Equal(t, "shared", libera.IRC.NickservPassword)
+		assert.Equal(t, []string{"#a", "#b"}, libera.IRC.Channels)
+		assert.Equal(t, 30, libera.Game.Interval)
+
+		ours := networks[1]
+		assert.Equal(t, 6667, ours.IRC.Port)
+		assert.False(t, ours.IRC.SSL)
+		assert.Equal(t, "Piggy", ours.IRC.Nick)
+		assert.Equal(t, 10, ours.IRC.FloodBurst)
+		assert.Equal(t, []string{"#pigeons"}, ours.IRC.Channels)
+		assert.Equal(t, 10, ours.Game.Interval)
+	})
+
+	t.Run("entries override the shared game settings", func(t *testing.T) {
+		off, keep := 0, 25
+		cfg := base
+		cfg.GameConfig.Schedule = config.ScheduleConfig{MinInterval: 60, MaxInterval: 120, IdleFactor: 2}
+		cfg.GameConfig.SeasonDays = 30
+		cfg.GameConfig.Persistence = "immediate"
+		cfg.Networks = []config.NetworkConfig{
+			{
+				Network:           "Quiet",
+				Host:              "irc.quiet.net",
+				Schedule:          config.ScheduleOverride{MaxInterval: 600, QuietHours: "23:00-07:00"},
+				SeasonDays:        &off,
+				SeasonKeepPercent: &keep,
+				Persistence:       "batch",
+				SaveDelay:         5000,
+				ReconnectMin:      10,
+				ReconnectMax:      60,
+			},
+			{Network: "Busy", Host: "irc.busy.net"},
+		}
+
+		networks, err := cfg.ResolveNetworks()
+		require.NoError(t, err)
+		require.Len(t, networks, 2)
+
+		quiet := networks[0]
+		assert.Equal(t, config.ScheduleConfig{MinInterval: 60, MaxInterval: 600, QuietHours: "23:00-07:00", IdleFactor: 2}, quiet.Game.Schedule)
+		assert.Equal(t, 0, quiet.Game.SeasonDays)
+		assert.Equal(t, 25, quiet.Game.SeasonKeepPercent)
+		assert.Equal(t, "batch", quiet.Game.Persistence)
+		assert.Equal(t, 5000, quiet.Game.SaveDelay)
+		assert.Equal(t, 10, quiet.IRC.ReconnectMin)
+		assert.Equal(t, 60, quiet.IRC.ReconnectMax)
+
+		busy := networks[1]
+		assert.Equal(t, cfg.GameConfig.Schedule, busy.Game.Schedule)
+		assert.Equal(t, 30, busy.Game.SeasonDays)
+		assert.Equal(t, "immediate", busy.Game.Persistence)
+	})
+
+	t.Run("schedules left out of an entry keep the shared values", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{
+			"gameconfig": {"schedule": {"idleFactor": 3}},
+			"networks": [{"network": "Quiet", "host": "irc.quiet.net", "schedule": {"minInterval": 60}}]
+		}`), 0o600))
+
+		var cfg config.Config
+		require.NoError(t, configor.New(&configor.Config{}).Load(&cfg, path))
+		networks, err := cfg.ResolveNetworks()
+		require.NoError(t, err)
+
+		assert.Equal(t, 60, networks[0].Game.Schedule.MinInterval)
+		assert.Equal(t, 3.0, networks[0].Game.Schedule.IdleFactor, "defaults must not override the shared schedule")
+	})
+
+	t.Run("network names must be unique", func(t *testing.T) {
+		cfg := base
+		cfg.Networks = []config.NetworkConfig{{Network: "A", Host: "a"}, {Network: "a", Host: "b"}}
+
+		_, err := cfg.ResolveNetworks()
+		assert.Error(t, err)
+	})
+
+	t.Run("entries need a network and host", func(t *testing.T) {
+		cfg := base
+		cfg.IRCConfig.Host = ""
+		cfg.Networks = []config.NetworkConfig{{Network: "A"}}
+
+		_, err := cfg.ResolveNetworks()
+		assert.Error(t, err)
+	})
+}
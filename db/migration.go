@@ -2,59 +2,74 @@ package db
 
 import (
 	"embed"
+	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
-	"sync"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
 	"github.com/MyelinBots/pigeonbot-go/internal/db"
 	"github.com/golang-migrate/migrate/v4"
+	dbdriver "github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/httpfs"
-	_ "github.com/golang-migrate/migrate/v4/source/httpfs"
 )
 
 var (
 	//go:embed migrations/*.sql
-	migrations   embed.FS
-	registerOnce sync.Once
+	migrations embed.FS
+	// the same schema for SQLite, numbered to match
+	//go:embed migrations/sqlite/*.sql
+	sqliteMigrations embed.FS
 )
 
-type driver struct {
-	httpfs.PartialDriver
+func getMigration() (*migrate.Migrate, error) {
+	cfg := config.LoadConfigOrPanic()
+	return newMigration(db.NewDatabase(cfg.DBConfig), cfg.DBConfig.DataBase)
 }
 
-func (d *driver) Open(rawURL string) (source.Driver, error) {
-	err := d.PartialDriver.Init(http.FS(migrations), "migrations")
+// newMigration migrates database with the migration set for its driver
+func newMigration(database *db.DB, name string) (*migrate.Migrate, error) {
+	sqldb, err := database.DB.DB()
 	if err != nil {
 		return nil, err
 	}
 
-	return d, nil
-}
-func getMigration() (*migrate.Migrate, error) {
-	cfg := config.LoadConfigOrPanic()
-	database := db.NewDatabase(cfg.DBConfig)
-	sqldb, err := database.DB.DB()
+	var (
+		files  fs.FS
+		dir    string
+		driver dbdriver.Driver
+	)
+	switch database.Driver {
+	case db.DriverSQLite:
+		files, dir = sqliteMigrations, "migrations/sqlite"
+		driver, err = newSQLiteDriver(sqldb)
+	case db.DriverPostgres:
+		files, dir = migrations, "migrations"
+		driver, err = postgres.WithInstance(sqldb, &postgres.Config{})
+	default:
+		return nil, fmt.Errorf("no migrations for database driver %q", database.Driver)
+	}
 	if err != nil {
 		return nil, err
 	}
-	dbdriver, err := postgres.WithInstance(sqldb, &postgres.Config{})
+
 	// log files in migrations folder
-	files, err := migrations.ReadDir("migrations")
+	entries, err := fs.ReadDir(files, dir)
 	if err != nil {
 		return nil, err
 	}
-	for _, file := range files {
-		println(file.Name())
+	for _, file := range entries {
+		if !file.IsDir() {
+			println(file.Name())
+		}
 	}
 
-	registerOnce.Do(func() {
-		source.Register("embed", &driver{})
-	})
-
-	return migrate.NewWithDatabaseInstance("embed://", cfg.DBConfig.DataBase, dbdriver)
+	source, err := httpfs.New(http.FS(files), dir)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("embed", source, name, driver)
 }
 
 func MigrateUp() error {
@@ -64,7 +79,22 @@ func MigrateUp() error {
 		return err
 	}
 
-	err = m.Up()
+	return up(m)
+}
+
+// MigrateDatabase brings database's schema up to date, e.g. a fresh SQLite
+// database in tests
+func MigrateDatabase(database *db.DB) error {
+	m, err := newMigration(database, "")
+	if err != nil {
+		return err
+	}
+
+	return up(m)
+}
+
+func up(m *migrate.Migrate) error {
+	err := m.Up()
 	if err != nil && err != migrate.ErrNoChange {
 		return err
 	}
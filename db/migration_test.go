@@ -1,6 +1,7 @@
 package db
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
@@ -10,6 +11,10 @@ import (
 )
 
 func TestMigrateUp(t *testing.T) {
+	if config.LoadConfigOrPanic().DBConfig.Driver == db.DriverSQLite {
+		t.Skip("checks the Postgres schema; TestMigrateDatabase_SQLite covers SQLite")
+	}
+
 	t.Run("runs migrations successfully", func(t *testing.T) {
 		// MigrateUp should not error (migrations already applied or no change)
 		err := MigrateUp()
@@ -60,6 +65,39 @@ func TestMigrateUp(t *testing.T) {
 	})
 }
 
+func TestMigrateDatabase_SQLite(t *testing.T) {
+	database := db.NewDatabase(config.DBConfig{
+		Driver: db.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "pigeon.db"),
+	})
+	defer database.Close()
+
+	tables := func() []string {
+		var names []string
+		err := database.DB.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name <> 'schema_migrations' ORDER BY name").
+			Scan(&names).Error
+		require.NoError(t, err)
+		return names
+	}
+
+	require.NoError(t, MigrateDatabase(database))
+	assert.Equal(t, []string{"channel_settings", "joined_channels", "player", "player_alias", "season", "season_result", "shot_event"}, tables())
+
+	// a second run has nothing to do
+	require.NoError(t, MigrateDatabase(database))
+
+	// versions match the Postgres set, so later migrations can come in pairs
+	m, err := newMigration(database, "")
+	require.NoError(t, err)
+	version, dirty, err := m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(13), version)
+	assert.False(t, dirty)
+
+	require.NoError(t, m.Down())
+	assert.Empty(t, tables())
+}
+
 func TestMigrateDown(t *testing.T) {
 	t.Run("migrate down then up works", func(t *testing.T) {
 		// Run down
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS channel_settings (
+    id VARCHAR(255) PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (network, channel, name)
+);
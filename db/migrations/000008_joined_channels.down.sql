@@ -0,0 +1 @@
+DROP TABLE IF EXISTS joined_channels;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS joined_channels (
+    id VARCHAR(255) PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (network, channel)
+);
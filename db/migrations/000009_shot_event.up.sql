@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS shot_event (
+    id VARCHAR(255) PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    spawn_id BIGINT NOT NULL DEFAULT 0,
+    pigeon_type TEXT NOT NULL DEFAULT '',
+    hit BOOLEAN NOT NULL DEFAULT FALSE,
+    points INT NOT NULL DEFAULT 0,
+    eggs_gained INT NOT NULL DEFAULT 0,
+    eggs_cracked INT NOT NULL DEFAULT 0,
+    rare_egg TEXT NOT NULL DEFAULT '',
+    shot_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS shot_event_channel_shot_at_idx ON shot_event (network, channel, shot_at);
+CREATE INDEX IF NOT EXISTS shot_event_name_shot_at_idx ON shot_event (network, name, shot_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS season_result;
+DROP TABLE IF EXISTS season;
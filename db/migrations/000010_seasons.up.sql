@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS season (
+    id VARCHAR(255) PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    number INT NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NULL,
+    UNIQUE (network, channel, number)
+);
+
+CREATE TABLE IF NOT EXISTS season_result (
+    id VARCHAR(255) PRIMARY KEY,
+    season_id VARCHAR(255) NOT NULL REFERENCES season (id) ON DELETE CASCADE,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    rank INT NOT NULL,
+    points INT NOT NULL DEFAULT 0,
+    count INT NOT NULL DEFAULT 0,
+    eggs INT NOT NULL DEFAULT 0,
+    rare_eggs INT NOT NULL DEFAULT 0,
+    title TEXT NOT NULL DEFAULT '',
+    badge TEXT NOT NULL DEFAULT '',
+    UNIQUE (season_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS season_result_player_idx ON season_result (network, channel, name);
@@ -0,0 +1,5 @@
+ALTER TABLE player
+DROP COLUMN IF EXISTS shots,
+DROP COLUMN IF EXISTS misses,
+DROP COLUMN IF EXISTS streak,
+DROP COLUMN IF EXISTS best_streak;
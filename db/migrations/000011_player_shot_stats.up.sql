@@ -0,0 +1,5 @@
+ALTER TABLE player
+ADD COLUMN IF NOT EXISTS shots INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS misses INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS streak INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS best_streak INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS player_alias (
+    id VARCHAR(255) PRIMARY KEY,
+    network TEXT NOT NULL,
+    nick TEXT NOT NULL,
+    account TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (network, nick)
+);
+
+CREATE INDEX IF NOT EXISTS player_alias_account_idx ON player_alias (network, account);
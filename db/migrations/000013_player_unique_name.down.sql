@@ -0,0 +1 @@
+DROP INDEX IF EXISTS player_network_channel_name_idx;
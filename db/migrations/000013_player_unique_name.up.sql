@@ -0,0 +1,48 @@
+-- Fold rows that differ only in the case of the name into the oldest id, as
+-- 000006 did, so the unique index can be built
+CREATE TEMP TABLE player_duplicates AS
+SELECT
+    MIN(id) as id,
+    LOWER(name) as name,
+    network,
+    channel,
+    SUM(points) as points,
+    SUM(count) as count,
+    SUM(eggs) as eggs,
+    SUM(rare_eggs) as rare_eggs,
+    SUM(shots) as shots,
+    SUM(misses) as misses,
+    MAX(best_streak) as best_streak
+FROM player
+WHERE deleted_at IS NULL
+GROUP BY LOWER(name), network, channel
+HAVING COUNT(*) > 1;
+
+DELETE FROM player p
+USING player_duplicates d
+WHERE p.deleted_at IS NULL
+  AND LOWER(p.name) = d.name
+  AND p.network = d.network
+  AND p.channel = d.channel
+  AND p.id <> d.id;
+
+UPDATE player p
+SET name = d.name,
+    points = d.points,
+    count = d.count,
+    eggs = d.eggs,
+    rare_eggs = d.rare_eggs,
+    shots = d.shots,
+    misses = d.misses,
+    streak = 0,
+    best_streak = d.best_streak,
+    updated_at = CURRENT_TIMESTAMP
+FROM player_duplicates d
+WHERE p.id = d.id;
+
+DROP TABLE player_duplicates;
+
+-- Scores are added with INSERT ... ON CONFLICT against this index
+CREATE UNIQUE INDEX IF NOT EXISTS player_network_channel_name_idx
+    ON player (network, channel, LOWER(name))
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS player_alias;
+DROP TABLE IF EXISTS season_result;
+DROP TABLE IF EXISTS season;
+DROP TABLE IF EXISTS shot_event;
+DROP TABLE IF EXISTS joined_channels;
+DROP TABLE IF EXISTS channel_settings;
+DROP TABLE IF EXISTS player;
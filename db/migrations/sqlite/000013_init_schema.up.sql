@@ -0,0 +1,103 @@
+-- SQLite databases start at the schema the Postgres migrations reach in
+-- 000013. Later migrations come in pairs, one in each set, with the same
+-- version.
+CREATE TABLE IF NOT EXISTS player (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    points INTEGER NOT NULL DEFAULT 0,
+    count INTEGER NOT NULL DEFAULT 0,
+    network TEXT NOT NULL,
+    channel TEXT,
+    eggs INTEGER NOT NULL DEFAULT 0,
+    rare_eggs INTEGER NOT NULL DEFAULT 0,
+    shots INTEGER NOT NULL DEFAULT 0,
+    misses INTEGER NOT NULL DEFAULT 0,
+    streak INTEGER NOT NULL DEFAULT 0,
+    best_streak INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+-- Scores are added with INSERT ... ON CONFLICT against this index
+CREATE UNIQUE INDEX IF NOT EXISTS player_network_channel_name_idx
+    ON player (network, channel, LOWER(name))
+    WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS channel_settings (
+    id TEXT PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (network, channel, name)
+);
+
+CREATE TABLE IF NOT EXISTS joined_channels (
+    id TEXT PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (network, channel)
+);
+
+CREATE TABLE IF NOT EXISTS shot_event (
+    id TEXT PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    spawn_id INTEGER NOT NULL DEFAULT 0,
+    pigeon_type TEXT NOT NULL DEFAULT '',
+    hit BOOLEAN NOT NULL DEFAULT FALSE,
+    points INTEGER NOT NULL DEFAULT 0,
+    eggs_gained INTEGER NOT NULL DEFAULT 0,
+    eggs_cracked INTEGER NOT NULL DEFAULT 0,
+    rare_egg TEXT NOT NULL DEFAULT '',
+    shot_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS shot_event_channel_shot_at_idx ON shot_event (network, channel, shot_at);
+CREATE INDEX IF NOT EXISTS shot_event_name_shot_at_idx ON shot_event (network, name, shot_at);
+
+CREATE TABLE IF NOT EXISTS season (
+    id TEXT PRIMARY KEY,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NULL,
+    UNIQUE (network, channel, number)
+);
+
+CREATE TABLE IF NOT EXISTS season_result (
+    id TEXT PRIMARY KEY,
+    season_id TEXT NOT NULL REFERENCES season (id) ON DELETE CASCADE,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    rank INTEGER NOT NULL,
+    points INTEGER NOT NULL DEFAULT 0,
+    count INTEGER NOT NULL DEFAULT 0,
+    eggs INTEGER NOT NULL DEFAULT 0,
+    rare_eggs INTEGER NOT NULL DEFAULT 0,
+    title TEXT NOT NULL DEFAULT '',
+    badge TEXT NOT NULL DEFAULT '',
+    UNIQUE (season_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS season_result_player_idx ON season_result (network, channel, name);
+
+CREATE TABLE IF NOT EXISTS player_alias (
+    id TEXT PRIMARY KEY,
+    network TEXT NOT NULL,
+    nick TEXT NOT NULL,
+    account TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (network, nick)
+);
+
+CREATE INDEX IF NOT EXISTS player_alias_account_idx ON player_alias (network, account);
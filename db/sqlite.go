@@ -0,0 +1,138 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/golang-migrate/migrate/v4/database"
+)
+
+// sqliteDriver runs migrations over the bot's own SQLite connection.
+// golang-migrate's sqlite driver links a second SQLite engine under the same
+// database/sql name as the one gorm uses, so the two cannot be built together.
+type sqliteDriver struct {
+	db     *sql.DB
+	locked atomic.Bool
+}
+
+// newSQLiteDriver returns a migration driver for db, creating the version table
+func newSQLiteDriver(db *sql.DB) (*sqliteDriver, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL, dirty BOOLEAN NOT NULL);
+CREATE UNIQUE INDEX IF NOT EXISTS schema_migrations_version_idx ON schema_migrations (version);`)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteDriver{db: db}, nil
+}
+
+func (d *sqliteDriver) Open(url string) (database.Driver, error) {
+	return nil, errors.New("sqlite migrations run on an open connection")
+}
+
+// Close leaves the connection open; it belongs to the caller
+func (d *sqliteDriver) Close() error {
+	return nil
+}
+
+func (d *sqliteDriver) Lock() error {
+	if !d.locked.CompareAndSwap(false, true) {
+		return database.ErrLocked
+	}
+	return nil
+}
+
+func (d *sqliteDriver) Unlock() error {
+	if !d.locked.CompareAndSwap(true, false) {
+		return database.ErrNotLocked
+	}
+	return nil
+}
+
+// Run applies one migration file in a transaction
+func (d *sqliteDriver) Run(migration io.Reader) error {
+	query, err := io.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(string(query)); err != nil {
+		_ = tx.Rollback()
+		return database.Error{OrigErr: err, Err: "migration failed", Query: query}
+	}
+	return tx.Commit()
+}
+
+func (d *sqliteDriver) SetVersion(version int, dirty bool) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec("DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	// a dirty NilVersion is kept so a failed first migration is noticed
+	if version >= 0 || (version == database.NilVersion && dirty) {
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", version, dirty); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (d *sqliteDriver) Version() (int, bool, error) {
+	var (
+		version int
+		dirty   bool
+	)
+	err := d.db.QueryRow("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.NilVersion, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
+
+// Drop removes every table, including the version table
+func (d *sqliteDriver) Drop() error {
+	rows, err := d.db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// the tables go in any order, so foreign keys must not stop a drop
+	if _, err := d.db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer func() { _, _ = d.db.Exec("PRAGMA foreign_keys = ON") }()
+
+	for _, table := range tables {
+		if _, err := d.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %q", table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/emersion/go-sasl"
+)
+
+// tlsConfig builds the TLS settings for the IRC connection
+func tlsConfig(cfg config.IRCConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSSkipVerify,
+	}
+	if tlsCfg.ServerName == "" {
+		tlsCfg.ServerName = cfg.Host
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSCAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		keyFile := cfg.TLSKeyFile
+		if keyFile == "" {
+			// a single PEM file may hold both the certificate and the key
+			keyFile = cfg.TLSCertFile
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+// saslClient returns the SASL client for the configured mechanism, or nil when SASL is off
+func saslClient(cfg config.IRCConfig) (sasl.Client, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.SASLMechanism)) {
+	case "":
+		return nil, nil
+	case "plain":
+		username, password := cfg.SASLUsername, cfg.SASLPassword
+		if username == "" {
+			username = cfg.Nick
+		}
+		if password == "" {
+			password = cfg.NickservPassword
+		}
+		if password == "" {
+			return nil, fmt.Errorf("SASL PLAIN needs a password")
+		}
+		return sasl.NewPlainClient("", username, password), nil
+	case "external":
+		if cfg.TLSCertFile == "" {
+			return nil, fmt.Errorf("SASL EXTERNAL needs a client certificate (TLS_CERT_FILE)")
+		}
+		if !cfg.SSL {
+			return nil, fmt.Errorf("SASL EXTERNAL needs SSL")
+		}
+		return sasl.NewExternalClient(cfg.SASLUsername), nil
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %q", cfg.SASLMechanism)
+	}
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert writes a self-signed certificate and its key as PEM files
+func writeCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pigeonbot"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Run("verifies by default against the host", func(t *testing.T) {
+		tlsCfg, err := tlsConfig(config.IRCConfig{Host: "irc.example.net"})
+		require.NoError(t, err)
+		assert.False(t, tlsCfg.InsecureSkipVerify)
+		assert.Equal(t, "irc.example.net", tlsCfg.ServerName)
+		assert.Nil(t, tlsCfg.RootCAs)
+	})
+
+	t.Run("server name and skip verify are opt-in", func(t *testing.T) {
+		tlsCfg, err := tlsConfig(config.IRCConfig{Host: "10.0.0.1", TLSServerName: "irc.example.net", TLSSkipVerify: true})
+		require.NoError(t, err)
+		assert.True(t, tlsCfg.InsecureSkipVerify)
+		assert.Equal(t, "irc.example.net", tlsCfg.ServerName)
+	})
+
+	t.Run("CA bundle and client certificate", func(t *testing.T) {
+		certFile, keyFile := writeCert(t)
+
+		tlsCfg, err := tlsConfig(config.IRCConfig{TLSCAFile: certFile, TLSCertFile: certFile, TLSKeyFile: keyFile})
+		require.NoError(t, err)
+		assert.NotNil(t, tlsCfg.RootCAs)
+		assert.Len(t, tlsCfg.Certificates, 1)
+	})
+
+	t.Run("bad files are reported", func(t *testing.T) {
+		_, err := tlsConfig(config.IRCConfig{TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")})
+		assert.Error(t, err)
+
+		empty := filepath.Join(t.TempDir(), "empty.pem")
+		require.NoError(t, os.WriteFile(empty, []byte("not a cert"), 0o600))
+		_, err = tlsConfig(config.IRCConfig{TLSCAFile: empty})
+		assert.Error(t, err)
+
+		_, err = tlsConfig(config.IRCConfig{TLSCertFile: empty})
+		assert.Error(t, err)
+	})
+}
+
+func TestSASLClient(t *testing.T) {
+	t.Run("off by default", func(t *testing.T) {
+		client, err := saslClient(config.IRCConfig{})
+		require.NoError(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("plain falls back to nick and nickserv password", func(t *testing.T) {
+		client, err := saslClient(config.IRCConfig{SASLMechanism: "PLAIN", Nick: "pigeon", NickservPassword: "secret"})
+		require.NoError(t, err)
+
+		mech, ir, err := client.Start()
+		require.NoError(t, err)
+		assert.Equal(t, "PLAIN", mech)
+		assert.Equal(t, "\x00pigeon\x00secret", string(ir))
+	})
+
+	t.Run("plain needs a password", func(t *testing.T) {
+		_, err := saslClient(config.IRCConfig{SASLMechanism: "plain", Nick: "pigeon"})
+		assert.Error(t, err)
+	})
+
+	t.Run("external needs a certificate over TLS", func(t *testing.T) {
+		_, err := saslClient(config.IRCConfig{SASLMechanism: "external", SSL: true})
+		assert.Error(t, err)
+		_, err = saslClient(config.IRCConfig{SASLMechanism: "external", TLSCertFile: "cert.pem"})
+		assert.Error(t, err)
+
+		client, err := saslClient(config.IRCConfig{SASLMechanism: "external", SSL: true, TLSCertFile: "cert.pem"})
+		require.NoError(t, err)
+		mech, _, err := client.Start()
+		require.NoError(t, err)
+		assert.Equal(t, "EXTERNAL", mech)
+	})
+
+	t.Run("unknown mechanism", func(t *testing.T) {
+		_, err := saslClient(config.IRCConfig{SASLMechanism: "scram-sha-256"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	rand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// backoff hands out reconnect delays that double after each failure up to
+// max. Each delay is jittered into [d/2, d] so restarts don't stampede.
+type backoff struct {
+	mu      sync.Mutex
+	min     time.Duration
+	max     time.Duration
+	attempt int
+	jitter  func(n int64) int64 // returns a value in [0, n)
+}
+
+func newBackoff(min, max time.Duration) *backoff {
+	if min <= 0 {
+		min = time.Second
+	}
+	if max < min {
+		max = min
+	}
+	return &backoff{min: min, max: max, jitter: rand.Int64N}
+}
+
+// Next returns the delay before the next attempt
+func (b *backoff) Next() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	d := b.min
+	for i := 0; i < b.attempt && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	b.attempt++
+
+	half := d / 2
+	return half + time.Duration(b.jitter(int64(d-half)+1))
+}
+
+// Reset starts over from the minimum delay after a successful connect
+func (b *backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.attempt = 0
+}
@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	t.Run("doubles up to max without jitter", func(t *testing.T) {
+		b := newBackoff(time.Second, 10*time.Second)
+		b.jitter = func(n int64) int64 { return n - 1 }
+
+		var got []time.Duration
+		for i := 0; i < 6; i++ {
+			got = append(got, b.Next())
+		}
+		assert.Equal(t, []time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second,
+		}, got)
+	})
+
+	t.Run("jitter keeps at least half the delay", func(t *testing.T) {
+		b := newBackoff(4*time.Second, time.Minute)
+		b.jitter = func(int64) int64 { return 0 }
+
+		assert.Equal(t, 2*time.Second, b.Next())
+		assert.Equal(t, 4*time.Second, b.Next())
+	})
+
+	t.Run("reset starts over", func(t *testing.T) {
+		b := newBackoff(time.Second, time.Minute)
+		b.jitter = func(n int64) int64 { return n - 1 }
+
+		b.Next()
+		b.Next()
+		b.Reset()
+		assert.Equal(t, time.Second, b.Next())
+	})
+
+	t.Run("bad bounds are fixed up", func(t *testing.T) {
+		b := newBackoff(0, 0)
+		assert.Equal(t, time.Second, b.min)
+		assert.Equal(t, time.Second, b.max)
+	})
+}
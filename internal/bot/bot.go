@@ -2,16 +2,28 @@ package bot
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
 	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/channel"
 	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
 	"github.com/MyelinBots/pigeonbot-go/internal/healthcheck"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/commands"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/game"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/permissions"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/shotlog"
 	irc "github.com/fluffle/goirc/client"
 )
 
@@ -20,167 +32,275 @@ type Identified struct {
 	identified bool
 }
 
+// Reset forgets that we identified, e.g. after losing the connection
+func (i *Identified) Reset() {
+	i.Lock()
+	defer i.Unlock()
+
+	i.identified = false
+}
+
+// gameKey identifies a game by network and channel, case-insensitively
+type gameKey struct {
+	network string
+	channel string
+}
+
+func keyFor(network, channel string) gameKey {
+	return gameKey{network: strings.ToLower(network), channel: strings.ToLower(channel)}
+}
+
 type GameInstances struct {
 	sync.Mutex
-	GameStarted      map[string]bool
-	games            map[string]*game.Game
-	commandInstances map[string]commands.CommandController
+	games            map[gameKey]*game.Game
+	commandInstances map[gameKey]commands.CommandController
 }
 
-func StartBot() error {
-	cfg := config.LoadConfigOrPanic()
+func newGameInstances() *GameInstances {
+	return &GameInstances{
+		games:            make(map[gameKey]*game.Game),
+		commandInstances: make(map[gameKey]commands.CommandController),
+	}
+}
 
-	identified := &Identified{identified: false}
+// Add registers the game and its commands for a channel
+func (gi *GameInstances) Add(network, channel string, g *game.Game, cmds commands.CommandController) {
+	gi.Lock()
+	defer gi.Unlock()
 
-	fmt.Printf("Starting bot with config: %+v\n", cfg)
-	database := db.NewDatabase(cfg.DBConfig)
-	playerRepo := player.NewPlayerRepository(database)
+	gi.games[keyFor(network, channel)] = g
+	gi.commandInstances[keyFor(network, channel)] = cmds
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// Remove forgets the game for a channel and returns it, or nil if there was none
+func (gi *GameInstances) Remove(network, channel string) *game.Game {
+	gi.Lock()
+	defer gi.Unlock()
 
-	healthcheck.StartHealthcheck(ctx, cfg.AppConfig)
+	k := keyFor(network, channel)
+	g := gi.games[k]
+	delete(gi.games, k)
+	delete(gi.commandInstances, k)
+	return g
+}
+
+// Get returns the game and commands for a channel
+func (gi *GameInstances) Get(network, channel string) (*game.Game, commands.CommandController, bool) {
+	gi.Lock()
+	defer gi.Unlock()
+
+	g, ok := gi.games[keyFor(network, channel)]
+	return g, gi.commandInstances[keyFor(network, channel)], ok
+}
 
-	ircConfig := irc.NewConfig(cfg.IRCConfig.Nick)
-	ircConfig.Me.Name = cfg.IRCConfig.RealName
-	ircConfig.Me.Ident = cfg.IRCConfig.Nick
-	ircConfig.SSL = cfg.IRCConfig.SSL
-	ircConfig.SSLConfig = &tls.Config{InsecureSkipVerify: true}
-	ircConfig.Server = fmt.Sprintf("%s:%d", cfg.IRCConfig.Host, cfg.IRCConfig.Port)
+// GameStarted reports whether the game loop for channel is running
+func (gi *GameInstances) GameStarted(network, channel string) bool {
+	g, _, ok := gi.Get(network, channel)
+	return ok && g.Running()
+}
 
-	c := irc.Client(ircConfig)
+// Games returns the games on network, or on every network when network is empty
+func (gi *GameInstances) Games(network string) []*game.Game {
+	gi.Lock()
+	defer gi.Unlock()
 
-	// for each channel make a new game instance
-	gameInstances := &GameInstances{
-		games:            make(map[string]*game.Game),
-		commandInstances: make(map[string]commands.CommandController),
-		GameStarted:      make(map[string]bool),
+	games := make([]*game.Game, 0, len(gi.games))
+	for k, g := range gi.games {
+		if network == "" || k.network == strings.ToLower(network) {
+			games = append(games, g)
+		}
 	}
+	return games
+}
 
-	for _, channel := range cfg.IRCConfig.Channels {
-		gameInstances.Lock()
+// StopNetwork stops the game loops on network; they are launched again when their channel is rejoined
+func (gi *GameInstances) StopNetwork(network string) {
+	for _, g := range gi.Games(network) {
+		g.Stop()
+	}
+}
 
-		// ✅ IMPORTANT: pass wrapper (Privmsg/Notice/Raw) not raw conn
-		gameInstance := game.NewGame(cfg.GameConfig, IRCWrapper{c}, playerRepo, cfg.IRCConfig.Network, channel)
-		commandInstance := commands.NewCommandController(gameInstance)
+// Shutdown stops every game and saves its players
+func (gi *GameInstances) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, g := range gi.Games("") {
+		if err := g.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("saving %s %s: %w", g.Network(), g.Channel(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
 
-		commandInstance.AddCommand("!shoot", gameInstance.HandleShoot)
-		commandInstance.AddCommand("!score", gameInstance.HandlePoints)
-		commandInstance.AddCommand("!help", gameInstance.HandleHelp)
-		commandInstance.AddCommand("!pigeons", gameInstance.HandleCount)
-		commandInstance.AddCommand("!bef", gameInstance.HandleBef)
-		commandInstance.AddCommand("!level", gameInstance.HandleLevel)
-		commandInstance.AddCommand("!top5", gameInstance.HandleTop5)
-		commandInstance.AddCommand("!top10", gameInstance.HandleTop10)
-		commandInstance.AddCommand("!eggs", gameInstance.HandleEggs)
+func StartBot() error {
+	cfg := config.LoadConfigOrPanic()
 
-		// ✅ ping command (CTCP PING -> NOTICE reply -> Pong)
-		commandInstance.AddCommand("!ping", gameInstance.HandlePingCommand)
+	fmt.Printf("Starting bot with config: %+v\n", cfg.Redacted())
 
-		gameInstances.games[channel] = gameInstance
-		gameInstances.commandInstances[channel] = commandInstance
-		gameInstances.GameStarted[channel] = false
+	networks, err := cfg.ResolveNetworks()
+	if err != nil {
+		return err
+	}
 
-		gameInstances.Unlock()
+	database := db.NewDatabase(cfg.DBConfig)
+	repos := repositories{
+		players:  player.NewPlayerRepository(database),
+		settings: settings.NewSettingsRepository(database),
+		channels: channel.NewChannelRepository(database),
+		shots:    shot.NewShotEventRepository(database),
+		seasons:  season.NewSeasonRepository(database),
+		aliases:  alias.NewAliasRepository(database),
 	}
+	// shots are written in batches in the background
+	shotLog := shotlog.NewWriter(repos.shots, shotlog.DefaultBatchSize, shotlog.DefaultFlushInterval)
 
-	c.HandleFunc(irc.CONNECTED, func(conn *irc.Conn, _ *irc.Line) {
-		fmt.Printf("Connected to %s\n", cfg.IRCConfig.Host)
-		for _, channel := range cfg.IRCConfig.Channels {
-			fmt.Printf("Joining channel %s\n", channel)
-			conn.Join(channel)
-		}
-	})
+	// SIGINT/SIGTERM cancel ctx, which stops the game loops and the healthcheck
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-	// Also join on MOTD end / no MOTD
-	c.HandleFunc("422", func(conn *irc.Conn, _ *irc.Line) {
-		for _, channel := range cfg.IRCConfig.Channels {
-			conn.Join(channel)
-		}
-	})
-	c.HandleFunc("376", func(conn *irc.Conn, _ *irc.Line) {
-		for _, channel := range cfg.IRCConfig.Channels {
-			conn.Join(channel)
-		}
-	})
+	healthcheck.StartHealthcheck(ctx, cfg.AppConfig)
 
-	c.HandleFunc(irc.JOIN, func(conn *irc.Conn, line *irc.Line) {
-		channel := line.Args[0]
-		fmt.Printf("Joined %s\n", channel)
+	gameInstances := newGameInstances()
 
-		handleNickserv(cfg.IRCConfig, identified, conn)
+	bots := make([]*networkBot, 0, len(networks))
+	for _, network := range networks {
+		b, err := newNetworkBot(ctx, network, cfg.PermissionsConfig, gameInstances, repos, shotLog)
+		if err != nil {
+			database.Close()
+			return fmt.Errorf("network %s: %w", network.IRC.Network, err)
+		}
+		bots = append(bots, b)
+	}
 
-		gameInstances.Lock()
-		defer gameInstances.Unlock()
+	// one supervised connection per network; they all return once ctx is done
+	var wg sync.WaitGroup
+	for _, b := range bots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			supervise(ctx, b.conn, b.disconnected, b.retry, b.onDisconnect)
+		}()
+	}
+	wg.Wait()
 
-		if g, ok := gameInstances.games[channel]; ok {
-			if !gameInstances.GameStarted[channel] {
-				go g.Start(ctx)
-				gameInstances.GameStarted[channel] = true
-			}
-		}
-	})
+	fmt.Println("Shutting down")
+	// a second signal kills the process straight away
+	cancel()
 
-	c.HandleFunc(irc.PRIVMSG, func(_ *irc.Conn, line *irc.Line) {
-		channel := line.Args[0]
-		msg := line.Args[1]
+	timeout := time.Duration(cfg.AppConfig.ShutdownTimeout) * time.Second
+	return shutdown(gameInstances, bots, shotLog, database, timeout)
+}
 
-		// manual start
-		if msg == "!start" {
-			gameInstances.Lock()
-			g, ok := gameInstances.games[channel]
-			if !ok {
-				gameInstances.Unlock()
-				return
-			}
-			if gameInstances.GameStarted[channel] {
-				gameInstances.Unlock()
-				fmt.Printf("Game already started for %s\n", channel)
+type connector interface {
+	ConnectContext(ctx context.Context) error
+}
+
+// supervise keeps the connection up until ctx is done, reconnecting with
+// backoff. onDisconnect runs after every lost connection.
+func supervise(ctx context.Context, c connector, disconnected <-chan struct{}, retry *backoff, onDisconnect func()) {
+	for {
+		if err := c.ConnectContext(ctx); err != nil {
+			fmt.Printf("Connection error: %s\n", err.Error())
+		} else {
+			select {
+			case <-ctx.Done():
 				return
+			case <-disconnected:
+				fmt.Println("Disconnected")
+				onDisconnect()
 			}
-			gameInstances.GameStarted[channel] = true
-			gameInstances.Unlock()
+		}
 
-			fmt.Printf("Starting gameInstance for %s\n", channel)
-			go g.Start(ctx)
+		delay := retry.Next()
+		fmt.Printf("Reconnecting in %s\n", delay.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
 			return
+		case <-time.After(delay):
 		}
+	}
+}
+
+// shutdown saves every game and the shot log, sends QUIT on every network and
+// closes the database. Whatever is still pending when timeout passes is abandoned.
+func shutdown(gameInstances *GameInstances, bots []*networkBot, shotLog *shotlog.Writer, database *db.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-		gameInstances.Lock()
-		commandInstance := gameInstances.commandInstances[channel]
-		gameInstances.Unlock()
+	err := gameInstances.Shutdown(ctx)
+	if err != nil {
+		fmt.Printf("Error saving players: %s\n", err.Error())
+	}
+	if closeErr := shotLog.Close(ctx); closeErr != nil {
+		fmt.Printf("Error saving shot log: %s\n", closeErr.Error())
+		err = errors.Join(err, closeErr)
+	}
 
-		ctxWithNick := context.WithValue(ctx, "nick", line.Nick)
-		if err := commandInstance.HandleCommand(ctxWithNick, line); err != nil {
-			fmt.Printf("Error handling command: %s\n", err.Error())
+	var wg sync.WaitGroup
+	for _, b := range bots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.quit(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if closeErr := database.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+	return err
+}
+
+// trackMode applies a channel MODE line to checker. User modes such as
+// "MODE pigeonbot +i" are not about a channel and are ignored.
+func trackMode(checker *permissions.Checker, line *irc.Line) {
+	if len(line.Args) < 2 || !isChannel(line.Args[0]) {
+		return
+	}
+	checker.Mode(line.Args[0], line.Args[1], line.Args[2:])
+}
+
+// trackPermissions keeps the checker's view of channel ops and voices up to date
+func trackPermissions(c *irc.Conn, checker *permissions.Checker) {
+	// RPL_NAMREPLY: <me> <type> <channel> :<names>
+	c.HandleFunc("353", func(_ *irc.Conn, line *irc.Line) {
+		if len(line.Args) < 4 {
 			return
 		}
+		checker.Names(line.Args[2], line.Args[3])
 	})
-
-	// CTCPREPLY handler - goirc parses CTCP and dispatches to this event
-	c.HandleFunc(irc.CTCPREPLY, func(_ *irc.Conn, line *irc.Line) {
+	c.HandleFunc(irc.MODE, func(_ *irc.Conn, line *irc.Line) {
+		trackMode(checker, line)
+	})
+	c.HandleFunc(irc.PART, func(conn *irc.Conn, line *irc.Line) {
 		if len(line.Args) < 1 {
 			return
 		}
-
-		gameInstances.Lock()
-		defer gameInstances.Unlock()
-
-		for _, g := range gameInstances.games {
-			g.HandleCTCPReply(line.Nick, line.Args)
+		if line.Nick == conn.Me().Nick {
+			checker.Forget(line.Args[0])
+			return
 		}
+		checker.Part(line.Args[0], line.Nick)
+	})
+	c.HandleFunc(irc.KICK, func(conn *irc.Conn, line *irc.Line) {
+		if len(line.Args) < 2 {
+			return
+		}
+		if line.Args[1] == conn.Me().Nick {
+			checker.Forget(line.Args[0])
+			return
+		}
+		checker.Part(line.Args[0], line.Args[1])
+	})
+	c.HandleFunc(irc.QUIT, func(_ *irc.Conn, line *irc.Line) {
+		checker.Quit(line.Nick)
+	})
+	c.HandleFunc(irc.NICK, func(_ *irc.Conn, line *irc.Line) {
+		if len(line.Args) < 1 {
+			return
+		}
+		checker.Rename(line.Nick, line.Args[0])
 	})
-
-	quit := make(chan bool, 1)
-	c.HandleFunc(irc.DISCONNECTED, func(_ *irc.Conn, _ *irc.Line) { quit <- true })
-
-	if err := c.Connect(); err != nil {
-		fmt.Printf("Connection error: %s\n", err.Error())
-		return err
-	}
-
-	<-quit
-	return nil
 }
 
 func handleNickserv(cfg config.IRCConfig, identified *Identified, c *irc.Conn) {
@@ -194,13 +314,29 @@ func handleNickserv(cfg config.IRCConfig, identified *Identified, c *irc.Conn) {
 	}
 }
 
+// IRCWrapper drops messages sent without a connection: goirc queues writes
+// and blocks once the queue is full while disconnected
 type IRCWrapper struct {
 	*irc.Conn
 }
 
-func (w IRCWrapper) Privmsg(channel, message string) { w.Conn.Privmsg(channel, message) }
+func (w IRCWrapper) Privmsg(channel, message string) {
+	if w.Conn.Connected() {
+		w.Conn.Privmsg(channel, message)
+	}
+}
 func (w IRCWrapper) Kick(channel, nick, reason string) {
-	w.Conn.Kick(channel, nick, reason)
+	if w.Conn.Connected() {
+		w.Conn.Kick(channel, nick, reason)
+	}
+}
+func (w IRCWrapper) Notice(target, message string) {
+	if w.Conn.Connected() {
+		w.Conn.Notice(target, message)
+	}
+}
+func (w IRCWrapper) Raw(message string) {
+	if w.Conn.Connected() {
+		w.Conn.Raw(message)
+	}
 }
-func (w IRCWrapper) Notice(target, message string) { w.Conn.Notice(target, message) }
-func (w IRCWrapper) Raw(message string)            { w.Conn.Raw(message) }
@@ -1,12 +1,30 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias"
+	aliasMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/channel"
+	playerRepo "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	playerMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/commands"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/game"
+	gameMocks "github.com/MyelinBots/pigeonbot-go/internal/services/game/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/identity"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/permissions"
+	irc "github.com/fluffle/goirc/client"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestIdentified(t *testing.T) {
@@ -31,29 +49,79 @@ func TestIdentified(t *testing.T) {
 
 func TestGameInstances(t *testing.T) {
 	t.Run("initializes empty maps", func(t *testing.T) {
-		gi := &GameInstances{
-			games:            make(map[string]*game.Game),
-			commandInstances: make(map[string]commands.CommandController),
-			GameStarted:      make(map[string]bool),
-		}
+		gi := newGameInstances()
 
 		assert.NotNil(t, gi.games)
 		assert.NotNil(t, gi.commandInstances)
-		assert.NotNil(t, gi.GameStarted)
 		assert.Empty(t, gi.games)
 	})
 
-	t.Run("can track game started state", func(t *testing.T) {
-		gi := &GameInstances{
-			GameStarted: make(map[string]bool),
-		}
+	t.Run("reports the real game state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := playerMocks.NewMockPlayerRepository(ctrl)
+		repo.EXPECT().GetAllPlayers(gomock.Any(), "net", "#test").Return(nil, nil).AnyTimes()
+		ircClient := gameMocks.NewMockIRCClient(ctrl)
+		ircClient.EXPECT().Privmsg(gomock.Any(), gomock.Any()).AnyTimes()
+
+		g := game.NewGame(config.GameConfig{Interval: 3600}, ircClient, repo, "net", "#test")
+		gi := newGameInstances()
+		gi.Add("net", "#test", g, commands.NewCommandController(g))
+
+		assert.False(t, gi.GameStarted("net", "#test"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		assert.True(t, g.Launch(ctx))
+		assert.True(t, gi.GameStarted("net", "#test"))
+		assert.True(t, gi.GameStarted("NET", "#Test"), "lookups ignore case")
+		assert.False(t, gi.GameStarted("net", "#other"))
+		assert.False(t, gi.GameStarted("othernet", "#test"))
+
+		gi.StopNetwork("othernet")
+		assert.True(t, gi.GameStarted("net", "#test"))
+		gi.StopNetwork("net")
+		assert.False(t, gi.GameStarted("net", "#test"))
+	})
+
+	t.Run("games are keyed by network and channel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		gi.Lock()
-		gi.GameStarted["#test"] = true
-		gi.Unlock()
+		repo := playerMocks.NewMockPlayerRepository(ctrl)
+		ircClient := gameMocks.NewMockIRCClient(ctrl)
 
-		assert.True(t, gi.GameStarted["#test"])
-		assert.False(t, gi.GameStarted["#other"])
+		libera := game.NewGame(config.GameConfig{}, ircClient, repo, "libera", "#pigeons")
+		ours := game.NewGame(config.GameConfig{}, ircClient, repo, "ours", "#pigeons")
+		gi := newGameInstances()
+		gi.Add("libera", "#pigeons", libera, commands.NewCommandController(libera))
+		gi.Add("ours", "#pigeons", ours, commands.NewCommandController(ours))
+
+		got, _, ok := gi.Get("ours", "#pigeons")
+		assert.True(t, ok)
+		assert.Same(t, ours, got)
+		assert.Equal(t, []*game.Game{libera}, gi.Games("libera"))
+		assert.Len(t, gi.Games(""), 2)
+	})
+
+	t.Run("removes a channel's game and commands", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := playerMocks.NewMockPlayerRepository(ctrl)
+		ircClient := gameMocks.NewMockIRCClient(ctrl)
+
+		g := game.NewGame(config.GameConfig{}, ircClient, repo, "net", "#pigeons")
+		gi := newGameInstances()
+		gi.Add("net", "#pigeons", g, commands.NewCommandController(g))
+
+		assert.Same(t, g, gi.Remove("NET", "#Pigeons"))
+		_, cmds, ok := gi.Get("net", "#pigeons")
+		assert.False(t, ok)
+		assert.Nil(t, cmds)
+		assert.Empty(t, gi.commandInstances)
+		assert.Nil(t, gi.Remove("net", "#pigeons"), "removing twice is a no-op")
 	})
 }
 
@@ -87,3 +155,198 @@ func TestHandleNickserv(t *testing.T) {
 		handleNickserv(cfg, id, nil)
 	})
 }
+
+func TestGameInstances_Shutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := playerMocks.NewMockPlayerRepository(ctrl)
+	repo.EXPECT().GetAllPlayers(gomock.Any(), "net", "#test").
+		Return([]*playerRepo.Player{{Name: "alice", Points: 30, Count: 3}}, nil).
+		Times(1)
+	// the first spawn happens after the players are loaded
+	spawned := make(chan struct{}, 1)
+	ircClient := gameMocks.NewMockIRCClient(ctrl)
+	ircClient.EXPECT().Privmsg(gomock.Any(), gomock.Any()).
+		Do(func(string, string) {
+			select {
+			case spawned <- struct{}{}:
+			default:
+			}
+		}).
+		AnyTimes()
+
+	g := game.NewGame(config.GameConfig{Interval: 3600}, ircClient, repo, "net", "#test")
+	gi := newGameInstances()
+	gi.Add("net", "#test", g, commands.NewCommandController(g))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.True(t, g.Launch(ctx))
+	select {
+	case <-spawned:
+	case <-time.After(time.Second):
+		t.Fatal("game did not spawn a pigeon")
+	}
+
+	// scores are stored as they change, so the mock fails on any write here
+	require.NoError(t, gi.Shutdown(context.Background()))
+	assert.False(t, gi.GameStarted("net", "#test"))
+}
+
+func TestIdentified_Reset(t *testing.T) {
+	id := &Identified{identified: true}
+	id.Reset()
+	assert.False(t, id.identified)
+}
+
+// fakeConnector fails the first attempts, then "connects" and reports each connection
+type fakeConnector struct {
+	failures  int
+	attempts  int
+	connected chan struct{}
+}
+
+func (f *fakeConnector) ConnectContext(ctx context.Context) error {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return errors.New("connection refused")
+	}
+	f.connected <- struct{}{}
+	return nil
+}
+
+func TestSupervise(t *testing.T) {
+	conn := &fakeConnector{failures: 2, connected: make(chan struct{}, 10)}
+	disconnected := make(chan struct{}, 1)
+	retry := newBackoff(time.Millisecond, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disconnects := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		supervise(ctx, conn, disconnected, retry, func() { disconnects <- struct{}{} })
+		close(done)
+	}()
+
+	// two failures are retried, then the connection is up
+	<-conn.connected
+	assert.Equal(t, 3, conn.attempts)
+
+	// a dropped connection runs the hook and reconnects
+	disconnected <- struct{}{}
+	<-disconnects
+	<-conn.connected
+	assert.Equal(t, 4, conn.attempts)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("supervise did not return after cancel")
+	}
+}
+
+func TestIsChannel(t *testing.T) {
+	assert.True(t, isChannel("#pigeons"))
+	assert.True(t, isChannel("&local"))
+	assert.False(t, isChannel("#"))
+	assert.False(t, isChannel("pigeons"))
+	assert.False(t, isChannel("#a,#b"))
+	assert.False(t, isChannel(""))
+}
+
+func TestNetworkBot_SetAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aliases := aliasMocks.NewMockAliasRepository(ctrl)
+	b := &networkBot{
+		ctx:        context.Background(),
+		cfg:        config.IRCConfig{Network: "testnet"},
+		identities: identity.NewTracker(),
+		repos:      repositories{aliases: aliases},
+	}
+
+	// each nick an account is seen with is stored once
+	aliases.EXPECT().SetAlias(gomock.Any(), "testnet", "bob", "robert").Return(nil)
+	aliases.EXPECT().SetAlias(gomock.Any(), "testnet", "bob_away", "robert").Return(errors.New("db down"))
+	b.setAccount("bob", "Robert")
+	b.setAccount("bob", "robert")
+	b.identities.Rename("bob", "bob_away")
+	b.rememberAlias("bob_away")
+
+	// logged out users are not stored
+	b.setAccount("bob_away", "*")
+	b.setAccount("alice", "*")
+	b.rememberAlias("alice")
+}
+
+func TestNetworkBot_JoinSurvivesRestart(t *testing.T) {
+	database := dbtest.SQLite(t)
+	repos := repositories{
+		players:  playerRepo.NewPlayerRepository(database),
+		settings: settings.NewSettingsRepository(database),
+		channels: channel.NewChannelRepository(database),
+		shots:    shot.NewShotEventRepository(database),
+		seasons:  season.NewSeasonRepository(database),
+		aliases:  alias.NewAliasRepository(database),
+	}
+	network := config.Network{IRC: config.IRCConfig{Network: "testnet", Nick: "pigeonbot"}}
+	ctx := context.Background()
+
+	b, err := newNetworkBot(ctx, network, config.PermissionsConfig{}, newGameInstances(), repos, nil)
+	require.NoError(t, err)
+	require.NoError(t, b.joinChannel(ctx, "#Pigeons"))
+	joined, _, ok := b.gameInstances.Get("testnet", "#pigeons")
+	require.True(t, ok)
+	require.NoError(t, joined.HandleSet(ctx, "locale", "th"))
+
+	// after a restart the game is keyed the same, so its data is still there
+	restarted, err := newNetworkBot(ctx, network, config.PermissionsConfig{}, newGameInstances(), repos, nil)
+	require.NoError(t, err)
+	rebuilt, _, ok := restarted.gameInstances.Get("testnet", "#Pigeons")
+	require.True(t, ok)
+	assert.Equal(t, joined.Channel(), rebuilt.Channel())
+	assert.Equal(t, "th", rebuilt.Settings().Locale)
+}
+
+func TestNetworkBot_RejoinKeepsStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := playerMocks.NewMockPlayerRepository(ctrl)
+	repo.EXPECT().GetAllPlayers(gomock.Any(), "net", "#test").Return(nil, nil).AnyTimes()
+	ircClient := gameMocks.NewMockIRCClient(ctrl)
+	ircClient.EXPECT().Privmsg(gomock.Any(), gomock.Any()).AnyTimes()
+
+	g := game.NewGame(config.GameConfig{Interval: 3600}, ircClient, repo, "net", "#test")
+	b := &networkBot{
+		ctx:           context.Background(),
+		cfg:           config.IRCConfig{Network: "net"},
+		gameInstances: newGameInstances(),
+	}
+	b.gameInstances.Add("net", "#test", g, commands.NewCommandController(g))
+
+	// a reconnect or rejoin leaves a game stopped with !stop alone
+	require.NoError(t, g.HandleStop(context.Background()))
+	b.rejoinGame("#test")
+	assert.False(t, g.Running())
+
+	// !start launches it, and later rejoins start it again
+	require.True(t, b.startGame("#test"))
+	assert.False(t, g.Halted())
+	g.Stop()
+	b.rejoinGame("#test")
+	assert.True(t, g.Running())
+	g.Stop()
+}
+
+func TestTrackMode(t *testing.T) {
+	checker := permissions.NewChecker(config.PermissionsConfig{})
+
+	trackMode(checker, &irc.Line{Cmd: irc.MODE, Args: []string{"#pigeons", "+o", "alice"}})
+	assert.Equal(t, permissions.Op, checker.LevelOf("#pigeons", "alice", "a", "host"))
+
+	// user modes are not channels
+	trackMode(checker, &irc.Line{Cmd: irc.MODE, Args: []string{"pigeonbot", "+o", "pigeonbot"}})
+	assert.Equal(t, permissions.Anyone, checker.LevelOf("pigeonbot", "pigeonbot", "p", "host"))
+}
@@ -0,0 +1,503 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/channel"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/commands"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/game"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/identity"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/outbox"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/permissions"
+	irc "github.com/fluffle/goirc/client"
+)
+
+// repositories are shared by every network
+type repositories struct {
+	players  player.PlayerRepository
+	settings settings.SettingsRepository
+	channels channel.ChannelRepository
+	shots    shot.ShotEventRepository
+	seasons  season.SeasonRepository
+	aliases  alias.AliasRepository
+}
+
+// accountCaps are the IRCv3 capabilities that tell the bot who is logged in to services
+var accountCaps = []string{"account-notify", "extended-join", "account-tag"}
+
+// networkBot is the connection to one IRC network and the games in its channels
+type networkBot struct {
+	ctx           context.Context
+	cfg           config.IRCConfig
+	gameCfg       config.GameConfig
+	conn          *irc.Conn
+	out           *outbox.Queue // everything the games say goes through here
+	identified    *Identified
+	checker       *permissions.Checker
+	identities    *identity.Tracker
+	retry         *backoff
+	disconnected  chan struct{}
+	gameInstances *GameInstances
+	repos         repositories
+	shots         game.ShotRecorder
+	persistence   game.Persistence
+
+	poolsMu sync.Mutex
+	pools   map[string]*game.Players // score pool -> players shared by its channels
+}
+
+func newNetworkBot(ctx context.Context, network config.Network, perms config.PermissionsConfig, gameInstances *GameInstances, repos repositories, shots game.ShotRecorder) (*networkBot, error) {
+	cfg := network.IRC
+
+	tlsCfg, err := tlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	saslCfg, err := saslClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	persistence, err := game.ParsePersistence(network.Game.Persistence)
+	if err != nil {
+		return nil, err
+	}
+
+	ircConfig := irc.NewConfig(cfg.Nick)
+	ircConfig.Me.Name = cfg.RealName
+	ircConfig.Me.Ident = cfg.Nick
+	ircConfig.SSL = cfg.SSL
+	ircConfig.SSLConfig = tlsCfg
+	if saslCfg != nil {
+		// goirc negotiates CAP sasl and holds registration until it finishes
+		ircConfig.Sasl = saslCfg
+	}
+	// scores follow services accounts where the server shares them
+	ircConfig.EnableCapabilityNegotiation = true
+	ircConfig.Capabilites = accountCaps
+	ircConfig.Server = fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	ircConfig.QuitMessage = cfg.QuitMessage
+
+	conn := irc.Client(ircConfig)
+	b := &networkBot{
+		ctx:           ctx,
+		cfg:           cfg,
+		gameCfg:       network.Game,
+		conn:          conn,
+		out:           outbox.New(IRCWrapper{conn}, cfg.FloodBurst, time.Duration(cfg.FloodInterval)*time.Millisecond),
+		identified:    &Identified{identified: false},
+		checker:       permissions.NewChecker(perms),
+		identities:    identity.NewTracker(),
+		retry:         newBackoff(time.Duration(cfg.ReconnectMin)*time.Second, time.Duration(cfg.ReconnectMax)*time.Second),
+		disconnected:  make(chan struct{}, 1),
+		gameInstances: gameInstances,
+		repos:         repos,
+		shots:         shots,
+		persistence:   persistence,
+		pools:         make(map[string]*game.Players),
+	}
+
+	// channels joined with !join or INVITE are remembered across restarts
+	joined, err := repos.channels.GetChannels(ctx, cfg.Network)
+	if err != nil {
+		fmt.Printf("Error loading joined channels for %s: %s\n", cfg.Network, err.Error())
+	}
+
+	// for each channel make a new game instance
+	for _, channel := range slices.Concat(cfg.Channels, joined) {
+		if strings.TrimSpace(channel) == "" {
+			continue
+		}
+		if _, _, ok := gameInstances.Get(cfg.Network, channel); ok {
+			continue
+		}
+		b.addGame(channel)
+	}
+
+	b.registerHandlers()
+	return b, nil
+}
+
+// addGame creates the game and commands for channel
+func (b *networkBot) addGame(channel string) *game.Game {
+	stored, err := b.repos.settings.GetSettings(b.ctx, b.cfg.Network, channel)
+	if err != nil {
+		fmt.Printf("Error loading settings for %s: %s\n", channel, err.Error())
+	}
+
+	// ✅ IMPORTANT: pass the rate-limited queue (Privmsg/Notice/Raw) not raw conn
+	gameInstance := game.NewGame(b.gameCfg, b.out, b.repos.players, b.cfg.Network, channel,
+		game.WithListingClient(b.out.Low()),
+		game.WithSettings(stored),
+		game.WithSettingsRepository(b.repos.settings),
+		game.WithScorePool(b.scorePool(channel)),
+		game.WithShotRecorder(b.shots),
+		game.WithShotRepository(b.repos.shots),
+		game.WithSeasonRepository(b.repos.seasons),
+		game.WithAliasRepository(b.repos.aliases),
+		game.WithPersistence(b.persistence, time.Duration(b.gameCfg.SaveDelay)*time.Millisecond),
+		game.WithNetworkGames(func() []*game.Game { return b.gameInstances.Games(b.cfg.Network) }),
+	)
+	commandInstance := commands.NewCommandController(gameInstance, b.checker)
+
+	commandInstance.AddCommand("!shoot", gameInstance.HandleShoot)
+	commandInstance.AddCommand("!score", gameInstance.HandlePoints)
+	commandInstance.AddCommand("!help", gameInstance.HandleHelp)
+	commandInstance.AddCommand("!pigeons", gameInstance.HandleCount)
+	commandInstance.AddCommand("!bef", gameInstance.HandleBef)
+	commandInstance.AddCommand("!level", gameInstance.HandleLevel)
+	commandInstance.AddCommand("!top5", gameInstance.HandleTop5)
+	commandInstance.AddCommand("!top10", gameInstance.HandleTop10)
+	commandInstance.AddCommand("!top", gameInstance.HandleTop)
+	commandInstance.AddCommand("!stats", gameInstance.HandleStats)
+	commandInstance.AddCommand("!season", gameInstance.HandleSeason)
+	commandInstance.AddCommand("!eggs", gameInstance.HandleEggs)
+	commandInstance.AddCommand("!set", gameInstance.HandleSet, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!start", func(context.Context, ...string) error {
+		if !b.startGame(channel) {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgAlreadyRunning))
+		}
+		return nil
+	}, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!stop", gameInstance.HandleStop, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!pause", gameInstance.HandlePause, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!resume", gameInstance.HandleResume, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!forcespawn", gameInstance.HandleForceSpawn, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!player", gameInstance.HandlePlayer, commands.Require(permissions.Op))
+	commandInstance.AddCommand("!queue", func(context.Context, ...string) error {
+		stats := b.out.Stats()
+		gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgQueueStats,
+			b.cfg.Network, stats.Waiting, stats.Sent, stats.Coalesced, stats.Dropped))
+		return nil
+	}, commands.Require(permissions.Op))
+
+	commandInstance.AddCommand("!join", func(ctx context.Context, args ...string) error {
+		if len(args) == 0 || !isChannel(args[0]) {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgJoinUsage))
+			return nil
+		}
+		if err := b.joinChannel(ctx, args[0]); err != nil {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgJoinFailed, args[0]))
+			return err
+		}
+		gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgJoining, args[0]))
+		return nil
+	}, commands.Require(permissions.Owner))
+	commandInstance.AddCommand("!part", func(ctx context.Context, args ...string) error {
+		target := channel
+		if len(args) > 0 {
+			target = args[0]
+		}
+		if !isChannel(target) {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgPartUsage))
+			return nil
+		}
+		if _, _, ok := b.gameInstances.Get(b.cfg.Network, target); !ok {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgNotInChannel, target))
+			return nil
+		}
+		if strings.EqualFold(target, channel) {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgPartingHere))
+		} else {
+			gameInstance.Styled().Privmsg(channel, gameInstance.Msg(game.MsgParting, target))
+		}
+		return b.partChannel(ctx, target)
+	}, commands.Require(permissions.Owner))
+
+	// ✅ ping command (CTCP PING -> NOTICE reply -> Pong)
+	commandInstance.AddCommand("!ping", gameInstance.HandlePingCommand)
+
+	b.gameInstances.Add(b.cfg.Network, channel, gameInstance, commandInstance)
+	return gameInstance
+}
+
+// scorePool returns the players shared by every channel in channel's score
+// pool, so games never overwrite each other's saved scores
+func (b *networkBot) scorePool(channel string) *game.Players {
+	b.poolsMu.Lock()
+	defer b.poolsMu.Unlock()
+
+	pool := strings.ToLower(b.gameCfg.ScorePoolFor(channel))
+	players, ok := b.pools[pool]
+	if !ok {
+		players = &game.Players{}
+		b.pools[pool] = players
+	}
+	return players
+}
+
+// startGame starts the game loop for channel unless it is already running.
+// Launching it clears a !stop.
+func (b *networkBot) startGame(channel string) bool {
+	g, _, ok := b.gameInstances.Get(b.cfg.Network, channel)
+	if !ok {
+		return false
+	}
+	if !g.Launch(b.ctx) {
+		fmt.Printf("Game already started for %s %s\n", b.cfg.Network, channel)
+		return false
+	}
+	fmt.Printf("Starting gameInstance for %s %s\n", b.cfg.Network, channel)
+	return true
+}
+
+// rejoinGame starts channel's game once the bot is in the channel again,
+// unless an operator stopped it with !stop
+func (b *networkBot) rejoinGame(channel string) {
+	if g, _, ok := b.gameInstances.Get(b.cfg.Network, channel); ok && g.Halted() {
+		fmt.Printf("Not starting gameInstance for %s %s: stopped by an operator\n", b.cfg.Network, channel)
+		return
+	}
+	b.startGame(channel)
+}
+
+// joinChannels joins every channel that has a game
+func (b *networkBot) joinChannels(conn *irc.Conn) {
+	for _, g := range b.gameInstances.Games(b.cfg.Network) {
+		fmt.Printf("Joining channel %s on %s\n", g.Channel(), b.cfg.Network)
+		conn.Join(g.Channel())
+	}
+}
+
+// joinChannel adds a game for channel, remembers it and joins it. The game
+// starts once the server confirms the JOIN.
+func (b *networkBot) joinChannel(ctx context.Context, channel string) error {
+	// joined channels are stored lowercased, so the game is keyed the same
+	// way now as when it is rebuilt from the database after a restart
+	channel = strings.ToLower(channel)
+	if err := b.repos.channels.AddChannel(ctx, b.cfg.Network, channel); err != nil {
+		return fmt.Errorf("saving channel %s: %w", channel, err)
+	}
+	if _, _, ok := b.gameInstances.Get(b.cfg.Network, channel); !ok {
+		b.addGame(channel)
+	}
+	if b.conn.Connected() {
+		b.conn.Join(channel)
+	}
+	return nil
+}
+
+// partChannel stops and saves channel's game, forgets it and leaves
+func (b *networkBot) partChannel(ctx context.Context, channel string) error {
+	var errs []error
+	if g := b.gameInstances.Remove(b.cfg.Network, channel); g != nil {
+		if err := g.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("saving %s: %w", channel, err))
+		}
+	}
+	if err := b.repos.channels.RemoveChannel(ctx, b.cfg.Network, channel); err != nil {
+		errs = append(errs, fmt.Errorf("forgetting channel %s: %w", channel, err))
+	}
+	if b.conn.Connected() {
+		b.conn.Part(channel)
+	}
+	return errors.Join(errs...)
+}
+
+// stopGame stops channel's game loop after the bot left it; the game stays
+// registered so it starts again when the channel is rejoined
+func (b *networkBot) stopGame(channel string) {
+	g, _, ok := b.gameInstances.Get(b.cfg.Network, channel)
+	if ok && g.Stop() {
+		fmt.Printf("Stopped gameInstance for %s %s\n", b.cfg.Network, channel)
+	}
+}
+
+// isChannel reports whether name looks like an IRC channel
+func isChannel(name string) bool {
+	return len(name) > 1 && strings.ContainsRune("#&+!", rune(name[0])) && !strings.ContainsAny(name, " ,\a")
+}
+
+// onDisconnect pauses the network's games until their channel is rejoined;
+// NickServ needs identifying again and who is who is learnt afresh
+func (b *networkBot) onDisconnect() {
+	b.gameInstances.StopNetwork(b.cfg.Network)
+	b.identified.Reset()
+	b.identities.Reset()
+}
+
+// setAccount records the services account nick is logged in to ("*" or ""
+// when logged out)
+func (b *networkBot) setAccount(nick, account string) {
+	if b.identities.SetAccount(nick, account) {
+		b.rememberAlias(nick)
+	}
+}
+
+// rememberAlias stores nick as one of its account's nicks, so it plays as the
+// account and nobody else can shoot with it while logged out
+func (b *networkBot) rememberAlias(nick string) {
+	id := b.identities.Lookup(nick)
+	if !id.Account || b.repos.aliases == nil {
+		return
+	}
+	if err := b.repos.aliases.SetAlias(b.ctx, b.cfg.Network, nick, id.Name); err != nil {
+		fmt.Printf("Error saving alias %s for %s: %s\n", nick, id.Name, err.Error())
+	}
+}
+
+// quit sends what is still queued, then QUIT, and waits for the server to
+// close the connection
+func (b *networkBot) quit(ctx context.Context) {
+	if err := b.out.Close(ctx); err != nil {
+		fmt.Printf("Error flushing outbound queue for %s: %s\n", b.cfg.Network, err.Error())
+	}
+	stats := b.out.Stats()
+	fmt.Printf("Outbound queue for %s: %d sent, %d coalesced, %d dropped, %d unsent\n",
+		b.cfg.Network, stats.Sent, stats.Coalesced, stats.Dropped, stats.Waiting)
+
+	if !b.conn.Connected() {
+		return
+	}
+	b.conn.Quit()
+	select {
+	case <-b.disconnected:
+	case <-ctx.Done():
+		b.conn.Close()
+	}
+}
+
+func (b *networkBot) registerHandlers() {
+	c := b.conn
+
+	c.HandleFunc(irc.CONNECTED, func(conn *irc.Conn, _ *irc.Line) {
+		fmt.Printf("Connected to %s\n", b.cfg.Host)
+		b.retry.Reset()
+		b.joinChannels(conn)
+	})
+
+	// Also join on MOTD end / no MOTD
+	c.HandleFunc("422", func(conn *irc.Conn, _ *irc.Line) {
+		b.joinChannels(conn)
+	})
+	c.HandleFunc("376", func(conn *irc.Conn, _ *irc.Line) {
+		b.joinChannels(conn)
+	})
+
+	// RPL_SASLSUCCESS arrives before registration completes, so NickServ is skipped.
+	// If SASL fails we stay unidentified and fall back to NickServ.
+	c.HandleFunc("903", func(_ *irc.Conn, _ *irc.Line) {
+		fmt.Printf("SASL authentication succeeded on %s\n", b.cfg.Network)
+		b.identified.Lock()
+		b.identified.identified = true
+		b.identified.Unlock()
+	})
+	c.HandleFunc("904", func(_ *irc.Conn, _ *irc.Line) {
+		fmt.Printf("SASL authentication failed on %s\n", b.cfg.Network)
+	})
+
+	c.HandleFunc(irc.JOIN, func(conn *irc.Conn, line *irc.Line) {
+		channel := line.Args[0]
+		if line.Nick != conn.Me().Nick {
+			b.checker.Join(channel, line.Nick)
+			// extended-join: JOIN <channel> <account> :<realname>
+			if len(line.Args) >= 2 && conn.HasCapability("extended-join") {
+				b.setAccount(line.Nick, line.Args[1])
+			}
+			return
+		}
+		fmt.Printf("Joined %s\n", channel)
+
+		handleNickserv(b.cfg, b.identified, conn)
+
+		b.rejoinGame(channel)
+	})
+
+	trackPermissions(c, b.checker)
+
+	// account-notify: ACCOUNT <account>, or * after logging out
+	c.HandleFunc("ACCOUNT", func(_ *irc.Conn, line *irc.Line) {
+		if len(line.Args) >= 1 {
+			b.setAccount(line.Nick, line.Args[0])
+		}
+	})
+	c.HandleFunc(irc.NICK, func(_ *irc.Conn, line *irc.Line) {
+		if len(line.Args) >= 1 {
+			b.identities.Rename(line.Nick, line.Args[0])
+			b.rememberAlias(line.Args[0])
+		}
+	})
+	c.HandleFunc(irc.QUIT, func(_ *irc.Conn, line *irc.Line) {
+		b.identities.Quit(line.Nick)
+	})
+
+	c.HandleFunc(irc.PART, func(conn *irc.Conn, line *irc.Line) {
+		if len(line.Args) >= 1 && line.Nick == conn.Me().Nick {
+			b.stopGame(line.Args[0])
+		}
+	})
+	c.HandleFunc(irc.KICK, func(conn *irc.Conn, line *irc.Line) {
+		if len(line.Args) >= 2 && line.Args[1] == conn.Me().Nick {
+			fmt.Printf("Kicked from %s by %s\n", line.Args[0], line.Nick)
+			b.stopGame(line.Args[0])
+		}
+	})
+
+	// INVITE <me> <channel>: follow invites from trusted users and owners
+	c.HandleFunc(irc.INVITE, func(_ *irc.Conn, line *irc.Line) {
+		if len(line.Args) < 2 || !isChannel(line.Args[1]) {
+			return
+		}
+		channel := line.Args[1]
+		if b.checker.LevelOf(channel, line.Nick, line.Ident, line.Host) < permissions.Op {
+			fmt.Printf("Ignoring invite to %s from %s\n", channel, line.Src)
+			return
+		}
+		fmt.Printf("Invited to %s by %s\n", channel, line.Nick)
+		if err := b.joinChannel(b.ctx, channel); err != nil {
+			fmt.Printf("Error joining %s: %s\n", channel, err.Error())
+		}
+	})
+
+	c.HandleFunc(irc.PRIVMSG, func(conn *irc.Conn, line *irc.Line) {
+		channel := line.Args[0]
+
+		g, commandInstance, ok := b.gameInstances.Get(b.cfg.Network, channel)
+		if !ok {
+			return
+		}
+		g.NoteActivity()
+
+		// account-tag: every message says who sent it; no tag means logged out
+		if conn.HasCapability("account-tag") {
+			b.setAccount(line.Nick, line.Tags["account"])
+		}
+
+		ctxWithNick := context.WithValue(b.ctx, "nick", line.Nick)
+		ctxWithNick = context_manager.WithIdentity(ctxWithNick, b.identities.Lookup(line.Nick))
+		if err := commandInstance.HandleCommand(ctxWithNick, line); err != nil {
+			fmt.Printf("Error handling command: %s\n", err.Error())
+			return
+		}
+	})
+
+	// CTCPREPLY handler - goirc parses CTCP and dispatches to this event
+	c.HandleFunc(irc.CTCPREPLY, func(_ *irc.Conn, line *irc.Line) {
+		if len(line.Args) < 1 {
+			return
+		}
+
+		for _, g := range b.gameInstances.Games(b.cfg.Network) {
+			g.HandleCTCPReply(line.Nick, line.Args)
+		}
+	})
+
+	c.HandleFunc(irc.DISCONNECTED, func(_ *irc.Conn, _ *irc.Line) {
+		select {
+		case b.disconnected <- struct{}{}:
+		default:
+		}
+	})
+}
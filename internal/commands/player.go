@@ -0,0 +1,95 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/healthcheck"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playerNetwork string
+	playerChannel string
+	playerForce   bool
+)
+
+// errBotRunning is returned while a bot answers the healthcheck: its roster
+// still has the old names and would write them back
+var errBotRunning = errors.New("pigeonbot is running; stop it first or use !player in the channel (--force skips this check)")
+
+// playerCmd represents the player command
+var playerCmd = &cobra.Command{
+	Use:   "player",
+	Short: "Merge, rename or delete player records",
+	Long: `Merge, rename or delete player records in the database.
+
+Points, pigeons, eggs, shot history and season results move together in one
+transaction. Without --channel every channel of the network is changed.
+
+A running bot keeps its players in memory and would write the old names back,
+so the command refuses to run while a bot answers the healthcheck on this
+host. Stop the bot first, or use !player in the channel to change both.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if !playerForce && healthcheck.Running(cmd.Context(), config.LoadConfigOrPanic().AppConfig) {
+			return errBotRunning
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// error need to call subcommand
+		return fmt.Errorf("please call subcommand")
+	},
+}
+
+// playerMergeCmd represents the player merge command
+var playerMergeCmd = &cobra.Command{
+	Use:   "merge <from> <into>",
+	Short: "Add one player's score to another's and remove the first",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return playerRepository().MergePlayers(cmd.Context(), playerNetwork, playerChannel, args[0], args[1])
+	},
+}
+
+// playerRenameCmd represents the player rename command
+var playerRenameCmd = &cobra.Command{
+	Use:   "rename <from> <to>",
+	Short: "Move a player's score to a new name",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return playerRepository().RenamePlayer(cmd.Context(), playerNetwork, playerChannel, args[0], args[1])
+	},
+}
+
+// playerDeleteCmd represents the player delete command
+var playerDeleteCmd = &cobra.Command{
+	Use:   "delete <nick>",
+	Short: "Delete a player's score and shot history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return playerRepository().DeletePlayer(cmd.Context(), playerNetwork, playerChannel, args[0])
+	},
+}
+
+// playerRepository opens the configured database
+func playerRepository() player.PlayerRepository {
+	cfg := config.LoadConfigOrPanic()
+	return player.NewPlayerRepository(db.NewDatabase(cfg.DBConfig))
+}
+
+func init() {
+	rootCmd.AddCommand(playerCmd)
+	playerCmd.AddCommand(playerMergeCmd, playerRenameCmd, playerDeleteCmd)
+
+	playerCmd.PersistentFlags().StringVar(&playerNetwork, "network", "", "network the player is on")
+	playerCmd.PersistentFlags().StringVar(&playerChannel, "channel", "", "channel or score pool, every channel of the network if empty")
+	playerCmd.PersistentFlags().BoolVar(&playerForce, "force", false, "run even though a bot answers the healthcheck")
+	_ = playerCmd.MarkPersistentFlagRequired("network")
+}
@@ -2,23 +2,67 @@ package db
 
 import (
 	"fmt"
+
 	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// database drivers for config.DBConfig.Driver
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DB struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Driver string // DriverPostgres or DriverSQLite
 }
 
 func NewDatabase(cfg config.DBConfig) *DB {
+	switch cfg.Driver {
+	case "", DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DataBase, cfg.SSLMode)
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DataBase, cfg.SSLMode)
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err != nil {
+			panic("failed to connect database")
+		}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		return &DB{DB: db, Driver: DriverPostgres}
+	case DriverSQLite:
+		return newSQLite(cfg.Path)
+	default:
+		panic(fmt.Sprintf("unknown database driver %q", cfg.Driver))
+	}
+}
+
+// newSQLite opens the SQLite database at path, creating it if needed;
+// ":memory:" keeps it in memory until it is closed
+func newSQLite(path string) *DB {
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 	if err != nil {
-		panic("failed to connect database")
+		panic("failed to open database")
 	}
 
-	return &DB{DB: db}
+	// SQLite takes one writer at a time, and every connection to ":memory:"
+	// would get a database of its own
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic("failed to open database")
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	return &DB{DB: db, Driver: DriverSQLite}
+}
+
+// Close closes the underlying connection pool
+func (d *DB) Close() error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
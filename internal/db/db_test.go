@@ -1,6 +1,7 @@
 package db
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
@@ -57,3 +58,23 @@ func TestNewDatabase_InvalidConfig(t *testing.T) {
 		})
 	})
 }
+
+func TestNewDatabase_SQLite(t *testing.T) {
+	t.Run("creates the database file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pigeon.db")
+		database := NewDatabase(config.DBConfig{Driver: DriverSQLite, Path: path})
+		defer database.Close()
+
+		assert.Equal(t, DriverSQLite, database.Driver)
+		var result int
+		require.NoError(t, database.DB.Raw("SELECT 1").Scan(&result).Error)
+		assert.Equal(t, 1, result)
+		assert.FileExists(t, path)
+	})
+
+	t.Run("panics on an unknown driver", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewDatabase(config.DBConfig{Driver: "oracle"})
+		})
+	})
+}
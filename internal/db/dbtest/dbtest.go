@@ -0,0 +1,27 @@
+// Package dbtest opens databases for repository tests
+package dbtest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	migrations "github.com/MyelinBots/pigeonbot-go/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+)
+
+// SQLite returns a migrated SQLite database of its own for t, closed when t ends
+func SQLite(t testing.TB) *db.DB {
+	t.Helper()
+
+	database := db.NewDatabase(config.DBConfig{
+		Driver: db.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "pigeon.db"),
+	})
+	t.Cleanup(func() { _ = database.Close() })
+
+	if err := migrations.MigrateDatabase(database); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+	return database
+}
@@ -0,0 +1,18 @@
+package alias
+
+import "time"
+
+// PlayerAlias links a nick to the services account last seen using it
+type PlayerAlias struct {
+	ID        string    `gorm:"column:id;type:varchar(255);primaryKey" json:"id"`
+	Network   string    `gorm:"column:network;type:text;not null" json:"network"`
+	Nick      string    `gorm:"column:nick;type:text;not null" json:"nick"`
+	Account   string    `gorm:"column:account;type:text;not null" json:"account"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// set table name
+func (PlayerAlias) TableName() string {
+	return "player_alias"
+}
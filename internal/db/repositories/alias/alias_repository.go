@@ -0,0 +1,75 @@
+//go:generate mockgen -destination=mocks/mock_alias_repository.go -package=mocks github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias AliasRepository
+package alias
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AliasRepository interface {
+	SetAlias(ctx context.Context, network, nick, account string) error
+	GetAccount(ctx context.Context, network, nick string) (string, error)
+	IsAccount(ctx context.Context, network, name string) (bool, error)
+}
+
+type AliasRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewAliasRepository(db *db.DB) AliasRepository {
+	return &AliasRepositoryImpl{
+		db: db,
+	}
+}
+
+// SetAlias records that nick is used by account, replacing any earlier owner
+func (r *AliasRepositoryImpl) SetAlias(ctx context.Context, network, nick, account string) error {
+	now := time.Now()
+	row := PlayerAlias{
+		ID:        uuid.New().String(),
+		Network:   network,
+		Nick:      strings.ToLower(nick),
+		Account:   strings.ToLower(account),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "network"}, {Name: "nick"}},
+			DoUpdates: clause.AssignmentColumns([]string{"account", "updated_at"}),
+		}).
+		Create(&row).Error
+}
+
+// GetAccount returns the account nick belongs to, or "" when it is nobody's
+func (r *AliasRepositoryImpl) GetAccount(ctx context.Context, network, nick string) (string, error) {
+	var row PlayerAlias
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND nick = ?", network, strings.ToLower(nick)).
+		First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return row.Account, nil
+}
+
+// IsAccount reports whether name is a services account some nick was seen with
+func (r *AliasRepositoryImpl) IsAccount(ctx context.Context, network, name string) (bool, error) {
+	var count int64
+	err := r.db.DB.WithContext(ctx).
+		Model(&PlayerAlias{}).
+		Where("network = ? AND account = ?", network, strings.ToLower(name)).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,83 @@
+package alias
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) (*db.DB, func()) {
+	t.Helper()
+
+	cfg := config.LoadConfigOrPanic()
+	if cfg.DBConfig.Driver == db.DriverSQLite {
+		// DBDRIVER=sqlite runs against a fresh database, no server needed
+		return dbtest.SQLite(t), func() {}
+	}
+	database := db.NewDatabase(cfg.DBConfig)
+
+	sqlDB, err := database.DB.DB()
+	require.NoError(t, err)
+
+	cleanup := func() {
+		database.DB.Exec("TRUNCATE TABLE player_alias")
+		sqlDB.Close()
+	}
+
+	database.DB.Exec("TRUNCATE TABLE player_alias")
+
+	return database, cleanup
+}
+
+func TestAliasRepository(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewAliasRepository(database)
+	ctx := context.Background()
+
+	t.Run("unknown nick is nobody's", func(t *testing.T) {
+		account, err := repo.GetAccount(ctx, "testnet", "bob")
+		require.NoError(t, err)
+		assert.Empty(t, account)
+	})
+
+	t.Run("last account seen with a nick owns it", func(t *testing.T) {
+		require.NoError(t, repo.SetAlias(ctx, "testnet", "Bob", "Robert"))
+		require.NoError(t, repo.SetAlias(ctx, "othernet", "bob", "bobby"))
+
+		account, err := repo.GetAccount(ctx, "testnet", "BOB")
+		require.NoError(t, err)
+		assert.Equal(t, "robert", account)
+
+		require.NoError(t, repo.SetAlias(ctx, "testnet", "bob", "bobcat"))
+		account, err = repo.GetAccount(ctx, "testnet", "bob")
+		require.NoError(t, err)
+		assert.Equal(t, "bobcat", account)
+
+		account, err = repo.GetAccount(ctx, "othernet", "bob")
+		require.NoError(t, err)
+		assert.Equal(t, "bobby", account)
+	})
+
+	t.Run("an account is known by the nicks seen with it", func(t *testing.T) {
+		require.NoError(t, repo.SetAlias(ctx, "testnet", "al", "Alice"))
+
+		known, err := repo.IsAccount(ctx, "testnet", "ALICE")
+		require.NoError(t, err)
+		assert.True(t, known)
+
+		known, err = repo.IsAccount(ctx, "testnet", "al")
+		require.NoError(t, err)
+		assert.False(t, known, "a nick is not an account")
+
+		known, err = repo.IsAccount(ctx, "othernet", "alice")
+		require.NoError(t, err)
+		assert.False(t, known)
+	})
+}
@@ -0,0 +1,16 @@
+package channel
+
+import "time"
+
+// JoinedChannel is a channel the bot was asked to join at runtime
+type JoinedChannel struct {
+	ID        string    `gorm:"column:id;type:varchar(255);primaryKey" json:"id"`
+	Network   string    `gorm:"column:network;type:text;not null" json:"network"`
+	Channel   string    `gorm:"column:channel;type:text;not null" json:"channel"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+// set table name
+func (JoinedChannel) TableName() string {
+	return "joined_channels"
+}
@@ -0,0 +1,70 @@
+//go:generate mockgen -destination=mocks/mock_channel_repository.go -package=mocks github.com/MyelinBots/pigeonbot-go/internal/db/repositories/channel ChannelRepository
+package channel
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+type ChannelRepository interface {
+	GetChannels(ctx context.Context, network string) ([]string, error)
+	AddChannel(ctx context.Context, network, channel string) error
+	RemoveChannel(ctx context.Context, network, channel string) error
+}
+
+type ChannelRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewChannelRepository(db *db.DB) ChannelRepository {
+	return &ChannelRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetChannels returns the channels joined at runtime on network, oldest first
+func (r *ChannelRepositoryImpl) GetChannels(ctx context.Context, network string) ([]string, error) {
+	var rows []*JoinedChannel
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ?", network).
+		Order("created_at ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]string, 0, len(rows))
+	for _, row := range rows {
+		channels = append(channels, row.Channel)
+	}
+	return channels, nil
+}
+
+// AddChannel remembers channel; adding it twice is a no-op
+func (r *ChannelRepositoryImpl) AddChannel(ctx context.Context, network, channel string) error {
+	row := JoinedChannel{
+		ID:        uuid.New().String(),
+		Network:   network,
+		Channel:   strings.ToLower(channel),
+		CreatedAt: time.Now(),
+	}
+
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "network"}, {Name: "channel"}},
+			DoNothing: true,
+		}).
+		Create(&row).Error
+}
+
+// RemoveChannel forgets channel
+func (r *ChannelRepositoryImpl) RemoveChannel(ctx context.Context, network, channel string) error {
+	return r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", network, strings.ToLower(channel)).
+		Delete(&JoinedChannel{}).Error
+}
@@ -0,0 +1,72 @@
+package channel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) (*db.DB, func()) {
+	t.Helper()
+
+	cfg := config.LoadConfigOrPanic()
+	if cfg.DBConfig.Driver == db.DriverSQLite {
+		// DBDRIVER=sqlite runs against a fresh database, no server needed
+		return dbtest.SQLite(t), func() {}
+	}
+	database := db.NewDatabase(cfg.DBConfig)
+
+	sqlDB, err := database.DB.DB()
+	require.NoError(t, err)
+
+	cleanup := func() {
+		database.DB.Exec("TRUNCATE TABLE joined_channels")
+		sqlDB.Close()
+	}
+
+	database.DB.Exec("TRUNCATE TABLE joined_channels")
+
+	return database, cleanup
+}
+
+func TestChannelRepository(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewChannelRepository(database)
+	ctx := context.Background()
+
+	t.Run("no channels joined yet", func(t *testing.T) {
+		channels, err := repo.GetChannels(ctx, "testnet")
+		require.NoError(t, err)
+		assert.Empty(t, channels)
+	})
+
+	t.Run("add is idempotent and ignores case", func(t *testing.T) {
+		require.NoError(t, repo.AddChannel(ctx, "testnet", "#Pigeons"))
+		require.NoError(t, repo.AddChannel(ctx, "testnet", "#pigeons"))
+		require.NoError(t, repo.AddChannel(ctx, "testnet", "#park"))
+		require.NoError(t, repo.AddChannel(ctx, "othernet", "#pigeons"))
+
+		channels, err := repo.GetChannels(ctx, "testnet")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"#pigeons", "#park"}, channels)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, repo.RemoveChannel(ctx, "testnet", "#PIGEONS"))
+
+		channels, err := repo.GetChannels(ctx, "testnet")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"#park"}, channels)
+
+		channels, err = repo.GetChannels(ctx, "othernet")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"#pigeons"}, channels)
+	})
+}
@@ -97,6 +97,36 @@ func (mr *MockPlayerRepositoryMockRecorder) TopByPoints(ctx, network, channel, l
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopByPoints", reflect.TypeOf((*MockPlayerRepository)(nil).TopByPoints), ctx, network, channel, limit)
 }
 
+// TopByPointsNetwork mocks base method.
+func (m *MockPlayerRepository) TopByPointsNetwork(ctx context.Context, network string, limit int) ([]*player.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopByPointsNetwork", ctx, network, limit)
+	ret0, _ := ret[0].([]*player.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopByPointsNetwork indicates an expected call of TopByPointsNetwork.
+func (mr *MockPlayerRepositoryMockRecorder) TopByPointsNetwork(ctx, network, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopByPointsNetwork", reflect.TypeOf((*MockPlayerRepository)(nil).TopByPointsNetwork), ctx, network, limit)
+}
+
+// GetPlayerTotals mocks base method.
+func (m *MockPlayerRepository) GetPlayerTotals(ctx context.Context, network, name string) (*player.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlayerTotals", ctx, network, name)
+	ret0, _ := ret[0].(*player.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlayerTotals indicates an expected call of GetPlayerTotals.
+func (mr *MockPlayerRepositoryMockRecorder) GetPlayerTotals(ctx, network, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlayerTotals", reflect.TypeOf((*MockPlayerRepository)(nil).GetPlayerTotals), ctx, network, name)
+}
+
 // UpsertPlayer mocks base method.
 func (m *MockPlayerRepository) UpsertPlayer(ctx context.Context, player *player.Player) error {
 	m.ctrl.T.Helper()
@@ -111,6 +141,108 @@ func (mr *MockPlayerRepositoryMockRecorder) UpsertPlayer(ctx, player any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPlayer", reflect.TypeOf((*MockPlayerRepository)(nil).UpsertPlayer), ctx, player)
 }
 
+// AwardKill mocks base method.
+func (m *MockPlayerRepository) AwardKill(ctx context.Context, network, channel, name string, points int) (*player.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AwardKill", ctx, network, channel, name, points)
+	ret0, _ := ret[0].(*player.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AwardKill indicates an expected call of AwardKill.
+func (mr *MockPlayerRepositoryMockRecorder) AwardKill(ctx, network, channel, name, points any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwardKill", reflect.TypeOf((*MockPlayerRepository)(nil).AwardKill), ctx, network, channel, name, points)
+}
+
+// RecordMiss mocks base method.
+func (m *MockPlayerRepository) RecordMiss(ctx context.Context, network, channel, name string) (*player.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMiss", ctx, network, channel, name)
+	ret0, _ := ret[0].(*player.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMiss indicates an expected call of RecordMiss.
+func (mr *MockPlayerRepositoryMockRecorder) RecordMiss(ctx, network, channel, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMiss", reflect.TypeOf((*MockPlayerRepository)(nil).RecordMiss), ctx, network, channel, name)
+}
+
+// AddPoints mocks base method.
+func (m *MockPlayerRepository) AddPoints(ctx context.Context, network, channel, name string, points int) (*player.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPoints", ctx, network, channel, name, points)
+	ret0, _ := ret[0].(*player.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPoints indicates an expected call of AddPoints.
+func (mr *MockPlayerRepositoryMockRecorder) AddPoints(ctx, network, channel, name, points any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPoints", reflect.TypeOf((*MockPlayerRepository)(nil).AddPoints), ctx, network, channel, name, points)
+}
+
+// CollectEggs mocks base method.
+func (m *MockPlayerRepository) CollectEggs(ctx context.Context, network, channel, name string, eggs, rareEggs int) (*player.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectEggs", ctx, network, channel, name, eggs, rareEggs)
+	ret0, _ := ret[0].(*player.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectEggs indicates an expected call of CollectEggs.
+func (mr *MockPlayerRepositoryMockRecorder) CollectEggs(ctx, network, channel, name, eggs, rareEggs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectEggs", reflect.TypeOf((*MockPlayerRepository)(nil).CollectEggs), ctx, network, channel, name, eggs, rareEggs)
+}
+
+// MergePlayers mocks base method.
+func (m *MockPlayerRepository) MergePlayers(ctx context.Context, network, channel, from, into string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergePlayers", ctx, network, channel, from, into)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergePlayers indicates an expected call of MergePlayers.
+func (mr *MockPlayerRepositoryMockRecorder) MergePlayers(ctx, network, channel, from, into any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePlayers", reflect.TypeOf((*MockPlayerRepository)(nil).MergePlayers), ctx, network, channel, from, into)
+}
+
+// RenamePlayer mocks base method.
+func (m *MockPlayerRepository) RenamePlayer(ctx context.Context, network, channel, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenamePlayer", ctx, network, channel, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenamePlayer indicates an expected call of RenamePlayer.
+func (mr *MockPlayerRepositoryMockRecorder) RenamePlayer(ctx, network, channel, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenamePlayer", reflect.TypeOf((*MockPlayerRepository)(nil).RenamePlayer), ctx, network, channel, from, to)
+}
+
+// DeletePlayer mocks base method.
+func (m *MockPlayerRepository) DeletePlayer(ctx context.Context, network, channel, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePlayer", ctx, network, channel, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePlayer indicates an expected call of DeletePlayer.
+func (mr *MockPlayerRepositoryMockRecorder) DeletePlayer(ctx, network, channel, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePlayer", reflect.TypeOf((*MockPlayerRepository)(nil).DeletePlayer), ctx, network, channel, name)
+}
+
 func key(network, channel, name string) string {
 	return network + "|" + channel + "|" + name
 }
@@ -18,6 +18,10 @@ type Player struct {
 	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
 	Eggs       int       `gorm:"column:eggs;type:int;not null;default:0" json:"eggs"`
 	RareEggs   int       `gorm:"column:rare_eggs;type:int;not null;default:0" json:"rare_eggs"`
+	Shots      int       `gorm:"column:shots;type:int;not null;default:0" json:"shots"`
+	Misses     int       `gorm:"column:misses;type:int;not null;default:0" json:"misses"`
+	Streak     int       `gorm:"column:streak;type:int;not null;default:0" json:"streak"`
+	BestStreak int       `gorm:"column:best_streak;type:int;not null;default:0" json:"best_streak"`
 }
 
 // set table name
@@ -4,11 +4,13 @@ package player
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/MyelinBots/pigeonbot-go/internal/db"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // canonicalName ensures consistent lowercase name storage
@@ -20,13 +22,29 @@ type PlayerRepository interface {
 	GetPlayerByID(id string) (*Player, error)
 	GetAllPlayers(ctx context.Context, network string, channel string) ([]*Player, error)
 	UpsertPlayer(ctx context.Context, player *Player) error
+	AwardKill(ctx context.Context, network, channel, name string, points int) (*Player, error)
+	RecordMiss(ctx context.Context, network, channel, name string) (*Player, error)
+	AddPoints(ctx context.Context, network, channel, name string, points int) (*Player, error)
+	CollectEggs(ctx context.Context, network, channel, name string, eggs, rareEggs int) (*Player, error)
 	TopByPoints(ctx context.Context, network, channel string, limit int) ([]*Player, error)
+	TopByPointsNetwork(ctx context.Context, network string, limit int) ([]*Player, error)
+	GetPlayerTotals(ctx context.Context, network, name string) (*Player, error)
 	AddEggs(ctx context.Context, network, channel, name string, delta int) (newTotal int, err error)
 	GetEggs(ctx context.Context, network, channel, name string) (total int, err error)
 	AddRareEggs(ctx context.Context, network, channel, name string, delta int) (newTotal int, err error)
 	GetRareEggs(ctx context.Context, network, channel, name string) (total int, err error)
+	MergePlayers(ctx context.Context, network, channel, from, into string) error
+	RenamePlayer(ctx context.Context, network, channel, from, to string) error
+	DeletePlayer(ctx context.Context, network, channel, name string) error
 }
 
+var (
+	// ErrPlayerNotFound is returned when the player to merge, rename or delete has no score
+	ErrPlayerNotFound = errors.New("player not found")
+	// ErrPlayerExists is returned by RenamePlayer when the new name already has a score
+	ErrPlayerExists = errors.New("player already exists")
+)
+
 type PlayerRepositoryImpl struct {
 	db *db.DB
 }
@@ -56,29 +74,96 @@ func (r *PlayerRepositoryImpl) GetAllPlayers(ctx context.Context, network string
 	return players, nil
 }
 
+// UpsertPlayer sets player's score, shots and streaks, creating the row if
+// there is none. Eggs are only stored when the row is created.
 func (r *PlayerRepositoryImpl) UpsertPlayer(ctx context.Context, player *Player) error {
 	// Canonicalize name for consistent storage
 	player.Name = canonicalName(player.Name)
 
-	// find player by name
-	var existing Player
-	err := r.db.DB.Where("name = ? AND channel = ? AND network = ?", player.Name, player.Channel, player.Network).First(&existing).Error
+	var stored Player
+	err := r.db.DB.WithContext(ctx).Raw(`INSERT INTO player (id, name, network, channel, points, count, shots, misses, streak, best_streak, eggs, rare_eggs)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`+onPlayerConflict+` DO UPDATE SET
+	points = excluded.points,
+	count = excluded.count,
+	shots = excluded.shots,
+	misses = excluded.misses,
+	streak = excluded.streak,
+	best_streak = excluded.best_streak,
+	updated_at = CURRENT_TIMESTAMP
+RETURNING *`,
+		uuid.New().String(), player.Name, player.Network, player.Channel, player.Points, player.Count,
+		player.Shots, player.Misses, player.Streak, player.BestStreak, player.Eggs, player.RareEggs,
+	).Scan(&stored).Error
 	if err != nil {
-		// if error type is gorm.ErrRecordNotFound, create new player
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// create uuid
-			uuidString := uuid.New().String()
-			player.ID = uuidString
-			return r.db.DB.Create(player).Error
-		}
-		// if error is not gorm.ErrRecordNotFound, return error
 		return err
 	}
-	existing.Points = player.Points
-	existing.Count = player.Count
-	// update player
+	player.ID = stored.ID
+	return nil
+}
+
+// AwardKill counts a hit worth points for name and returns the new totals.
+// It is one statement, so concurrent shots, even from another bot, all count.
+func (r *PlayerRepositoryImpl) AwardKill(ctx context.Context, network, channel, name string, points int) (*Player, error) {
+	return r.increment(ctx, network, channel, name, Player{Points: points, Count: 1, Shots: 1, Streak: 1}, streakHit)
+}
 
-	return r.db.DB.Save(&existing).Error
+// RecordMiss counts a missed shot for name, ends their streak and returns the new totals
+func (r *PlayerRepositoryImpl) RecordMiss(ctx context.Context, network, channel, name string) (*Player, error) {
+	return r.increment(ctx, network, channel, name, Player{Shots: 1, Misses: 1}, streakBroken)
+}
+
+// AddPoints adds points to name's score without counting a shot and returns
+// the new totals. Adding 0 returns the stored totals, creating the player if
+// needed.
+func (r *PlayerRepositoryImpl) AddPoints(ctx context.Context, network, channel, name string, points int) (*Player, error) {
+	return r.increment(ctx, network, channel, name, Player{Points: points}, streakKept)
+}
+
+// CollectEggs adds eggs and rare eggs to name's score and returns the new
+// totals. A rare egg is counted in eggs as well, so pass it in both.
+func (r *PlayerRepositoryImpl) CollectEggs(ctx context.Context, network, channel, name string, eggs, rareEggs int) (*Player, error) {
+	return r.increment(ctx, network, channel, name, Player{Eggs: eggs, RareEggs: rareEggs}, streakKept)
+}
+
+// onPlayerConflict makes an insert hit the unique index on a player's name in
+// a channel from migration 000013
+const onPlayerConflict = "ON CONFLICT (network, channel, lower(name)) WHERE deleted_at IS NULL"
+
+// how an increment changes the hit streak, as SQL over the stored row
+const (
+	streakKept   = "player.streak"
+	streakHit    = "player.streak + 1"
+	streakBroken = "0"
+)
+
+// increment adds add's points, pigeons, shots, misses and eggs to name's row,
+// creating it with add's values if there is none, sets the streak to the
+// expression streak and returns the row as stored
+func (r *PlayerRepositoryImpl) increment(ctx context.Context, network, channel, name string, add Player, streak string) (*Player, error) {
+	name = canonicalName(name)
+
+	var stored Player
+	err := r.db.DB.WithContext(ctx).Raw(fmt.Sprintf(`INSERT INTO player (id, name, network, channel, points, count, shots, misses, streak, best_streak, eggs, rare_eggs)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`+onPlayerConflict+` DO UPDATE SET
+	points = player.points + excluded.points,
+	count = player.count + excluded.count,
+	shots = player.shots + excluded.shots,
+	misses = player.misses + excluded.misses,
+	eggs = player.eggs + excluded.eggs,
+	rare_eggs = player.rare_eggs + excluded.rare_eggs,
+	streak = %[1]s,
+	best_streak = CASE WHEN %[1]s > player.best_streak THEN %[1]s ELSE player.best_streak END,
+	updated_at = CURRENT_TIMESTAMP
+RETURNING *`, streak),
+		uuid.New().String(), name, network, channel, add.Points, add.Count,
+		add.Shots, add.Misses, add.Streak, add.Streak, add.Eggs, add.RareEggs,
+	).Scan(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
 }
 
 // TopByPoints returns the top N players by points (and count as tiebreaker).
@@ -105,6 +190,60 @@ func (r *PlayerRepositoryImpl) TopByPoints(ctx context.Context, network, channel
 	return players, nil
 }
 
+// totalsColumns sums a player's rows across every channel of a network
+const totalsColumns = "name, network, SUM(points) AS points, SUM(count) AS count, SUM(eggs) AS eggs, SUM(rare_eggs) AS rare_eggs, " +
+	"SUM(shots) AS shots, SUM(misses) AS misses, MAX(best_streak) AS best_streak"
+
+// TopByPointsNetwork returns the top N players on network with their points,
+// pigeons and eggs summed over every channel. Channel is left empty.
+func (r *PlayerRepositoryImpl) TopByPointsNetwork(ctx context.Context, network string, limit int) ([]*Player, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	var players []*Player
+	err := r.db.DB.WithContext(ctx).
+		Model(&Player{}).
+		Select(totalsColumns).
+		Where("network = ?", network).
+		Group("network, name").
+		Order("points DESC").
+		Order("count DESC").
+		Order("name ASC").
+		Limit(limit).
+		Scan(&players).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
+
+// GetPlayerTotals returns name's points, pigeons and eggs summed over every
+// channel of network, or nil if they have never played there
+func (r *PlayerRepositoryImpl) GetPlayerTotals(ctx context.Context, network, name string) (*Player, error) {
+	name = canonicalName(name)
+
+	var players []*Player
+	err := r.db.DB.WithContext(ctx).
+		Model(&Player{}).
+		Select(totalsColumns).
+		Where("network = ? AND name = ?", network, name).
+		Group("network, name").
+		Scan(&players).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(players) == 0 {
+		return nil, nil
+	}
+
+	return players[0], nil
+}
+
 func (r *PlayerRepositoryImpl) GetEggs(ctx context.Context, network, channel, name string) (int, error) {
 	name = canonicalName(name)
 
@@ -124,44 +263,15 @@ func (r *PlayerRepositoryImpl) GetEggs(ctx context.Context, network, channel, na
 }
 
 func (r *PlayerRepositoryImpl) AddEggs(ctx context.Context, network, channel, name string, delta int) (int, error) {
-	name = canonicalName(name)
-
 	if delta <= 0 {
 		return r.GetEggs(ctx, network, channel, name)
 	}
 
-	// Ensure player exists (create minimal row if not found)
-	var p Player
-	err := r.db.DB.WithContext(ctx).
-		Where("name = ? AND channel = ? AND network = ?", name, channel, network).
-		First(&p).Error
-
+	p, err := r.increment(ctx, network, channel, name, Player{Eggs: delta}, streakKept)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			p = Player{
-				ID:      uuid.New().String(),
-				Name:    name,
-				Channel: channel,
-				Network: network,
-				Eggs:    0,
-			}
-			if err := r.db.DB.WithContext(ctx).Create(&p).Error; err != nil {
-				return 0, err
-			}
-		} else {
-			return 0, err
-		}
-	}
-
-	// Atomic increment (no race conditions)
-	if err := r.db.DB.WithContext(ctx).
-		Model(&Player{}).
-		Where("name = ? AND channel = ? AND network = ?", name, channel, network).
-		UpdateColumn("eggs", gorm.Expr("eggs + ?", delta)).Error; err != nil {
 		return 0, err
 	}
-
-	return r.GetEggs(ctx, network, channel, name)
+	return p.Eggs, nil
 }
 
 func (r *PlayerRepositoryImpl) GetRareEggs(ctx context.Context, network, channel, name string) (int, error) {
@@ -179,23 +289,127 @@ func (r *PlayerRepositoryImpl) GetRareEggs(ctx context.Context, network, channel
 }
 
 func (r *PlayerRepositoryImpl) AddRareEggs(ctx context.Context, network, channel, name string, delta int) (int, error) {
-	name = canonicalName(name)
-
 	if delta <= 0 {
 		return r.GetRareEggs(ctx, network, channel, name)
 	}
 
-	// Ensure player exists (reuse your Upsert logic if needed)
-	// If you already create player rows elsewhere, you can skip this part.
-
-	err := r.db.DB.WithContext(ctx).
-		Model(&Player{}).
-		Where("network = ? AND channel = ? AND name = ?", network, channel, name).
-		UpdateColumn("rare_eggs", gorm.Expr("rare_eggs + ?", delta)).
-		Error
+	p, err := r.increment(ctx, network, channel, name, Player{RareEggs: delta}, streakKept)
 	if err != nil {
 		return 0, err
 	}
+	return p.RareEggs, nil
+}
+
+// MergePlayers adds from's points, pigeons, eggs and shots to into and removes
+// from, in channel or in every channel of network when channel is empty.
+// from's shot history and season results go to into as well.
+func (r *PlayerRepositoryImpl) MergePlayers(ctx context.Context, network, channel, from, into string) error {
+	return r.movePlayer(ctx, network, channel, from, into, true)
+}
 
-	return r.GetRareEggs(ctx, network, channel, name)
+// RenamePlayer gives from's score, shot history and season results to the
+// new name to, in channel or in every channel of network when channel is
+// empty. It fails with ErrPlayerExists where to already has a score.
+func (r *PlayerRepositoryImpl) RenamePlayer(ctx context.Context, network, channel, from, to string) error {
+	return r.movePlayer(ctx, network, channel, from, to, false)
+}
+
+// movePlayer is MergePlayers, or RenamePlayer when merge is false, in one transaction
+func (r *PlayerRepositoryImpl) movePlayer(ctx context.Context, network, channel, from, into string, merge bool) error {
+	from, into = canonicalName(from), canonicalName(into)
+	if from == "" || into == "" || from == into {
+		return fmt.Errorf("cannot move %q to %q", from, into)
+	}
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// from's rows stay locked until the end, so a kill landing on them
+		// meanwhile waits instead of being deleted with them
+		var rows []*Player
+		err := inScope(tx.Clauses(clause.Locking{Strength: "UPDATE"}), network, channel).Where("name = ?", from).Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return ErrPlayerNotFound
+		}
+
+		for _, src := range rows {
+			var dst Player
+			err := tx.Where("network = ? AND channel = ? AND name = ?", network, src.Channel, into).First(&dst).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				err = tx.Model(&Player{}).Where("id = ?", src.ID).Update("name", into).Error
+			case err != nil:
+			case !merge:
+				err = fmt.Errorf("%w: %s in %s", ErrPlayerExists, into, src.Channel)
+			default:
+				// added in SQL so kills landing on into meanwhile are kept
+				err = tx.Model(&Player{}).Where("id = ?", dst.ID).Updates(map[string]any{
+					"points":      gorm.Expr("points + ?", src.Points),
+					"count":       gorm.Expr("count + ?", src.Count),
+					"eggs":        gorm.Expr("eggs + ?", src.Eggs),
+					"rare_eggs":   gorm.Expr("rare_eggs + ?", src.RareEggs),
+					"shots":       gorm.Expr("shots + ?", src.Shots),
+					"misses":      gorm.Expr("misses + ?", src.Misses),
+					"best_streak": gorm.Expr("CASE WHEN best_streak < ? THEN ? ELSE best_streak END", src.BestStreak, src.BestStreak),
+				}).Error
+				if err == nil {
+					err = tx.Unscoped().Where("id = ?", src.ID).Delete(&Player{}).Error
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		where, args := scopeSQL(network, channel, from)
+		if err := tx.Exec("UPDATE shot_event SET name = ? WHERE "+where, append([]any{into}, args...)...).Error; err != nil {
+			return err
+		}
+		// where both finished the same season, into keeps its own result
+		err = tx.Exec("UPDATE season_result SET name = ? WHERE "+where+
+			" AND season_id NOT IN (SELECT season_id FROM season_result WHERE name = ?)",
+			append(append([]any{into}, args...), into)...).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM season_result WHERE "+where, args...).Error
+	})
+}
+
+// DeletePlayer removes name's score and shot history in channel, or in every
+// channel of network when channel is empty. Archived season results are kept.
+func (r *PlayerRepositoryImpl) DeletePlayer(ctx context.Context, network, channel, name string) error {
+	name = canonicalName(name)
+
+	return r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := inScope(tx.Unscoped(), network, channel).Where("name = ?", name).Delete(&Player{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPlayerNotFound
+		}
+
+		where, args := scopeSQL(network, channel, name)
+		return tx.Exec("DELETE FROM shot_event WHERE "+where, args...).Error
+	})
+}
+
+// inScope limits q to network and, unless channel is empty, to channel
+func inScope(q *gorm.DB, network, channel string) *gorm.DB {
+	q = q.Where("network = ?", network)
+	if channel != "" {
+		q = q.Where("channel = ?", channel)
+	}
+	return q
+}
+
+// scopeSQL is the WHERE clause selecting name's rows in channel, or in every
+// channel of network when channel is empty, for tables without a model here
+func scopeSQL(network, channel, name string) (string, []any) {
+	if channel == "" {
+		return "network = ? AND name = ?", []any{network, name}
+	}
+	return "network = ? AND channel = ? AND name = ?", []any{network, channel, name}
 }
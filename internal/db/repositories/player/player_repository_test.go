@@ -2,10 +2,12 @@ package player
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
 	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -15,6 +17,10 @@ func setupTestDB(t *testing.T) (*db.DB, func()) {
 	t.Helper()
 
 	cfg := config.LoadConfigOrPanic()
+	if cfg.DBConfig.Driver == db.DriverSQLite {
+		// DBDRIVER=sqlite runs against a fresh database, no server needed
+		return dbtest.SQLite(t), func() {}
+	}
 	database := db.NewDatabase(cfg.DBConfig)
 
 	// Get underlying sql.DB to close later
@@ -185,6 +191,46 @@ func TestPlayerRepository_TopByPoints(t *testing.T) {
 	})
 }
 
+func TestPlayerRepository_NetworkTotals(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPlayerRepository(database)
+	ctx := context.Background()
+
+	players := []*Player{
+		{Name: "alice", Network: "testnet", Channel: "#one", Points: 100, Count: 5},
+		{Name: "alice", Network: "testnet", Channel: "#two", Points: 50, Count: 2},
+		{Name: "bob", Network: "testnet", Channel: "#one", Points: 120, Count: 6},
+		{Name: "alice", Network: "othernet", Channel: "#one", Points: 999, Count: 99}, // different network
+	}
+	for _, p := range players {
+		require.NoError(t, repo.UpsertPlayer(ctx, p))
+	}
+
+	t.Run("TopByPointsNetwork sums every channel", func(t *testing.T) {
+		result, err := repo.TopByPointsNetwork(ctx, "testnet", 5)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "alice", result[0].Name)
+		assert.Equal(t, 150, result[0].Points)
+		assert.Equal(t, 7, result[0].Count)
+		assert.Equal(t, "bob", result[1].Name)
+	})
+
+	t.Run("GetPlayerTotals", func(t *testing.T) {
+		totals, err := repo.GetPlayerTotals(ctx, "testnet", "Alice")
+		require.NoError(t, err)
+		require.NotNil(t, totals)
+		assert.Equal(t, 150, totals.Points)
+		assert.Equal(t, 7, totals.Count)
+
+		totals, err = repo.GetPlayerTotals(ctx, "testnet", "nobody")
+		require.NoError(t, err)
+		assert.Nil(t, totals)
+	})
+}
+
 func TestPlayerRepository_Eggs(t *testing.T) {
 	database, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -286,6 +332,192 @@ func TestPlayerRepository_RareEggs(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 7, eggs)
 	})
+
+	t.Run("CollectEggs adds both counts and keeps the score", func(t *testing.T) {
+		_, err := repo.AwardKill(ctx, "testnet", "#test", "collector", 10)
+		require.NoError(t, err)
+
+		p, err := repo.CollectEggs(ctx, "testnet", "#test", "Collector", 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.Eggs)
+		assert.Equal(t, 1, p.RareEggs)
+		assert.Equal(t, 10, p.Points)
+		assert.Equal(t, 1, p.Streak)
+	})
+}
+
+func TestPlayerRepository_AwardKill(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPlayerRepository(database)
+	ctx := context.Background()
+
+	t.Run("concurrent kills all count", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.AwardKill(ctx, "testnet", "#test", "Racer", 10)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		players, err := repo.GetAllPlayers(ctx, "testnet", "#test")
+		require.NoError(t, err)
+		require.Len(t, players, 1, "one row however the shots interleave")
+		assert.Equal(t, "racer", players[0].Name)
+		assert.Equal(t, 200, players[0].Points)
+		assert.Equal(t, 20, players[0].Count)
+		assert.Equal(t, 20, players[0].Shots)
+	})
+
+	t.Run("misses break the streak", func(t *testing.T) {
+		p, err := repo.AwardKill(ctx, "testnet", "#test", "streaky", 5)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.Streak)
+
+		p, err = repo.AwardKill(ctx, "testnet", "#test", "streaky", 5)
+		require.NoError(t, err)
+		assert.Equal(t, 2, p.Streak)
+		assert.Equal(t, 2, p.BestStreak)
+
+		p, err = repo.RecordMiss(ctx, "testnet", "#test", "streaky")
+		require.NoError(t, err)
+		assert.Equal(t, 0, p.Streak)
+		assert.Equal(t, 2, p.BestStreak)
+		assert.Equal(t, 3, p.Shots)
+		assert.Equal(t, 1, p.Misses)
+		assert.Equal(t, 10, p.Points)
+	})
+
+	t.Run("bonus points keep the streak", func(t *testing.T) {
+		p, err := repo.AddPoints(ctx, "testnet", "#test", "streaky", 500)
+		require.NoError(t, err)
+		assert.Equal(t, 510, p.Points)
+		assert.Equal(t, 2, p.Count)
+		assert.Equal(t, 3, p.Shots)
+
+		p, err = repo.AddPoints(ctx, "testnet", "#test", "newcomer", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "newcomer", p.Name)
+		assert.NotEmpty(t, p.ID)
+	})
+}
+
+func TestPlayerRepository_MergeRenameDelete(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPlayerRepository(database)
+	ctx := context.Background()
+
+	for _, p := range []*Player{
+		{Name: "alice", Network: "testnet", Channel: "#a", Points: 100, Count: 10, Eggs: 3, RareEggs: 1, BestStreak: 2},
+		{Name: "alice_", Network: "testnet", Channel: "#a", Points: 40, Count: 4, Eggs: 2, BestStreak: 5},
+		{Name: "alice_", Network: "testnet", Channel: "#b", Points: 7, Count: 1},
+		{Name: "bob", Network: "testnet", Channel: "#a", Points: 1},
+	} {
+		require.NoError(t, repo.UpsertPlayer(ctx, p))
+	}
+
+	t.Run("merge sums into the target in one channel", func(t *testing.T) {
+		require.NoError(t, repo.MergePlayers(ctx, "testnet", "#a", "Alice_", "alice"))
+
+		players, err := repo.GetAllPlayers(ctx, "testnet", "#a")
+		require.NoError(t, err)
+		require.Len(t, players, 2)
+		for _, p := range players {
+			if p.Name == "alice" {
+				assert.Equal(t, 140, p.Points)
+				assert.Equal(t, 14, p.Count)
+				assert.Equal(t, 5, p.Eggs)
+				assert.Equal(t, 1, p.RareEggs)
+				assert.Equal(t, 5, p.BestStreak)
+			}
+		}
+
+		// other channels are left alone
+		players, err = repo.GetAllPlayers(ctx, "testnet", "#b")
+		require.NoError(t, err)
+		require.Len(t, players, 1)
+		assert.Equal(t, "alice_", players[0].Name)
+	})
+
+	t.Run("rename refuses a name with a score", func(t *testing.T) {
+		err := repo.RenamePlayer(ctx, "testnet", "#a", "bob", "alice")
+		assert.ErrorIs(t, err, ErrPlayerExists)
+	})
+
+	t.Run("rename across the network", func(t *testing.T) {
+		require.NoError(t, repo.RenamePlayer(ctx, "testnet", "", "alice_", "carol"))
+
+		players, err := repo.GetAllPlayers(ctx, "testnet", "#b")
+		require.NoError(t, err)
+		require.Len(t, players, 1)
+		assert.Equal(t, "carol", players[0].Name)
+		assert.Equal(t, 7, players[0].Points)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.DeletePlayer(ctx, "testnet", "#a", "BOB"))
+
+		players, err := repo.GetAllPlayers(ctx, "testnet", "#a")
+		require.NoError(t, err)
+		require.Len(t, players, 1)
+		assert.Equal(t, "alice", players[0].Name)
+	})
+
+	t.Run("unknown player", func(t *testing.T) {
+		assert.ErrorIs(t, repo.DeletePlayer(ctx, "testnet", "#a", "bob"), ErrPlayerNotFound)
+		assert.ErrorIs(t, repo.MergePlayers(ctx, "testnet", "#a", "nobody", "alice"), ErrPlayerNotFound)
+	})
+}
+
+func TestPlayerRepository_MergeDuringKills(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPlayerRepository(database)
+	ctx := context.Background()
+
+	require.NoError(t, repo.UpsertPlayer(ctx, &Player{Name: "dave", Network: "testnet", Channel: "#a", Points: 100, Count: 10, BestStreak: 3}))
+	require.NoError(t, repo.UpsertPlayer(ctx, &Player{Name: "dave_", Network: "testnet", Channel: "#a", Points: 50, Count: 5, BestStreak: 4}))
+
+	// kills land on both names while they are merged; none may be lost,
+	// though the ones on dave_ after the merge start a new row
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_, err := repo.AwardKill(ctx, "testnet", "#a", name, 10)
+			assert.NoError(t, err)
+		}([]string{"dave", "dave_"}[i%2])
+		if i == 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, repo.MergePlayers(ctx, "testnet", "#a", "dave_", "dave"))
+			}()
+		}
+	}
+	wg.Wait()
+
+	players, err := repo.GetAllPlayers(ctx, "testnet", "#a")
+	require.NoError(t, err)
+	var points, count int
+	for _, p := range players {
+		points += p.Points
+		count += p.Count
+		if p.Name == "dave" {
+			assert.GreaterOrEqual(t, p.BestStreak, 4)
+		}
+	}
+	assert.Equal(t, 100+50+20*10, points)
+	assert.Equal(t, 10+5+20, count)
 }
 
 func TestPlayerRepository_GetPlayerByID(t *testing.T) {
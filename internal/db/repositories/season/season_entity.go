@@ -0,0 +1,43 @@
+package season
+
+import "time"
+
+// Season is one scoring period of a channel (or score pool)
+type Season struct {
+	ID        string     `gorm:"column:id;type:varchar(255);primaryKey" json:"id"`
+	Network   string     `gorm:"column:network;type:text;not null" json:"network"`
+	Channel   string     `gorm:"column:channel;type:text;not null" json:"channel"`
+	Number    int        `gorm:"column:number;type:int;not null" json:"number"`
+	StartedAt time.Time  `gorm:"column:started_at;not null" json:"started_at"`
+	EndsAt    time.Time  `gorm:"column:ends_at;not null" json:"ends_at"`
+	EndedAt   *time.Time `gorm:"column:ended_at" json:"ended_at"` // nil while the season is running
+}
+
+// set table name
+func (Season) TableName() string {
+	return "season"
+}
+
+// SeasonResult is a player's final standing in a season
+type SeasonResult struct {
+	ID       string `gorm:"column:id;type:varchar(255);primaryKey" json:"id"`
+	SeasonID string `gorm:"column:season_id;type:varchar(255);not null" json:"season_id"`
+	Network  string `gorm:"column:network;type:text;not null" json:"network"`
+	Channel  string `gorm:"column:channel;type:text;not null" json:"channel"`
+	Name     string `gorm:"column:name;type:text;not null" json:"name"`
+	Rank     int    `gorm:"column:rank;type:int;not null" json:"rank"`
+	Points   int    `gorm:"column:points;type:int;not null" json:"points"`
+	Count    int    `gorm:"column:count;type:int;not null" json:"count"`
+	Eggs     int    `gorm:"column:eggs;type:int;not null" json:"eggs"`
+	RareEggs int    `gorm:"column:rare_eggs;type:int;not null" json:"rare_eggs"`
+	Title    string `gorm:"column:title;type:text;not null" json:"title"` // level reached
+	Badge    string `gorm:"column:badge;type:text;not null" json:"badge"` // medal for the top three
+
+	// filled in by GetBadges
+	SeasonNumber int `gorm:"column:season_number;->" json:"season_number,omitempty"`
+}
+
+// set table name
+func (SeasonResult) TableName() string {
+	return "season_result"
+}
@@ -0,0 +1,224 @@
+//go:generate mockgen -destination=mocks/mock_season_repository.go -package=mocks github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season SeasonRepository
+package season
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSeasonEnded is returned by EndSeason when the season was already ended, e.g. by another game in the same score pool
+var ErrSeasonEnded = errors.New("season already ended")
+
+type SeasonRepository interface {
+	CurrentSeason(ctx context.Context, network, channel string) (*Season, error)
+	GetSeason(ctx context.Context, network, channel string, number int) (*Season, error)
+	StartSeason(ctx context.Context, network, channel string, startedAt, endsAt time.Time) (*Season, error)
+	EndSeason(ctx context.Context, s *Season, results []*SeasonResult, keepPercent int, endedAt, nextEndsAt time.Time) (*Season, error)
+	GetResults(ctx context.Context, seasonID string, limit int) ([]*SeasonResult, error)
+	GetBadges(ctx context.Context, network, channel, name string) ([]*SeasonResult, error)
+}
+
+type SeasonRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewSeasonRepository(db *db.DB) SeasonRepository {
+	return &SeasonRepositoryImpl{
+		db: db,
+	}
+}
+
+// CurrentSeason returns the running season, or nil if none was started
+func (r *SeasonRepositoryImpl) CurrentSeason(ctx context.Context, network, channel string) (*Season, error) {
+	var s Season
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ? AND ended_at IS NULL", network, channel).
+		Order("number DESC").
+		First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetSeason returns season number, or nil if there is no such season
+func (r *SeasonRepositoryImpl) GetSeason(ctx context.Context, network, channel string, number int) (*Season, error) {
+	var s Season
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ? AND number = ?", network, channel, number).
+		First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// StartSeason starts the next season unless one is already running, in
+// which case that one is returned
+func (r *SeasonRepositoryImpl) StartSeason(ctx context.Context, network, channel string, startedAt, endsAt time.Time) (*Season, error) {
+	var started *Season
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		started, err = startSeason(tx, network, channel, startedAt, endsAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+func startSeason(tx *gorm.DB, network, channel string, startedAt, endsAt time.Time) (*Season, error) {
+	var running Season
+	err := tx.Where("network = ? AND channel = ? AND ended_at IS NULL", network, channel).
+		Order("number DESC").
+		First(&running).Error
+	if err == nil {
+		return &running, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var last int
+	err = tx.Model(&Season{}).
+		Where("network = ? AND channel = ?", network, channel).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Season{
+		ID:        uuid.New().String(),
+		Network:   network,
+		Channel:   channel,
+		Number:    last + 1,
+		StartedAt: startedAt.UTC(),
+		EndsAt:    endsAt.UTC(),
+	}
+	// a concurrent start wins the unique (network, channel, number) index
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		err = tx.Where("network = ? AND channel = ? AND number = ?", network, channel, s.Number).First(&running).Error
+		if err != nil {
+			return nil, err
+		}
+		return &running, nil
+	}
+	return s, nil
+}
+
+// EndSeason archives results, shrinks the season's player scores to
+// keepPercent percent (0 resets them) and starts the next season, all in one
+// transaction. It returns the new season.
+func (r *SeasonRepositoryImpl) EndSeason(ctx context.Context, s *Season, results []*SeasonResult, keepPercent int, endedAt, nextEndsAt time.Time) (*Season, error) {
+	keepPercent = min(max(keepPercent, 0), 100)
+	endedAt = endedAt.UTC()
+
+	var next *Season
+	err := r.db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Season{}).
+			Where("id = ? AND ended_at IS NULL", s.ID).
+			Update("ended_at", endedAt)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrSeasonEnded
+		}
+
+		for _, result := range results {
+			result.ID = uuid.New().String()
+			result.SeasonID = s.ID
+			result.Network = s.Network
+			result.Channel = s.Channel
+		}
+		if len(results) > 0 {
+			if err := tx.CreateInBatches(results, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		decay := func(column string) clause.Expr {
+			return gorm.Expr(column+" * ? / 100", keepPercent)
+		}
+		err := tx.Model(&player.Player{}).
+			Where("network = ? AND channel = ?", s.Network, s.Channel).
+			UpdateColumns(map[string]any{
+				"points":    decay("points"),
+				"count":     decay("count"),
+				"eggs":      decay("eggs"),
+				"rare_eggs": decay("rare_eggs"),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		next, err = startSeason(tx, s.Network, s.Channel, endedAt, nextEndsAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ended := endedAt
+	s.EndedAt = &ended
+	return next, nil
+}
+
+// GetResults returns a season's final standings, best first
+func (r *SeasonRepositoryImpl) GetResults(ctx context.Context, seasonID string, limit int) ([]*SeasonResult, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	var results []*SeasonResult
+	err := r.db.DB.WithContext(ctx).
+		Where("season_id = ?", seasonID).
+		Order("rank ASC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetBadges returns the seasons where name earned a badge, oldest first,
+// with SeasonNumber filled in
+func (r *SeasonRepositoryImpl) GetBadges(ctx context.Context, network, channel, name string) ([]*SeasonResult, error) {
+	var results []*SeasonResult
+	err := r.db.DB.WithContext(ctx).
+		Table("season_result").
+		Select("season_result.*, season.number AS season_number").
+		Joins("JOIN season ON season.id = season_result.season_id").
+		Where("season_result.network = ? AND season_result.channel = ? AND season_result.name = ? AND season_result.badge <> ''",
+			network, channel, strings.ToLower(strings.TrimSpace(name))).
+		Order("season.number ASC").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
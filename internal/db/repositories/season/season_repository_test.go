@@ -0,0 +1,114 @@
+package season
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) (*db.DB, func()) {
+	t.Helper()
+
+	cfg := config.LoadConfigOrPanic()
+	if cfg.DBConfig.Driver == db.DriverSQLite {
+		// DBDRIVER=sqlite runs against a fresh database, no server needed
+		return dbtest.SQLite(t), func() {}
+	}
+	database := db.NewDatabase(cfg.DBConfig)
+
+	sqlDB, err := database.DB.DB()
+	require.NoError(t, err)
+
+	truncate := func() {
+		database.DB.Exec("TRUNCATE TABLE season_result, season")
+		database.DB.Exec("DELETE FROM player WHERE network = 'seasonnet'")
+	}
+	cleanup := func() {
+		truncate()
+		sqlDB.Close()
+	}
+
+	truncate()
+
+	return database, cleanup
+}
+
+func TestSeasonRepository(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSeasonRepository(database)
+	players := player.NewPlayerRepository(database)
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, players.UpsertPlayer(ctx, &player.Player{Name: "alice", Network: "seasonnet", Channel: "#test", Points: 100, Count: 10}))
+	_, err := players.AddEggs(ctx, "seasonnet", "#test", "alice", 5)
+	require.NoError(t, err)
+
+	t.Run("no season yet", func(t *testing.T) {
+		s, err := repo.CurrentSeason(ctx, "seasonnet", "#test")
+		require.NoError(t, err)
+		assert.Nil(t, s)
+	})
+
+	var first *Season
+	t.Run("start is idempotent", func(t *testing.T) {
+		var err error
+		first, err = repo.StartSeason(ctx, "seasonnet", "#test", start, start.AddDate(0, 1, 0))
+		require.NoError(t, err)
+		assert.Equal(t, 1, first.Number)
+
+		again, err := repo.StartSeason(ctx, "seasonnet", "#test", start, start.AddDate(0, 1, 0))
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, again.ID)
+	})
+
+	t.Run("end archives, decays and starts the next season", func(t *testing.T) {
+		end := start.AddDate(0, 1, 0)
+		results := []*SeasonResult{{Name: "alice", Rank: 1, Points: 100, Count: 10, Eggs: 5, Title: "Pigeon Hunter", Badge: "🥇"}}
+
+		next, err := repo.EndSeason(ctx, first, results, 50, end, end.AddDate(0, 1, 0))
+		require.NoError(t, err)
+		assert.Equal(t, 2, next.Number)
+		assert.NotNil(t, first.EndedAt)
+
+		_, err = repo.EndSeason(ctx, first, nil, 50, end, end.AddDate(0, 1, 0))
+		assert.ErrorIs(t, err, ErrSeasonEnded)
+
+		current, err := repo.CurrentSeason(ctx, "seasonnet", "#test")
+		require.NoError(t, err)
+		assert.Equal(t, next.ID, current.ID)
+
+		rows, err := players.GetAllPlayers(ctx, "seasonnet", "#test")
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, 50, rows[0].Points)
+		assert.Equal(t, 5, rows[0].Count)
+		assert.Equal(t, 2, rows[0].Eggs)
+	})
+
+	t.Run("results and badges", func(t *testing.T) {
+		s, err := repo.GetSeason(ctx, "seasonnet", "#test", 1)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+
+		results, err := repo.GetResults(ctx, s.ID, 5)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Pigeon Hunter", results[0].Title)
+
+		badges, err := repo.GetBadges(ctx, "seasonnet", "#test", "Alice")
+		require.NoError(t, err)
+		require.Len(t, badges, 1)
+		assert.Equal(t, "🥇", badges[0].Badge)
+		assert.Equal(t, 1, badges[0].SeasonNumber)
+	})
+}
@@ -0,0 +1,19 @@
+package settings
+
+import "time"
+
+// ChannelSetting is one runtime game setting for a channel
+type ChannelSetting struct {
+	ID        string    `gorm:"column:id;type:varchar(255);primaryKey" json:"id"`
+	Network   string    `gorm:"column:network;type:text;not null" json:"network"`
+	Channel   string    `gorm:"column:channel;type:text;not null" json:"channel"`
+	Name      string    `gorm:"column:name;type:text;not null" json:"name"`
+	Value     string    `gorm:"column:value;type:text;not null" json:"value"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// set table name
+func (ChannelSetting) TableName() string {
+	return "channel_settings"
+}
@@ -0,0 +1,65 @@
+//go:generate mockgen -destination=mocks/mock_settings_repository.go -package=mocks github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings SettingsRepository
+package settings
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+type SettingsRepository interface {
+	GetSettings(ctx context.Context, network, channel string) (map[string]string, error)
+	SetSetting(ctx context.Context, network, channel, name, value string) error
+}
+
+type SettingsRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewSettingsRepository(db *db.DB) SettingsRepository {
+	return &SettingsRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetSettings returns the stored settings for a channel keyed by name
+func (r *SettingsRepositoryImpl) GetSettings(ctx context.Context, network, channel string) (map[string]string, error) {
+	var rows []*ChannelSetting
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", network, channel).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(rows))
+	for _, row := range rows {
+		values[row.Name] = row.Value
+	}
+	return values, nil
+}
+
+// SetSetting inserts or replaces a single setting
+func (r *SettingsRepositoryImpl) SetSetting(ctx context.Context, network, channel, name, value string) error {
+	now := time.Now()
+	row := ChannelSetting{
+		ID:        uuid.New().String(),
+		Network:   network,
+		Channel:   channel,
+		Name:      strings.ToLower(strings.TrimSpace(name)),
+		Value:     value,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "network"}, {Name: "channel"}, {Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).
+		Create(&row).Error
+}
@@ -0,0 +1,67 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) (*db.DB, func()) {
+	t.Helper()
+
+	cfg := config.LoadConfigOrPanic()
+	if cfg.DBConfig.Driver == db.DriverSQLite {
+		// DBDRIVER=sqlite runs against a fresh database, no server needed
+		return dbtest.SQLite(t), func() {}
+	}
+	database := db.NewDatabase(cfg.DBConfig)
+
+	sqlDB, err := database.DB.DB()
+	require.NoError(t, err)
+
+	cleanup := func() {
+		database.DB.Exec("TRUNCATE TABLE channel_settings")
+		sqlDB.Close()
+	}
+
+	database.DB.Exec("TRUNCATE TABLE channel_settings")
+
+	return database, cleanup
+}
+
+func TestSettingsRepository_SetAndGet(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSettingsRepository(database)
+	ctx := context.Background()
+
+	t.Run("empty channel has no settings", func(t *testing.T) {
+		values, err := repo.GetSettings(ctx, "testnet", "#empty")
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("set then overwrite", func(t *testing.T) {
+		require.NoError(t, repo.SetSetting(ctx, "testnet", "#testchan", "shoot_cooldown", "5"))
+		require.NoError(t, repo.SetSetting(ctx, "testnet", "#testchan", "Shoot_Cooldown", "30"))
+		require.NoError(t, repo.SetSetting(ctx, "testnet", "#testchan", "max_attempts", "3"))
+
+		values, err := repo.GetSettings(ctx, "testnet", "#testchan")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"shoot_cooldown": "30", "max_attempts": "3"}, values)
+	})
+
+	t.Run("settings are scoped per channel", func(t *testing.T) {
+		require.NoError(t, repo.SetSetting(ctx, "testnet", "#other", "max_attempts", "7"))
+
+		values, err := repo.GetSettings(ctx, "testnet", "#other")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"max_attempts": "7"}, values)
+	})
+}
@@ -0,0 +1,31 @@
+package shot
+
+import "time"
+
+// rare egg outcomes of a shot
+const (
+	RareEggNone      = ""
+	RareEggCracked   = "cracked"
+	RareEggCollected = "collected"
+)
+
+// ShotEvent is one !shoot attempt
+type ShotEvent struct {
+	ID          string    `gorm:"column:id;type:varchar(255);primaryKey" json:"id"`
+	Network     string    `gorm:"column:network;type:text;not null" json:"network"`
+	Channel     string    `gorm:"column:channel;type:text;not null" json:"channel"`
+	Name        string    `gorm:"column:name;type:text;not null" json:"name"`
+	SpawnID     int64     `gorm:"column:spawn_id;not null" json:"spawn_id"`
+	PigeonType  string    `gorm:"column:pigeon_type;type:text;not null" json:"pigeon_type"` // empty when there was no pigeon
+	Hit         bool      `gorm:"column:hit;not null" json:"hit"`
+	Points      int       `gorm:"column:points;type:int;not null" json:"points"`
+	EggsGained  int       `gorm:"column:eggs_gained;type:int;not null" json:"eggs_gained"`
+	EggsCracked int       `gorm:"column:eggs_cracked;type:int;not null" json:"eggs_cracked"`
+	RareEgg     string    `gorm:"column:rare_egg;type:text;not null" json:"rare_egg"`
+	ShotAt      time.Time `gorm:"column:shot_at;not null" json:"shot_at"`
+}
+
+// set table name
+func (ShotEvent) TableName() string {
+	return "shot_event"
+}
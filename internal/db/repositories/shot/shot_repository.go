@@ -0,0 +1,140 @@
+//go:generate mockgen -destination=mocks/mock_shot_repository.go -package=mocks github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot ShotEventRepository
+package shot
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/google/uuid"
+)
+
+const insertBatchSize = 500
+
+type ShotEventRepository interface {
+	AddShots(ctx context.Context, events []*ShotEvent) error
+	GetShots(ctx context.Context, network, channel string, since time.Time) ([]*ShotEvent, error)
+	TopByPointsBetween(ctx context.Context, network, channel string, from, to time.Time, limit int) ([]*Score, error)
+	KillsByType(ctx context.Context, network, channel, name string) ([]*Kills, error)
+}
+
+// Score is a player's totals over the shots in a time window
+type Score struct {
+	Name     string `gorm:"column:name"`
+	Points   int    `gorm:"column:points"`
+	Hits     int    `gorm:"column:hits"`
+	Shots    int    `gorm:"column:shots"` // at a pigeon, as player.Shots counts them
+	Eggs     int    `gorm:"column:eggs"`
+	RareEggs int    `gorm:"column:rare_eggs"`
+}
+
+// Kills is how many pigeons of one type a player has shot
+type Kills struct {
+	PigeonType string `gorm:"column:pigeon_type"`
+	Count      int    `gorm:"column:count"`
+}
+
+type ShotEventRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewShotEventRepository(db *db.DB) ShotEventRepository {
+	return &ShotEventRepositoryImpl{
+		db: db,
+	}
+}
+
+// AddShots inserts events, giving each an ID if it has none
+func (r *ShotEventRepositoryImpl) AddShots(ctx context.Context, events []*ShotEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	for _, e := range events {
+		if e.ID == "" {
+			e.ID = uuid.New().String()
+		}
+		// shot_at has no time zone, so it is always stored as UTC
+		e.ShotAt = e.ShotAt.UTC()
+	}
+
+	return r.db.DB.WithContext(ctx).CreateInBatches(events, insertBatchSize).Error
+}
+
+// GetShots returns the shots in channel since a time, oldest first
+func (r *ShotEventRepositoryImpl) GetShots(ctx context.Context, network, channel string, since time.Time) ([]*ShotEvent, error) {
+	var events []*ShotEvent
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ? AND shot_at >= ?", network, channel, since.UTC()).
+		Order("shot_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// TopByPointsBetween ranks players by the points they scored from from up to
+// (not including) to. An empty channel ranks the whole network. Players who
+// scored nothing in the window are left out. Shots fired when there was no
+// pigeon are not counted, matching the totals !stats shows.
+func (r *ShotEventRepositoryImpl) TopByPointsBetween(ctx context.Context, network, channel string, from, to time.Time, limit int) ([]*Score, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	query := r.db.DB.WithContext(ctx).
+		Model(&ShotEvent{}).
+		Select("name, SUM(points) AS points, "+
+			"SUM(CASE WHEN hit THEN 1 ELSE 0 END) AS hits, "+
+			"COUNT(*) AS shots, "+
+			"SUM(eggs_gained) AS eggs, "+
+			"SUM(CASE WHEN rare_egg = ? THEN 1 ELSE 0 END) AS rare_eggs", RareEggCollected).
+		Where("network = ? AND shot_at >= ? AND shot_at < ? AND pigeon_type <> ''", network, from.UTC(), to.UTC())
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+
+	var scores []*Score
+	err := query.
+		Group("name").
+		Having("SUM(points) > 0").
+		Order("points DESC").
+		Order("hits DESC").
+		Order("name ASC").
+		Limit(limit).
+		Scan(&scores).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// KillsByType counts the pigeons name has shot by type, most shot first. An
+// empty channel counts the whole network.
+func (r *ShotEventRepositoryImpl) KillsByType(ctx context.Context, network, channel, name string) ([]*Kills, error) {
+	query := r.db.DB.WithContext(ctx).
+		Model(&ShotEvent{}).
+		Select("pigeon_type, COUNT(*) AS count").
+		Where("network = ? AND name = ? AND hit", network, strings.ToLower(name))
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+
+	var kills []*Kills
+	err := query.
+		Group("pigeon_type").
+		Order("count DESC").
+		Order("pigeon_type ASC").
+		Scan(&kills).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return kills, nil
+}
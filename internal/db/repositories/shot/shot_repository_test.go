@@ -0,0 +1,97 @@
+package shot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) (*db.DB, func()) {
+	t.Helper()
+
+	cfg := config.LoadConfigOrPanic()
+	if cfg.DBConfig.Driver == db.DriverSQLite {
+		// DBDRIVER=sqlite runs against a fresh database, no server needed
+		return dbtest.SQLite(t), func() {}
+	}
+	database := db.NewDatabase(cfg.DBConfig)
+
+	sqlDB, err := database.DB.DB()
+	require.NoError(t, err)
+
+	cleanup := func() {
+		database.DB.Exec("TRUNCATE TABLE shot_event")
+		sqlDB.Close()
+	}
+
+	database.DB.Exec("TRUNCATE TABLE shot_event")
+
+	return database, cleanup
+}
+
+func TestShotEventRepository(t *testing.T) {
+	database, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewShotEventRepository(database)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	events := []*ShotEvent{
+		{Network: "testnet", Channel: "#test", Name: "alice", SpawnID: 1, PigeonType: "boss", Hit: true, Points: 50, ShotAt: now.Add(-48 * time.Hour)},
+		{Network: "testnet", Channel: "#test", Name: "bob", SpawnID: 2, PigeonType: "white", ShotAt: now.Add(-time.Hour)},
+		{Network: "testnet", Channel: "#test", Name: "alice", SpawnID: 2, PigeonType: "white", Hit: true, Points: 20, EggsGained: 1, RareEgg: RareEggCollected, ShotAt: now},
+		{Network: "testnet", Channel: "#other", Name: "alice", SpawnID: 1, ShotAt: now},
+	}
+
+	t.Run("AddShots assigns IDs", func(t *testing.T) {
+		require.NoError(t, repo.AddShots(ctx, events))
+		for _, e := range events {
+			assert.NotEmpty(t, e.ID)
+		}
+		require.NoError(t, repo.AddShots(ctx, nil))
+	})
+
+	t.Run("GetShots filters by channel and time", func(t *testing.T) {
+		got, err := repo.GetShots(ctx, "testnet", "#test", now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "bob", got[0].Name)
+		assert.Equal(t, "alice", got[1].Name)
+		assert.Equal(t, RareEggCollected, got[1].RareEgg)
+		assert.Equal(t, 1, got[1].EggsGained)
+	})
+
+	t.Run("TopByPointsBetween sums the window", func(t *testing.T) {
+		scores, err := repo.TopByPointsBetween(ctx, "testnet", "#test", now.Add(-24*time.Hour), now.Add(time.Second), 5)
+		require.NoError(t, err)
+		require.Len(t, scores, 1, "bob only missed")
+		assert.Equal(t, Score{Name: "alice", Points: 20, Hits: 1, Shots: 1, Eggs: 1, RareEggs: 1}, *scores[0])
+
+		scores, err = repo.TopByPointsBetween(ctx, "testnet", "", now.Add(-72*time.Hour), now.Add(time.Second), 5)
+		require.NoError(t, err)
+		require.Len(t, scores, 1)
+		assert.Equal(t, 70, scores[0].Points)
+		assert.Equal(t, 2, scores[0].Shots, "the shot with no pigeon is not counted")
+	})
+
+	t.Run("KillsByType counts hits per pigeon", func(t *testing.T) {
+		kills, err := repo.KillsByType(ctx, "testnet", "#test", "Alice")
+		require.NoError(t, err)
+		assert.Equal(t, []*Kills{{PigeonType: "boss", Count: 1}, {PigeonType: "white", Count: 1}}, kills)
+
+		kills, err = repo.KillsByType(ctx, "testnet", "", "alice")
+		require.NoError(t, err)
+		assert.Len(t, kills, 2, "the miss in #other is not a kill")
+
+		kills, err = repo.KillsByType(ctx, "testnet", "#test", "bob")
+		require.NoError(t, err)
+		assert.Empty(t, kills)
+	})
+}
@@ -2,22 +2,56 @@ package healthcheck
 
 import (
 	"context"
-	"github.com/MyelinBots/pigeonbot-go/config"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
 )
 
-// Healthcheck that starts http server
+// Healthcheck that starts http server; it shuts down when ctx is done
 func StartHealthcheck(ctx context.Context, cfg config.AppConfig) {
+	server := &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.Port),
+		Handler: HealthCheckHandler(),
+	}
+
 	// start http server
 	go func() {
-		port := strconv.Itoa(cfg.Port)
-		err := http.ListenAndServe(":"+port, HealthCheckHandler())
-		if err != nil {
-			panic(err)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Healthcheck server error: %s\n", err.Error())
 		}
 	}()
 
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			fmt.Printf("Healthcheck shutdown error: %s\n", err.Error())
+		}
+	}()
+}
+
+// Running reports whether a bot answers the healthcheck on cfg.Port of this host
+func Running(ctx context.Context, cfg config.AppConfig) bool {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	url := "http://127.0.0.1:" + strconv.Itoa(cfg.Port) + "/health"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
 }
 
 func HealthCheckHandler() http.HandlerFunc {
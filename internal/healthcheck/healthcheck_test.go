@@ -1,12 +1,18 @@
 package healthcheck_test
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/MyelinBots/pigeonbot-go/config"
 	"github.com/MyelinBots/pigeonbot-go/internal/healthcheck"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHealthCheckHandler(t *testing.T) {
@@ -80,3 +86,61 @@ func TestHealthCheckHandler_Integration(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 }
+
+func TestStartHealthcheck(t *testing.T) {
+	t.Run("port in use does not panic", func(t *testing.T) {
+		l, err := net.Listen("tcp", ":0")
+		require.NoError(t, err)
+		defer l.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		healthcheck.StartHealthcheck(ctx, config.AppConfig{Port: l.Addr().(*net.TCPAddr).Port})
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		l, err := net.Listen("tcp", ":0")
+		require.NoError(t, err)
+		port := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		healthcheck.StartHealthcheck(ctx, config.AppConfig{Port: port})
+		url := "http://127.0.0.1:" + strconv.Itoa(port) + "/health"
+
+		assert.Eventually(t, func() bool {
+			resp, err := http.Get(url)
+			if err != nil {
+				return false
+			}
+			resp.Body.Close()
+			return resp.StatusCode == http.StatusOK
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+
+		assert.Eventually(t, func() bool {
+			_, err := http.Get(url)
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestRunning(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	cfg := config.AppConfig{Port: l.Addr().(*net.TCPAddr).Port}
+	l.Close()
+
+	assert.False(t, healthcheck.Running(context.Background(), cfg), "nothing is listening yet")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	healthcheck.StartHealthcheck(ctx, cfg)
+
+	assert.Eventually(t, func() bool {
+		return healthcheck.Running(context.Background(), cfg)
+	}, time.Second, 10*time.Millisecond)
+}
@@ -2,8 +2,7 @@ package actions
 
 import (
 	"fmt"
-	"math/rand"
-	"time"
+	rand "math/rand/v2"
 )
 
 // Action represents an action with associated data
@@ -16,7 +15,15 @@ type Action struct {
 
 // Act performs the action and returns the formatted result
 func (a *Action) Act(name string) string {
-	rand.Seed(time.Now().UnixNano())
-	item := a.Items[rand.Intn(len(a.Items))]
-	return fmt.Sprintf(a.Format, name, a.Action, item)
+	return a.ActWith(rand.IntN, name)
+}
+
+// ActWith is Act with the item picked by intN, which must return a value in [0, n)
+func (a *Action) ActWith(intN func(n int) int, name string) string {
+	return fmt.Sprintf(a.Format, name, a.Action, a.PickItem(intN))
+}
+
+// PickItem picks one of the action's items with intN
+func (a *Action) PickItem(intN func(n int) int) string {
+	return a.Items[intN(len(a.Items))]
 }
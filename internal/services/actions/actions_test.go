@@ -129,6 +129,22 @@ func TestAction_Act_SingleItem(t *testing.T) {
 	assert.Contains(t, result, "landed")
 }
 
+func TestAction_ActWith(t *testing.T) {
+	action := actions.Action{
+		Action:      "stole",
+		Items:       []string{"tv", "wallet", "food"},
+		Format:      "A %s pigeon %s your %s",
+		ActionPoint: 10,
+	}
+
+	result := action.ActWith(func(n int) int {
+		assert.Equal(t, 3, n)
+		return 1
+	}, "boss")
+
+	assert.Equal(t, "A boss pigeon stole your wallet", result)
+}
+
 func TestAction_Struct(t *testing.T) {
 	action := actions.Action{
 		Action:      "test-action",
@@ -6,29 +6,73 @@ import (
 
 	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/game"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/permissions"
 	irc "github.com/fluffle/goirc/client"
 )
 
 type CommandController interface {
 	HandleCommand(ctx context.Context, line *irc.Line) error
-	AddCommand(command string, handler func(ctx context.Context, args ...string) error)
+	AddCommand(command string, handler func(ctx context.Context, args ...string) error, opts ...CommandOption)
+}
+
+// PermissionChecker resolves the level of the user sending a command
+type PermissionChecker interface {
+	LevelOf(channel, nick, ident, host string) permissions.Level
+}
+
+type command struct {
+	handler func(ctx context.Context, args ...string) error
+	level   permissions.Level
+}
+
+// CommandOption customises a command registered with AddCommand
+type CommandOption func(*command)
+
+// Require restricts a command to users with at least level
+func Require(level permissions.Level) CommandOption {
+	return func(c *command) {
+		c.level = level
+	}
 }
 
 type CommandControllerImpl struct {
-	game     *game.Game
-	commands map[string]func(ctx context.Context, args ...string) error
+	game        *game.Game
+	commands    map[string]command
+	permissions PermissionChecker
 }
 
-func NewCommandController(gameinstance *game.Game) CommandController {
-	return &CommandControllerImpl{
+// NewCommandController builds a controller for a game. Without a
+// PermissionChecker only commands open to anyone can be used.
+func NewCommandController(gameinstance *game.Game, checker ...PermissionChecker) CommandController {
+	c := &CommandControllerImpl{
 		game:     gameinstance,
-		commands: make(map[string]func(ctx context.Context, args ...string) error),
+		commands: make(map[string]command),
+	}
+	if len(checker) > 0 {
+		c.permissions = checker[0]
 	}
+	return c
 }
 
-func (c *CommandControllerImpl) AddCommand(command string, handler func(ctx context.Context, args ...string) error) {
+func (c *CommandControllerImpl) AddCommand(name string, handler func(ctx context.Context, args ...string) error, opts ...CommandOption) {
+	cmd := command{handler: handler, level: permissions.Anyone}
+	for _, opt := range opts {
+		opt(&cmd)
+	}
+
 	// normalize key ให้เป็น lower-case ไว้ก่อน จะได้ match ง่าย
-	c.commands[strings.ToLower(strings.TrimSpace(command))] = handler
+	c.commands[strings.ToLower(strings.TrimSpace(name))] = cmd
+}
+
+// allowed reports whether the sender of line may run cmd
+func (c *CommandControllerImpl) allowed(cmd command, line *irc.Line) bool {
+	if cmd.level <= permissions.Anyone {
+		return true
+	}
+	if c.permissions == nil {
+		return false
+	}
+	return c.permissions.LevelOf(line.Args[0], line.Nick, line.Ident, line.Host) >= cmd.level
 }
 
 func (c *CommandControllerImpl) HandleCommand(ctx context.Context, line *irc.Line) error {
@@ -47,12 +91,19 @@ func (c *CommandControllerImpl) HandleCommand(ctx context.Context, line *irc.Lin
 		return nil
 	}
 
-	cmd := strings.ToLower(parts[0])
-	handler, ok := c.commands[cmd]
+	name := strings.ToLower(parts[0])
+	cmd, ok := c.commands[name]
 	if !ok {
 		return nil
 	}
 
+	if !c.allowed(cmd, line) {
+		if c.game != nil {
+			c.game.Styled().Notice(line.Nick, c.game.Msg(game.MsgNotAllowedPrefix+cmd.level.String(), name))
+		}
+		return nil
+	}
+
 	// ใส่ nick เข้า context (มาตรฐาน)
 	ctx2 := context_manager.WithNick(ctx, line.Nick)
 
@@ -62,5 +113,5 @@ func (c *CommandControllerImpl) HandleCommand(ctx context.Context, line *irc.Lin
 		args = parts[1:]
 	}
 
-	return handler(ctx2, args...)
+	return cmd.handler(ctx2, args...)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/game"
 	gameMocks "github.com/MyelinBots/pigeonbot-go/internal/services/game/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/permissions"
 	irc "github.com/fluffle/goirc/client"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -274,3 +275,73 @@ func TestCommandController_EmptyArgs(t *testing.T) {
 	err := controller.HandleCommand(context.Background(), line)
 	assert.NoError(t, err)
 }
+
+func TestCommandController_RequireLevel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	playerRepository := playerRepoMocks.NewMockPlayerRepository(ctrl)
+	ircClient := gameMocks.NewMockIRCClient(ctrl)
+	gameInstance := game.NewGame(config.GameConfig{Interval: 3}, ircClient, playerRepository, "network", "channel")
+
+	checker := permissions.NewChecker(config.PermissionsConfig{Owners: []string{"*!*@owner.example"}})
+	checker.Names("channel", "@opuser voiced")
+	controller := commands.NewCommandController(gameInstance, checker)
+
+	calls := 0
+	handler := func(ctx context.Context, args ...string) error {
+		calls++
+		return nil
+	}
+	controller.AddCommand("!opcmd", handler, commands.Require(permissions.Op))
+	controller.AddCommand("!ownercmd", handler, commands.Require(permissions.Owner))
+
+	t.Run("op may use op command", func(t *testing.T) {
+		line := &irc.Line{Args: []string{"channel", "!opcmd"}, Nick: "opuser"}
+		assert.NoError(t, controller.HandleCommand(context.Background(), line))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("owner mask outranks channel modes", func(t *testing.T) {
+		line := &irc.Line{Args: []string{"channel", "!ownercmd"}, Nick: "boss", Ident: "b", Host: "owner.example"}
+		assert.NoError(t, controller.HandleCommand(context.Background(), line))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("denied user gets a notice", func(t *testing.T) {
+		ircClient.EXPECT().Notice("voiced", "Sorry, !opcmd is only for operators").Times(1)
+
+		line := &irc.Line{Args: []string{"channel", "!opcmd"}, Nick: "voiced"}
+		assert.NoError(t, controller.HandleCommand(context.Background(), line))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("op cannot use owner command", func(t *testing.T) {
+		ircClient.EXPECT().Notice("opuser", "Sorry, !ownercmd is only for bot owners").Times(1)
+
+		line := &irc.Line{Args: []string{"channel", "!ownercmd"}, Nick: "opuser"}
+		assert.NoError(t, controller.HandleCommand(context.Background(), line))
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestCommandController_RequireLevel_NoChecker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ircClient := gameMocks.NewMockIRCClient(ctrl)
+	gameInstance := game.NewGame(config.GameConfig{Interval: 3}, ircClient, playerRepoMocks.NewMockPlayerRepository(ctrl), "network", "channel")
+	controller := commands.NewCommandController(gameInstance)
+
+	called := false
+	controller.AddCommand("!opcmd", func(ctx context.Context, args ...string) error {
+		called = true
+		return nil
+	}, commands.Require(permissions.Op))
+
+	ircClient.EXPECT().Notice("someone", gomock.Any()).Times(1)
+
+	line := &irc.Line{Args: []string{"channel", "!opcmd"}, Nick: "someone"}
+	assert.NoError(t, controller.HandleCommand(context.Background(), line))
+	assert.False(t, called)
+}
@@ -24,3 +24,26 @@ func GetNickContext(ctx context.Context) string {
 	}
 	return nick
 }
+
+// Identity is who a user plays as: their services account when they are
+// logged in, else the nick they were first seen with this session
+type Identity struct {
+	Name    string
+	Account bool // Name is a services account
+}
+
+type identityKeyType struct{}
+
+var identityKey = identityKeyType{}
+
+// WithIdentity stores the sender's identity in context (name normalized to lowercase)
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	id.Name = strings.ToLower(id.Name)
+	return context.WithValue(ctx, identityKey, id)
+}
+
+// GetIdentityContext returns the sender's identity from context, or the zero Identity if missing
+func GetIdentityContext(ctx context.Context) Identity {
+	id, _ := ctx.Value(identityKey).(Identity)
+	return id
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+)
+
+// MergePlayers adds from's score to into's in this game's pool, in the
+// database and in memory, and removes from
+func (g *Game) MergePlayers(ctx context.Context, from, into string) error {
+	return g.editPlayers(ctx, func() error {
+		return g.playerRepository.MergePlayers(ctx, g.network, g.scope(), from, into)
+	}, func(players []*player.Player) []*player.Player {
+		src, dst := findIn(players, from), findIn(players, into)
+		switch {
+		case src == nil:
+		case dst == nil:
+			src.Name = canonicalPlayerName(into)
+		default:
+			dst.Points += src.Points
+			dst.Count += src.Count
+			dst.Shots += src.Shots
+			dst.Misses += src.Misses
+			dst.BestStreak = max(dst.BestStreak, src.BestStreak)
+			dst.Eggs += src.Eggs
+			dst.RareEggs += src.RareEggs
+			players = slices.DeleteFunc(players, func(p *player.Player) bool { return p == src })
+		}
+		return players
+	})
+}
+
+// RenamePlayer gives from's score in this game's pool to the new name to
+func (g *Game) RenamePlayer(ctx context.Context, from, to string) error {
+	return g.editPlayers(ctx, func() error {
+		return g.playerRepository.RenamePlayer(ctx, g.network, g.scope(), from, to)
+	}, func(players []*player.Player) []*player.Player {
+		if p := findIn(players, from); p != nil {
+			p.Name = canonicalPlayerName(to)
+		}
+		return players
+	})
+}
+
+// DeletePlayer removes name's score from this game's pool
+func (g *Game) DeletePlayer(ctx context.Context, name string) error {
+	return g.editPlayers(ctx, func() error {
+		return g.playerRepository.DeletePlayer(ctx, g.network, g.scope(), name)
+	}, func(players []*player.Player) []*player.Player {
+		target := findIn(players, name)
+		return slices.DeleteFunc(players, func(p *player.Player) bool { return p == target })
+	})
+}
+
+// editPlayers writes the scores waiting for the database, runs store against
+// it and, if it succeeds, applies the same change to the roster with apply.
+// The roster is locked throughout so no cached score lands in between.
+func (g *Game) editPlayers(ctx context.Context, store func() error, apply func([]*player.Player) []*player.Player) error {
+	g.syncPlayers(ctx)
+	// waiting shots go to the names they were made under
+	if err := g.writeWaiting(ctx); err != nil {
+		return err
+	}
+
+	roster := g.roster()
+	roster.Lock()
+	defer roster.Unlock()
+
+	if err := store(); err != nil {
+		return err
+	}
+	roster.players = apply(roster.players)
+	return nil
+}
+
+// findIn returns the player called name, or nil
+func findIn(players []*player.Player, name string) *player.Player {
+	name = canonicalPlayerName(name)
+	for _, p := range players {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// HandlePlayer fixes up player records: !player merge|rename|delete ...
+func (g *Game) HandlePlayer(ctx context.Context, args ...string) error {
+	if len(args) == 0 {
+		g.irc().Privmsg(g.channel, g.msg(msgPlayerUsage))
+		return nil
+	}
+
+	var (
+		err  error
+		done string
+	)
+	switch sub := strings.ToLower(args[0]); {
+	case sub == "merge" && len(args) == 3:
+		err = g.MergePlayers(ctx, args[1], args[2])
+		done = g.msg(msgPlayerMerged, args[1], args[2])
+	case sub == "rename" && len(args) == 3:
+		err = g.RenamePlayer(ctx, args[1], args[2])
+		done = g.msg(msgPlayerRenamed, args[1], args[2])
+	case sub == "delete" && len(args) == 2:
+		err = g.DeletePlayer(ctx, args[1])
+		done = g.msg(msgPlayerDeleted, args[1])
+	default:
+		g.irc().Privmsg(g.channel, g.msg(msgPlayerUsage))
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, player2.ErrPlayerNotFound):
+		g.irc().Privmsg(g.channel, g.msg(msgPlayerNotFound, args[1]))
+		return nil
+	case errors.Is(err, player2.ErrPlayerExists):
+		g.irc().Privmsg(g.channel, g.msg(msgPlayerExists, args[2]))
+		return nil
+	case err != nil:
+		g.irc().Privmsg(g.channel, g.msg(msgPlayerError))
+		return err
+	}
+	g.irc().Privmsg(g.channel, done)
+	return nil
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAdminGame returns a game whose roster holds alice and bob
+func newAdminGame() (*Game, *mockIRCClientForTest, *mockPlayerRepositoryForTest) {
+	client := &mockIRCClientForTest{}
+	repo := newMockPlayerRepoForTest()
+	g := NewGame(config.GameConfig{}, client, repo, "testnet", "#test")
+	g.roster().loaded = true
+	g.roster().players = []*player.Player{
+		{Name: "alice", Points: 100, Count: 10, Shots: 12, Misses: 2, BestStreak: 4},
+		{Name: "bob", Points: 50, Count: 5, Shots: 9, Misses: 4, BestStreak: 6},
+	}
+	return g, client, repo
+}
+
+func TestHandlePlayer_Merge(t *testing.T) {
+	g, client, repo := newAdminGame()
+
+	require.NoError(t, g.HandlePlayer(context.Background(), "merge", "Bob", "alice"))
+
+	assert.Equal(t, []string{"merge #test Bob alice"}, repo.edits)
+	require.Len(t, g.roster().players, 1)
+	assert.Equal(t, &player.Player{Name: "alice", Points: 150, Count: 15, Shots: 21, Misses: 6, BestStreak: 6}, g.roster().players[0])
+	assert.Equal(t, []string{"⚙️ Merged Bob into alice"}, client.messages)
+}
+
+func TestHandlePlayer_MergeIntoNewName(t *testing.T) {
+	g, _, _ := newAdminGame()
+
+	require.NoError(t, g.MergePlayers(context.Background(), "bob", "carol"))
+
+	names := []string{g.roster().players[0].Name, g.roster().players[1].Name}
+	assert.Equal(t, []string{"alice", "carol"}, names)
+}
+
+func TestHandlePlayer_Rename(t *testing.T) {
+	g, client, repo := newAdminGame()
+
+	require.NoError(t, g.HandlePlayer(context.Background(), "rename", "alice", "Alicia"))
+
+	assert.Equal(t, []string{"rename #test alice Alicia"}, repo.edits)
+	assert.Equal(t, "alicia", g.roster().players[0].Name)
+	assert.Equal(t, 100, g.roster().players[0].Points)
+	assert.Equal(t, []string{"⚙️ Renamed alice to Alicia"}, client.messages)
+}
+
+func TestHandlePlayer_Delete(t *testing.T) {
+	g, client, repo := newAdminGame()
+
+	require.NoError(t, g.HandlePlayer(context.Background(), "delete", "alice"))
+
+	assert.Equal(t, []string{"delete #test alice"}, repo.edits)
+	require.Len(t, g.roster().players, 1)
+	assert.Equal(t, "bob", g.roster().players[0].Name)
+	assert.Equal(t, []string{"⚙️ Deleted alice"}, client.messages)
+}
+
+func TestHandlePlayer_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		args []string
+		want string
+	}{
+		{"not found", player2.ErrPlayerNotFound, []string{"delete", "zed"}, "⚙️ zed has no score here"},
+		{"exists", player2.ErrPlayerExists, []string{"rename", "alice", "bob"}, "⚙️ bob already has a score here, use !player merge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, client, repo := newAdminGame()
+			repo.editErr = tt.err
+
+			require.NoError(t, g.HandlePlayer(context.Background(), tt.args...))
+
+			assert.Equal(t, []string{tt.want}, client.messages)
+			assert.Len(t, g.roster().players, 2, "a failed edit must leave the roster alone")
+		})
+	}
+
+	t.Run("database error", func(t *testing.T) {
+		g, client, repo := newAdminGame()
+		repo.editErr = errors.New("boom")
+
+		assert.Error(t, g.HandlePlayer(context.Background(), "merge", "bob", "alice"))
+		assert.Equal(t, []string{"⚙️ Error updating players"}, client.messages)
+		assert.Equal(t, 50, g.roster().players[1].Points)
+	})
+}
+
+func TestHandlePlayer_Usage(t *testing.T) {
+	for _, args := range [][]string{nil, {"merge", "bob"}, {"delete"}, {"ban", "bob"}} {
+		g, client, repo := newAdminGame()
+
+		require.NoError(t, g.HandlePlayer(context.Background(), args...))
+
+		assert.Equal(t, []string{g.msg(msgPlayerUsage)}, client.messages, "args %v", args)
+		assert.Empty(t, repo.edits)
+	}
+}
@@ -0,0 +1,14 @@
+package game
+
+import "time"
+
+// Clock abstracts time so the game loop can be driven without real sleeps
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
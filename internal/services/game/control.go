@@ -0,0 +1,234 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/pigeon"
+)
+
+// ErrUnknownPigeon is returned by ForceSpawn for a type not in the catalogue
+var ErrUnknownPigeon = errors.New("unknown pigeon type")
+
+// runState is the game loop's lifecycle; the zero value is stopped
+type runState struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	paused bool
+	// halted is set by !stop; the bot leaves a halted game stopped when it
+	// rejoins the channel, until the game is launched again by hand
+	halted bool
+}
+
+// begin marks the loop as running; it returns false if it already is
+func (g *Game) begin(ctx context.Context) (context.Context, bool) {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	if g.run.cancel != nil {
+		return nil, false
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	g.run.cancel = cancel
+	g.run.halted = false
+	g.run.done = make(chan struct{})
+	return runCtx, true
+}
+
+// end marks the loop as stopped
+func (g *Game) end() {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	g.run.cancel()
+	close(g.run.done)
+	g.run.cancel = nil
+	g.run.done = nil
+}
+
+// Launch starts the game loop in the background unless it is already running
+func (g *Game) Launch(ctx context.Context) bool {
+	runCtx, ok := g.begin(ctx)
+	if !ok {
+		return false
+	}
+	go func() {
+		defer g.end()
+		g.loop(runCtx)
+	}()
+	return true
+}
+
+// Stop ends the game loop and waits for it to exit. It returns false if it was not running.
+func (g *Game) Stop() bool {
+	g.runMu.Lock()
+	cancel, done := g.run.cancel, g.run.done
+	g.runMu.Unlock()
+
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	<-done
+	return true
+}
+
+// Halt stops the game loop and keeps it stopped across rejoins until the next
+// Launch. It returns false if the loop was not running.
+func (g *Game) Halt() bool {
+	g.runMu.Lock()
+	g.run.halted = true
+	g.runMu.Unlock()
+
+	return g.Stop()
+}
+
+// Halted reports whether an operator stopped the game
+func (g *Game) Halted() bool {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	return g.run.halted
+}
+
+// Shutdown stops the game loop and writes the scores still waiting for the
+// database, retrying until ctx is done
+func (g *Game) Shutdown(ctx context.Context) error {
+	g.Stop()
+	return g.flushScores(ctx)
+}
+
+// flushScores writes every waiting score change, retrying until ctx is done
+func (g *Game) flushScores(ctx context.Context) error {
+	if g.saves == nil {
+		return nil
+	}
+	return g.saves.Flush(ctx)
+}
+
+// writeWaiting tries once to write every waiting score change, so the
+// database has the roster's totals
+func (g *Game) writeWaiting(ctx context.Context) error {
+	if g.saves == nil {
+		return nil
+	}
+	return g.saves.flush(ctx)
+}
+
+// writeWaitingIn tries once to write the waiting score changes of every game
+// on the network storing its players under scope, or of every game when
+// scope is empty
+func (g *Game) writeWaitingIn(ctx context.Context, scope string) error {
+	games := []*Game{g}
+	if g.networkGames != nil {
+		games = append(games, g.networkGames()...)
+	}
+
+	var errs []error
+	for _, other := range games {
+		if scope != "" && other.scope() != scope {
+			continue
+		}
+		if err := other.writeWaiting(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Running reports whether the game loop is running
+func (g *Game) Running() bool {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	return g.run.cancel != nil
+}
+
+// Pause stops new spawns without ending the loop; shooting still works
+func (g *Game) Pause() {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	g.run.paused = true
+}
+
+// Resume undoes Pause
+func (g *Game) Resume() {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	g.run.paused = false
+}
+
+// Paused reports whether spawns are paused
+func (g *Game) Paused() bool {
+	g.runMu.Lock()
+	defer g.runMu.Unlock()
+
+	return g.run.paused
+}
+
+// ForceSpawn spawns a pigeon now, replacing any active one. An empty
+// pigeonType picks one by weight like a scheduled spawn.
+func (g *Game) ForceSpawn(ctx context.Context, pigeonType string) error {
+	var p *pigeon.Pigeon
+	if strings.TrimSpace(pigeonType) != "" {
+		if p = g.findPigeon(pigeonType); p == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownPigeon, pigeonType)
+		}
+	}
+
+	g.roster().Lock()
+	g.activePigeon.Lock()
+	defer g.activePigeon.Unlock()
+	defer g.roster().Unlock()
+
+	g.spawn(p)
+	return nil
+}
+
+// pigeonTypes lists this game's pigeon types for messages
+func (g *Game) pigeonTypes() string {
+	pigeons := g.pigeons
+	if len(pigeons) == 0 {
+		pigeons = pigeon.PredefinedPigeons()
+	}
+	types := make([]string, 0, len(pigeons))
+	for _, p := range pigeons {
+		types = append(types, p.Type)
+	}
+	return strings.Join(types, ", ")
+}
+
+func (g *Game) HandleStop(ctx context.Context, args ...string) error {
+	if !g.Halt() {
+		g.irc().Privmsg(g.channel, g.msg(msgGameNotRunning))
+		return nil
+	}
+	g.irc().Privmsg(g.channel, g.msg(msgGameStopped))
+	return nil
+}
+
+func (g *Game) HandlePause(ctx context.Context, args ...string) error {
+	g.Pause()
+	g.irc().Privmsg(g.channel, g.msg(msgGamePaused))
+	return nil
+}
+
+func (g *Game) HandleResume(ctx context.Context, args ...string) error {
+	g.Resume()
+	g.irc().Privmsg(g.channel, g.msg(msgGameResumed))
+	return nil
+}
+
+// HandleForceSpawn spawns a pigeon now: !forcespawn [type]
+func (g *Game) HandleForceSpawn(ctx context.Context, args ...string) error {
+	err := g.ForceSpawn(ctx, strings.Join(args, " "))
+	if errors.Is(err, ErrUnknownPigeon) {
+		g.irc().Privmsg(g.channel, g.msg(msgSpawnUnknown, g.pigeonTypes()))
+		return nil
+	}
+	return err
+}
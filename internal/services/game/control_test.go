@@ -0,0 +1,115 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_LaunchStop(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock, 0, 0, 0)
+
+	assert.False(t, g.Running())
+	assert.False(t, g.Stop(), "stopping a stopped game is a no-op")
+
+	ctx := context.Background()
+	require.True(t, g.Launch(ctx))
+	assert.False(t, g.Launch(ctx), "a second launch must not start another loop")
+	g.Start(ctx) // returns at once while the loop runs
+
+	// first spawn, then the loop waits on the clock
+	<-clock.waits
+	assert.True(t, g.Running())
+
+	assert.True(t, g.Stop())
+	assert.False(t, g.Running())
+	assert.False(t, g.Halted(), "only an operator's stop halts the game")
+	assert.Equal(t, []string{"A cartel member pigeon has landed on your car"}, client.messages)
+}
+
+func TestGame_PauseSkipsSpawns(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock)
+
+	g.Pause()
+	assert.True(t, g.Paused())
+
+	require.True(t, g.Launch(context.Background()))
+	<-clock.waits
+	clock.Advance(30 * time.Second)
+	<-clock.waits
+	g.Stop()
+
+	assert.Empty(t, client.messages, "no rolls are scripted, so a spawn would fail the test")
+
+	g.Resume()
+	assert.False(t, g.Paused())
+}
+
+func TestGame_ForceSpawn(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	ctx := context.Background()
+
+	// random spawn: pigeon roll, action roll, item roll
+	g := newScriptedGame(t, client, clock, 2, 0, 0)
+	require.NoError(t, g.ForceSpawn(ctx, ""))
+	assert.Equal(t, "white", g.activePigeon.activePigeon.Type)
+	assert.Equal(t, int64(1), g.CurrentSpawnID())
+
+	// named spawn replaces the active pigeon and skips the pigeon roll
+	g.rand = &scriptedRand{t: t, rolls: []int{0, 1}}
+	require.NoError(t, g.ForceSpawn(ctx, "BOSS"))
+	assert.Equal(t, "boss", g.activePigeon.activePigeon.Type)
+	assert.Equal(t, int64(2), g.CurrentSpawnID())
+	assert.Equal(t, "A boss pigeon has landed on your bed", client.messages[len(client.messages)-1])
+
+	err := g.ForceSpawn(ctx, "dodo")
+	assert.ErrorIs(t, err, ErrUnknownPigeon)
+	assert.Equal(t, "boss", g.activePigeon.activePigeon.Type)
+}
+
+func TestGame_ControlHandlers(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock)
+	ctx := context.Background()
+
+	require.NoError(t, g.HandleStop(ctx))
+	assert.Equal(t, "🕊️ The game is not running", client.messages[len(client.messages)-1])
+	assert.True(t, g.Halted(), "!stop is remembered even when the loop was not running")
+
+	require.NoError(t, g.HandlePause(ctx))
+	assert.True(t, g.Paused())
+	require.NoError(t, g.HandleResume(ctx))
+	assert.False(t, g.Paused())
+
+	require.NoError(t, g.HandleForceSpawn(ctx, "golden", "eagle"))
+	assert.Equal(t, "🕊️ Unknown pigeon type, try: cartel member, boss, white", client.messages[len(client.messages)-1])
+}
+
+func TestGame_HaltUntilLaunched(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock, 0, 0, 0, 0, 0, 0)
+
+	ctx := context.Background()
+	require.True(t, g.Launch(ctx))
+	<-clock.waits
+
+	assert.True(t, g.Halt())
+	assert.True(t, g.Halted())
+	assert.False(t, g.Running())
+
+	// launching by hand undoes the halt
+	require.True(t, g.Launch(ctx))
+	<-clock.waits
+	assert.False(t, g.Halted())
+	assert.True(t, g.Stop())
+}
@@ -2,6 +2,7 @@ package game
 
 import "time"
 
+// Defaults; channels can override them with !set
 const (
 	maxAttemptsPerSpawn = 10 // allow 10 shots per spawn
 	shootCooldown       = 5 * time.Second
@@ -14,14 +15,15 @@ type shotState struct {
 	CooldownUntil time.Time
 }
 
-// canShoot allows up to maxAttemptsPerSpawn shots per spawn.
+// canShoot allows up to Settings.MaxAttemptsPerSpawn shots per spawn.
 // Attempts reset instantly when a new pigeon spawns (spawnID changes).
 // No time-based cooldown at all.
 func (g *Game) canShoot(name string, spawnID int64) (bool, time.Duration) {
 	g.shotMu.Lock()
 	defer g.shotMu.Unlock()
 
-	now := time.Now()
+	now := g.now()
+	settings := g.Settings()
 
 	st := g.lastShot[name]
 	if st == nil {
@@ -36,14 +38,14 @@ func (g *Game) canShoot(name string, spawnID int64) (bool, time.Duration) {
 	}
 
 	if !st.CooldownUntil.IsZero() && now.Before(st.CooldownUntil) {
-		return false, time.Until(st.CooldownUntil)
+		return false, st.CooldownUntil.Sub(now)
 	}
 
 	// Allow 5 shots, then start cooldown but DO NOT require new spawn
-	if st.Attempts >= maxAttemptsPerSpawn {
+	if st.Attempts >= settings.MaxAttemptsPerSpawn {
 		st.Attempts = 0 // reset attempts after cooldown starts
-		st.CooldownUntil = now.Add(shootCooldown)
-		return false, shootCooldown
+		st.CooldownUntil = now.Add(settings.ShootCooldown)
+		return false, settings.ShootCooldown
 	}
 
 	st.Attempts++
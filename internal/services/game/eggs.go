@@ -2,120 +2,106 @@ package game
 
 import (
 	"context"
-	"fmt"
-	rand "math/rand/v2"
-	"strings"
 )
 
-// Base eggs by pigeon type
-// cartel member = 1, white = 2, boss = 5
-func baseEggsByType(pigeonType string) int {
-	switch strings.ToLower(strings.TrimSpace(pigeonType)) {
-	case "cartel member":
-		return 1
-	case "white":
-		return 2
-	case "boss":
-		return 5
-	default:
+// baseEggsByType rolls how many eggs a mating pair of pigeonType lays,
+// using the yield range from the pigeon catalogue (0 for unknown types)
+func (g *Game) baseEggsByType(pigeonType string) int {
+	p := g.findPigeon(pigeonType)
+	if p == nil || p.Eggs.Max <= 0 {
 		return 0
 	}
+	if p.Eggs.Max <= p.Eggs.Min {
+		return p.Eggs.Min
+	}
+	return p.Eggs.Min + g.rng().IntN(p.Eggs.Max-p.Eggs.Min+1)
 }
 
-// Cracking rules (exactly as requested)
+// eggsAfterCrack applies the catalogue's cracking rules to a fresh clutch.
+// With the default catalogue:
 // cartel member: 1 -> 0
 // white:         2 -> 0 or 1
 // boss:          5 -> 0..4
-func eggsAfterCrack(pigeonType string) (final int, cracked int) {
-	base := baseEggsByType(pigeonType)
-	if base == 0 {
+func (g *Game) eggsAfterCrack(pigeonType string) (final int, cracked int) {
+	base := g.baseEggsByType(pigeonType)
+	if base <= 0 {
 		return 0, 0
 	}
 
-	switch strings.ToLower(strings.TrimSpace(pigeonType)) {
-	case "cartel member":
-		return 0, 1
-
-	case "white":
-		final = rand.IntN(2) // 0..1
-		return final, base - final
-
-	case "boss":
-		final = rand.IntN(5) // 0..4
-		return final, base - final
-
-	default:
+	rules := g.findPigeon(pigeonType).Eggs
+	hi := min(rules.CrackMax, base)
+	lo := min(max(rules.CrackMin, 0), hi)
+	if hi <= 0 {
 		return base, 0
 	}
+
+	cracked = lo + g.rng().IntN(hi-lo+1)
+	return base - cracked, cracked
 }
 
 // HandleMatingEggs is called AFTER a successful shot
 // g.activePigeon must already be locked by the caller
 func (g *Game) HandleMatingEggs(ctx context.Context, shooterName string) (string, error) {
+	msg, _, _, err := g.matingEggs(ctx, shooterName)
+	return msg, err
+}
+
+// matingEggs collects the clutch of a shot mating pair and also returns how
+// many eggs were gained and cracked
+func (g *Game) matingEggs(ctx context.Context, shooterName string) (msg string, final int, cracked int, err error) {
 
 	// Must have an active mating pigeon
 	if g.activePigeon == nil ||
 		g.activePigeon.activePigeon == nil ||
 		!g.activePigeon.IsMating {
-		return "", nil
+		return "", 0, 0, nil
 	}
 
 	pType := g.activePigeon.activePigeon.Type
-	if baseEggsByType(pType) == 0 {
-		return "", nil
+	final, cracked = g.eggsAfterCrack(pType)
+	if final == 0 && cracked == 0 {
+		return "", 0, 0, nil
 	}
 
-	final, cracked := eggsAfterCrack(pType)
-
 	// ✅ canonical name for DB read/write (works for ALL users)
-	dbName := canonicalPlayerName(shooterName)
+	dbName := g.playerName(ctx, shooterName)
 
 	// All eggs cracked
 	if final <= 0 {
-		total, err := g.playerRepository.GetEggs(ctx, g.network, g.channel, dbName)
+		total, _, err := g.eggsOf(ctx, dbName)
 		if err != nil {
-			return "", err
+			return "", 0, cracked, err
 		}
-		return fmt.Sprintf(
-			"🥚💥 Oh no... :( the eggs cracked during the chaos! - no eggs collected ... You now have %s egg(s) in total.",
+		return g.msg(
+			msgEggsAllCracked,
 			fmtNum(total),
-		), nil
+		), 0, cracked, nil
 	}
 
-	// Add eggs
-	total, err := g.playerRepository.AddEggs(
-		ctx,
-		g.network,
-		g.channel,
-		dbName, // ✅ store canonical
-		final,
-	)
+	// Add eggs, written like kills
+	stored, err := g.store(ctx, scoreChange{kind: changeEggs, name: dbName, eggs: final})
 	if err != nil {
-		return "", err
+		return "", 0, cracked, err
 	}
+	total := stored.Eggs
 
 	if cracked > 0 {
-		return fmt.Sprintf(
-			"🥚🐣 Yay!! %s has collected %s egg(s) ... Unfortunately, %s cracked!... You now have %s egg(s) in total.",
+		return g.msg(
+			msgEggsSomeCracked,
 			shooterName, // ✅ display original nick
 			fmtNum(final),
 			fmtNum(cracked),
 			fmtNum(total),
-		), nil
-	}
-
-	rareEggs, err := g.playerRepository.GetRareEggs(ctx, g.network, g.channel, dbName)
-	if err != nil {
-		return "", err
+		), final, cracked, nil
 	}
 
-	return fmt.Sprintf(
-		"%s collected %s egg(s)! Total eggs: %s (Rare egg(s): %s 🌟🥚)",
+	return g.msg(
+		msgEggsCollected,
 		shooterName,
 		fmtNum(final),
 		fmtNum(total),
-		fmtNum(rareEggs),
-	), nil
+		fmtNum(stored.RareEggs),
+	), final, cracked, nil
 }
 
 func (g *Game) EggsAfterShot(ctx context.Context, shooterName string) (string, error) {
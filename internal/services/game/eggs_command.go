@@ -2,7 +2,6 @@ package game
 
 import (
 	"context"
-	"fmt"
 )
 
 func (g *Game) HandleEggs(ctx context.Context, args ...string) error {
@@ -16,28 +15,21 @@ func (g *Game) HandleEggs(ctx context.Context, args ...string) error {
 	}
 
 	if nick == "" {
-		g.ircClient.Privmsg(g.channel, "DEBUG eggs: no nick in ctx and no args")
 		return nil
 	}
 
-	dbName := canonicalPlayerName(nick)
+	dbName := g.playerName(ctx, nick)
 
-	totalEggs, err := g.playerRepository.GetEggs(ctx, g.network, g.channel, dbName)
+	totalEggs, totalRare, err := g.eggsOf(ctx, dbName)
 	if err != nil {
-		g.ircClient.Privmsg(g.channel, fmt.Sprintf("DEBUG eggs: GetEggs err=%v", err))
+		g.irc().Privmsg(g.channel, g.msg(msgEggsError))
 		return err
 	}
 
-	totalRare, err := g.playerRepository.GetRareEggs(ctx, g.network, g.channel, dbName)
-	if err != nil {
-		g.ircClient.Privmsg(g.channel, fmt.Sprintf("DEBUG eggs: GetRareEggs err=%v", err))
-		return err
-	}
-
-	g.ircClient.Privmsg(
+	g.irc().Privmsg(
 		g.channel,
-		fmt.Sprintf(
-			"🥚 %s has %s egg(s) total — including %s rare egg(s) 🌟🥚",
+		g.msg(
+			msgEggsTotal,
 			nick,
 			fmtNum(totalEggs),
 			fmtNum(totalRare),
@@ -45,3 +37,26 @@ func (g *Game) HandleEggs(ctx context.Context, args ...string) error {
 	)
 	return nil
 }
+
+// eggsOf returns name's eggs and rare eggs, counting those still waiting for
+// the database; players not on the roster are read from it
+func (g *Game) eggsOf(ctx context.Context, name string) (eggs, rare int, err error) {
+	roster := g.roster()
+	roster.Lock()
+	p := findIn(roster.players, name)
+	if p != nil {
+		eggs, rare = p.Eggs, p.RareEggs
+	}
+	roster.Unlock()
+	if p != nil {
+		return eggs, rare, nil
+	}
+
+	if eggs, err = g.playerRepository.GetEggs(ctx, g.network, g.scope(), name); err != nil {
+		return 0, 0, err
+	}
+	if rare, err = g.playerRepository.GetRareEggs(ctx, g.network, g.scope(), name); err != nil {
+		return 0, 0, err
+	}
+	return eggs, rare, nil
+}
@@ -29,10 +29,8 @@ func TestHandleShoot_NoPigeon(t *testing.T) {
 		}, nil).
 		Times(1)
 
-	playerRepository.EXPECT().
-		UpsertPlayer(gomock.Any(), gomock.Any()).
-		Return(nil).
-		AnyTimes()
+	// the first pigeon spawns as soon as the game starts
+	allowScoring(playerRepository)
 
 	ircClient := gameMocks.NewMockIRCClient(ctrl)
 	// Expect a message about no pigeon to shoot (among other messages)
@@ -143,40 +141,6 @@ func TestHandleTopN_Error(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
-func TestSavePlayers_Error(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	playerRepository := mocks.NewMockPlayerRepository(ctrl)
-	playerRepository.EggsByKey = make(map[string]int)
-	playerRepository.RareEggsByKey = make(map[string]int)
-
-	playerRepository.EXPECT().
-		GetAllPlayers(gomock.Any(), "network", "channel").
-		Return([]*player.Player{
-			{ID: "1", Name: "player1", Points: 100, Count: 10},
-		}, nil).
-		Times(1)
-
-	expectedErr := assert.AnError
-	playerRepository.EXPECT().
-		UpsertPlayer(gomock.Any(), gomock.Any()).
-		Return(expectedErr).
-		Times(1)
-
-	ircClient := gameMocks.NewMockIRCClient(ctrl)
-	ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
-
-	gameInstance := game.NewGame(config.GameConfig{Interval: 300}, ircClient, playerRepository, "network", "channel")
-
-	ctx := context.Background()
-	go gameInstance.Start(ctx)
-	time.Sleep(50 * time.Millisecond)
-
-	err := gameInstance.SavePlayers(ctx)
-	assert.Equal(t, expectedErr, err)
-}
-
 func TestAddPlayer_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -192,8 +156,8 @@ func TestAddPlayer_Error(t *testing.T) {
 
 	expectedErr := assert.AnError
 	playerRepository.EXPECT().
-		UpsertPlayer(gomock.Any(), gomock.Any()).
-		Return(expectedErr).
+		AddPoints(gomock.Any(), "network", "channel", "newplayer", 0).
+		Return(nil, expectedErr).
 		Times(1)
 
 	ircClient := gameMocks.NewMockIRCClient(ctrl)
@@ -205,7 +169,7 @@ func TestAddPlayer_Error(t *testing.T) {
 	go gameInstance.Start(ctx)
 	time.Sleep(50 * time.Millisecond)
 
-	// FindPlayer should try to add a new player and fail
+	// FindPlayer should try to read the player from the database and fail
 	foundPlayer, err := gameInstance.FindPlayer(ctx, "newplayer")
 	assert.Equal(t, expectedErr, err)
 	assert.Nil(t, foundPlayer)
@@ -3,6 +3,7 @@ package game
 import (
 	"testing"
 
+	"github.com/MyelinBots/pigeonbot-go/internal/services/pigeon"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,27 +72,29 @@ func TestBaseEggsByType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := baseEggsByType(tt.pigeonType)
+			result := (&Game{}).baseEggsByType(tt.pigeonType)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
 func TestEggsAfterCrack_CartelMember(t *testing.T) {
+	g := &Game{}
 	// Cartel member always cracks all eggs (1 -> 0)
 	for i := 0; i < 10; i++ {
-		final, cracked := eggsAfterCrack("cartel member")
+		final, cracked := g.eggsAfterCrack("cartel member")
 		assert.Equal(t, 0, final, "Cartel member should always have 0 eggs")
 		assert.Equal(t, 1, cracked, "Cartel member should always crack 1 egg")
 	}
 }
 
 func TestEggsAfterCrack_White(t *testing.T) {
+	g := &Game{}
 	// White can have 0 or 1 final eggs (from base of 2)
 	results := make(map[int]int)
 
 	for i := 0; i < 100; i++ {
-		final, cracked := eggsAfterCrack("white")
+		final, cracked := g.eggsAfterCrack("white")
 		assert.True(t, final >= 0 && final <= 1,
 			"White pigeon final eggs should be 0 or 1, got %d", final)
 		assert.Equal(t, 2-final, cracked,
@@ -105,11 +108,12 @@ func TestEggsAfterCrack_White(t *testing.T) {
 }
 
 func TestEggsAfterCrack_Boss(t *testing.T) {
+	g := &Game{}
 	// Boss can have 0-4 final eggs (from base of 5)
 	results := make(map[int]int)
 
 	for i := 0; i < 200; i++ {
-		final, cracked := eggsAfterCrack("boss")
+		final, cracked := g.eggsAfterCrack("boss")
 		assert.True(t, final >= 0 && final <= 4,
 			"Boss pigeon final eggs should be 0-4, got %d", final)
 		assert.Equal(t, 5-final, cracked,
@@ -126,32 +130,59 @@ func TestEggsAfterCrack_Boss(t *testing.T) {
 }
 
 func TestEggsAfterCrack_Unknown(t *testing.T) {
-	final, cracked := eggsAfterCrack("unknown")
+	g := &Game{}
+	final, cracked := g.eggsAfterCrack("unknown")
 	assert.Equal(t, 0, final)
 	assert.Equal(t, 0, cracked)
 }
 
 func TestEggsAfterCrack_Empty(t *testing.T) {
-	final, cracked := eggsAfterCrack("")
+	g := &Game{}
+	final, cracked := g.eggsAfterCrack("")
 	assert.Equal(t, 0, final)
 	assert.Equal(t, 0, cracked)
 }
 
 func TestEggsAfterCrack_CaseInsensitive(t *testing.T) {
+	g := &Game{}
 	// Test case insensitivity
 	types := []string{"CARTEL MEMBER", "Cartel Member", "cartel member"}
 	for _, pigeonType := range types {
-		final, cracked := eggsAfterCrack(pigeonType)
+		final, cracked := g.eggsAfterCrack(pigeonType)
 		assert.Equal(t, 0, final)
 		assert.Equal(t, 1, cracked)
 	}
 
 	// Boss case insensitivity
 	for i := 0; i < 20; i++ {
-		final1, _ := eggsAfterCrack("BOSS")
+		final1, _ := g.eggsAfterCrack("BOSS")
 		assert.True(t, final1 >= 0 && final1 <= 4)
 
-		final2, _ := eggsAfterCrack("Boss")
+		final2, _ := g.eggsAfterCrack("Boss")
 		assert.True(t, final2 >= 0 && final2 <= 4)
 	}
 }
+
+func TestEggsAfterCrack_CustomCatalogue(t *testing.T) {
+	g := &Game{pigeons: []*pigeon.Pigeon{
+		{Type: "golden", Weight: 1, Eggs: pigeon.EggRules{Min: 3, Max: 3, CrackMin: 0, CrackMax: 0}},
+		{Type: "fragile", Weight: 1, Eggs: pigeon.EggRules{Min: 2, Max: 4, CrackMin: 5, CrackMax: 9}},
+	}}
+
+	// Never cracks
+	final, cracked := g.eggsAfterCrack("golden")
+	assert.Equal(t, 3, final)
+	assert.Equal(t, 0, cracked)
+
+	// Cracking is clamped to the eggs laid
+	for i := 0; i < 20; i++ {
+		final, cracked := g.eggsAfterCrack("fragile")
+		assert.Equal(t, 0, final)
+		assert.True(t, cracked >= 2 && cracked <= 4, "laid eggs should be within 2..4, got %d", cracked)
+	}
+
+	// Built-in pigeons are not in this catalogue
+	final, cracked = g.eggsAfterCrack("boss")
+	assert.Equal(t, 0, final)
+	assert.Equal(t, 0, cracked)
+}
@@ -4,14 +4,17 @@ package game
 import (
 	"context"
 	"fmt"
-	rand "math/rand/v2"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/MyelinBots/pigeonbot-go/config"
+	alias2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias"
 	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	season2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	settings2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings"
+	shot2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/actions"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/pigeon"
@@ -37,6 +40,7 @@ type ActivePigeon struct {
 type Players struct {
 	sync.Mutex
 	players []*player.Player
+	loaded  bool
 }
 
 type PlayerShootState struct {
@@ -49,6 +53,11 @@ type IRCClient interface {
 	Raw(message string)
 }
 
+// ShotRecorder keeps the shot history; Record must not block
+type ShotRecorder interface {
+	Record(e *shot2.ShotEvent)
+}
+
 type pendingPing struct {
 	nick    string
 	channel string
@@ -59,17 +68,45 @@ type pendingPing struct {
 type Game struct {
 	config           config.GameConfig
 	players          Players
+	pool             *Players // shared roster, nil unless the channel is in a score pool
 	ircClient        IRCClient
+	listings         IRCClient // nil sends listings through ircClient
 	actions          []actions.Action
 	activePigeon     *ActivePigeon
 	pigeons          []*pigeon.Pigeon
 	playerRepository player2.PlayerRepository
 	channel          string
 	network          string
+	clock            Clock
+	rand             Rand
+	scheduler        *scheduler
+
+	runMu sync.Mutex
+	run   runState
+
+	settingsMu         sync.RWMutex
+	settings           *Settings // nil means DefaultSettings
+	settingsRepository settings2.SettingsRepository
+
+	shots          ShotRecorder // nil keeps no history
+	shotRepository shot2.ShotEventRepository
+
+	aliasRepository alias2.AliasRepository // nil plays every nick as itself
+
+	seasonMu         sync.Mutex
+	season           *season2.Season // running season, loaded lazily
+	seasonRepository season2.SeasonRepository
 
 	spawnMu        sync.RWMutex
 	currentSpawnID int64
 
+	messages messages // nil uses builtinMessages
+
+	persistence  Persistence
+	saveDelay    time.Duration
+	saves        *saveDebouncer
+	networkGames func() []*Game // nil when the game runs alone
+
 	lastShot map[string]*shotState
 	shotMu   sync.Mutex
 
@@ -79,22 +116,48 @@ type Game struct {
 }
 
 // NewGame initializes and returns a new Game instance
-func NewGame(cfg config.GameConfig, client IRCClient, repo player2.PlayerRepository, network string, channel string) *Game {
-
-	return &Game{
+func NewGame(cfg config.GameConfig, client IRCClient, repo player2.PlayerRepository, network string, channel string, opts ...Option) *Game {
+	g := &Game{
 		config:           cfg,
 		ircClient:        client,
 		actions:          predefinedActions(),
 		activePigeon:     &ActivePigeon{},
-		pigeons:          pigeon.PredefinedPigeons(),
+		pigeons:          pigeonsFor(cfg, channel),
 		playerRepository: repo,
 		channel:          channel,
 		network:          network,
+		clock:            realClock{},
+		rand:             globalRand{},
+		messages:         newMessages(cfg.Messages.ForChannel(channel)),
 		lastShot:         make(map[string]*shotState),
 
 		// ping state
 		pending: make(map[string]pendingPing),
 	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.scheduler = newScheduler(cfg.ScheduleFor(channel), cfg.Interval, g.clock, g.rand)
+	g.saves = newSaveDebouncer(g, g.persistence, g.saveDelay)
+
+	return g
+}
+
+// pigeonsFor resolves the pigeon catalogue for channel, defaulting to the built-in pigeons
+func pigeonsFor(cfg config.GameConfig, channel string) []*pigeon.Pigeon {
+	if pigeons := pigeon.FromConfig(cfg.Pigeons.ForChannel(channel)); len(pigeons) > 0 {
+		return pigeons
+	}
+	return pigeon.PredefinedPigeons()
+}
+
+// findPigeon looks up a pigeon type in this game's catalogue
+func (g *Game) findPigeon(pigeonType string) *pigeon.Pigeon {
+	if len(g.pigeons) == 0 {
+		return pigeon.Find(pigeon.PredefinedPigeons(), pigeonType)
+	}
+	return pigeon.Find(g.pigeons, pigeonType)
 }
 
 // predefinedActions returns the predefined game actions
@@ -133,34 +196,78 @@ func predefinedActions() []actions.Action {
 	}
 }
 
-// Start begins the game's timer
+// Start runs the game loop until ctx is cancelled or Stop is called; the
+// scheduler decides the wait between spawns. It returns at once if the loop
+// is already running.
 func (g *Game) Start(ctx context.Context) {
+	runCtx, ok := g.begin(ctx)
+	if !ok {
+		return
+	}
+	defer g.end()
+	g.loop(runCtx)
+}
+
+func (g *Game) loop(ctx context.Context) {
 	g.syncPlayers(ctx)
+	if g.saves != nil {
+		var writer sync.WaitGroup
+		writer.Add(1)
+		go func() {
+			defer writer.Done()
+			g.saves.run(ctx)
+		}()
+		defer func() {
+			writer.Wait()
+			// one last try at whatever is still waiting; Shutdown retries the rest
+			if err := g.saves.flush(context.WithoutCancel(ctx)); err != nil {
+				fmt.Printf("Error saving scores: %v\n", err)
+			}
+		}()
+	}
 	for {
+		g.checkSeason(ctx)
+		if !g.scheduler.Quiet() && !g.Paused() {
+			g.ActOnPlayer(ctx)
+		}
+
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			g.ActOnPlayer(ctx)
-			timer := g.config.Interval
-			if timer == 0 {
-				timer = 120 // default to 2 minutes
-			}
-			<-time.After(time.Duration(timer) * time.Second)
+		case <-g.clock.After(g.scheduler.NextDelay()):
 		}
 	}
 }
 
+// NoteActivity tells the scheduler someone talked in the channel
+func (g *Game) NoteActivity() {
+	g.scheduler.NoteActivity()
+}
+
+// syncPlayers loads the channel's players once; after that memory is authoritative
 func (g *Game) syncPlayers(ctx context.Context) {
+	g.roster().Lock()
+	defer g.roster().Unlock()
+	if g.roster().loaded {
+		return
+	}
 
-	players, err := g.playerRepository.GetAllPlayers(ctx, g.network, g.channel)
+	players, err := g.playerRepository.GetAllPlayers(ctx, g.network, g.scope())
 	if err != nil {
 		return
 	}
+	g.roster().loaded = true
 	for _, p := range players {
 		// Use canonical name for consistency (DB should already be lowercase after migration)
 		canonicalName := canonicalPlayerName(p.Name)
-		g.players.players = append(g.players.players, player.NewPlayer(canonicalName, p.Points, p.Count))
+		if findIn(g.roster().players, canonicalName) != nil {
+			continue // cached by a shot that raced the load, and newer
+		}
+		loaded := player.NewPlayer(canonicalName, p.Points, p.Count)
+		loaded.Shots, loaded.Misses = p.Shots, p.Misses
+		loaded.Streak, loaded.BestStreak = p.Streak, p.BestStreak
+		loaded.Eggs, loaded.RareEggs = p.Eggs, p.RareEggs
+		g.roster().players = append(g.roster().players, loaded)
 	}
 
 }
@@ -174,94 +281,96 @@ func (g *Game) NewPigeonSpawn() int64 {
 
 // ActOnPlayer triggers an action on a player
 func (g *Game) ActOnPlayer(ctx context.Context) {
-	g.players.Lock()
+	g.roster().Lock()
 	g.activePigeon.Lock()
 	defer g.activePigeon.Unlock()
-	defer g.players.Unlock()
+	defer g.roster().Unlock()
 
 	if g.activePigeon.activePigeon != nil {
-		aliveFor := time.Since(g.activePigeon.SpawnedAt)
+		aliveFor := g.now().Sub(g.activePigeon.SpawnedAt)
 
-		// pigeon must live at least 60 seconds (adjust as you like)
-		if aliveFor < 60*time.Second {
+		// pigeon must live at least pigeon_lifetime before it can escape
+		if aliveFor < g.Settings().MinPigeonLifetime {
 			return
 		}
 
-		g.ircClient.Privmsg(g.channel, fmt.Sprintf(
-			"🕊️ ~ coo coo ~ the %s pigeon has made a clean escape ~ 🕊️",
-			g.activePigeon.activePigeon.Type,
-		))
+		g.irc().Privmsg(g.channel, g.msg(msgEscape, g.activePigeon.activePigeon.Type))
 
 		g.activePigeon.activePigeon = nil
 		g.activePigeon.IsMating = false
 		return
 	}
 
-	randomPigeon := g.pigeons[rand.IntN(len(g.pigeons))]
-	randomAction := g.actions[rand.IntN(len(g.actions))]
+	g.spawn(nil)
+}
+
+// spawn makes randomPigeon the active pigeon, picking one by weight when it is nil.
+// The caller holds the players and activePigeon locks.
+func (g *Game) spawn(randomPigeon *pigeon.Pigeon) {
+	if randomPigeon == nil {
+		randomPigeon = pigeon.Pick(g.pigeons, g.rng().IntN)
+	}
+	if randomPigeon == nil {
+		return
+	}
+	randomAction := g.actions[g.rng().IntN(len(g.actions))]
 
 	// ✅ นกเกิดใหม่จริง ๆ → เพิ่ม spawnID
 	newSpawnID := g.NewPigeonSpawn()
 
 	g.activePigeon.activePigeon = randomPigeon
 	g.activePigeon.IsMating = (randomAction.Action == "mating")
-	g.activePigeon.SpawnedAt = time.Now()
+	g.activePigeon.SpawnedAt = g.now()
 
-	g.ircClient.Privmsg(g.channel, randomAction.Act(randomPigeon.Type))
+	item := randomAction.PickItem(g.rng().IntN)
+	g.irc().Privmsg(g.channel, g.announce(randomAction.Action, randomAction.Format, randomPigeon.Type, item))
 
 	// (optional debug)
 	fmt.Printf("[dbg] NEW PIGEON spawnID=%d type=%s\n", newSpawnID, randomPigeon.Type)
 
 }
 
-// AddPlayer adds a new player to the game
-func (g *Game) addPlayer(ctx context.Context, name string) (*player.Player, error) {
-	g.players.Lock()
-	defer g.players.Unlock()
+// FindPlayer returns name's cached score, reading it from the database (and
+// creating the player there) when it is not in the roster yet
+func (g *Game) FindPlayer(ctx context.Context, name string) (*player.Player, error) {
+	g.roster().Lock()
 
-	// Canonicalize name for consistent storage
+	// Canonicalize name for consistent lookup
 	canonicalName := canonicalPlayerName(name)
 
-	for _, p := range g.players.players {
+	for _, p := range g.roster().players {
 		if p.Name == canonicalName {
+			g.roster().Unlock()
 			return p, nil
 		}
 	}
+	g.roster().Unlock()
 
-	newPlayer := player.NewPlayer(canonicalName, 0, 0)
-	g.players.players = append(g.players.players, newPlayer)
-	playerEntity := player2.Player{
-		Count:   0,
-		Points:  0,
-		Name:    canonicalName,
-		Channel: g.channel,
-		Network: g.network,
-	}
-	err := g.playerRepository.UpsertPlayer(ctx, &playerEntity)
+	stored, err := g.playerRepository.AddPoints(ctx, g.network, g.scope(), canonicalName, 0)
 	if err != nil {
 		return nil, err
 	}
-
-	return newPlayer, nil
+	return g.cache(stored), nil
 }
 
-// FindPlayer finds a player by name
-func (g *Game) FindPlayer(ctx context.Context, name string) (*player.Player, error) {
-	g.players.Lock()
-
-	// Canonicalize name for consistent lookup
-	canonicalName := canonicalPlayerName(name)
-
-	for _, p := range g.players.players {
-		if p.Name == canonicalName {
-			g.players.Unlock()
-			return p, nil
-		}
+// cache copies a score as the database returned it into the roster, adding
+// the player if they are new, and returns the roster's player. The database
+// is authoritative; the roster only saves a query on every listing.
+func (g *Game) cache(stored *player2.Player) *player.Player {
+	g.roster().Lock()
+	defer g.roster().Unlock()
+
+	name := canonicalPlayerName(stored.Name)
+	p := findIn(g.roster().players, name)
+	if p == nil {
+		p = player.NewPlayer(name, 0, 0)
+		g.roster().players = append(g.roster().players, p)
 	}
-	g.players.Unlock()
-
-	return g.addPlayer(ctx, name)
-
+	p.Points, p.Count = stored.Points, stored.Count
+	p.Shots, p.Misses = stored.Shots, stored.Misses
+	p.Streak, p.BestStreak = stored.Streak, stored.BestStreak
+	p.Eggs, p.RareEggs = stored.Eggs, stored.RareEggs
+	return p
 }
 
 func (g *Game) CurrentSpawnID() int64 {
@@ -273,12 +382,19 @@ func (g *Game) CurrentSpawnID() int64 {
 func (g *Game) HandleShoot(ctx context.Context, args ...string) error {
 	name := context_manager.GetNickContext(ctx)
 
+	// nicks that belong to a services account are only played while logged in
+	if g.impostor(ctx) {
+		g.irc().Privmsg(g.channel, g.msg(msgShootImpostor, name))
+		return nil
+	}
+	playerName := g.playerName(ctx, name)
+
 	// 🔒 PER-USER COOLDOWN CHECK (5 shots before cooldown)
 	spawnID := g.CurrentSpawnID()
-	ok, wait := g.canShoot(name, spawnID)
+	ok, wait := g.canShoot(playerName, spawnID)
 	if !ok {
-		g.ircClient.Privmsg(g.channel,
-			fmt.Sprintf("...%s slow down... you can shoot again in %.1f seconds ⏳🕊️", name, wait.Seconds()),
+		g.irc().Privmsg(g.channel,
+			g.msg(msgShootCooldown, name, wait.Seconds()),
 		)
 		return nil
 	}
@@ -287,103 +403,110 @@ func (g *Game) HandleShoot(ctx context.Context, args ...string) error {
 	g.activePigeon.Lock()
 	defer g.activePigeon.Unlock()
 
+	event := &shot2.ShotEvent{
+		Network: g.network,
+		Channel: g.scope(),
+		Name:    playerName,
+		SpawnID: spawnID,
+		ShotAt:  g.now(),
+	}
+
 	if g.activePigeon.activePigeon == nil {
-		g.ircClient.Privmsg(
+		g.recordShot(event)
+		g.irc().Privmsg(
 			g.channel,
-			fmt.Sprintf("❗⚠️ %s has shot a pigeon!, but there are no pigeons to shoot! - - 🐦", name),
+			g.msg(msgShootNothing, name),
 		)
 		return nil
 	}
+	event.PigeonType = g.activePigeon.activePigeon.Type
 
-	foundPlayer, err := g.FindPlayer(ctx, name)
-	if err != nil {
-		g.ircClient.Privmsg(
+	randomValue := g.rng().IntN(100)
+	success := randomValue < g.activePigeon.activePigeon.Success
+
+	if !success {
+		if _, err := g.store(ctx, scoreChange{kind: changeMiss, name: playerName}); err != nil {
+			g.irc().Privmsg(g.channel, g.msg(msgShootError, name))
+			return err
+		}
+		g.recordShot(event)
+		g.irc().Privmsg(
 			g.channel,
-			fmt.Sprintf("❗⚠️ %s has shot a pigeon, but there was an error finding the player! - - 🐦", name),
+			g.msg(msgShootMiss, name),
 		)
-		return err
+		return nil
 	}
 
-	randomValue := rand.IntN(100)
-	success := randomValue < g.activePigeon.activePigeon.Success
-
-	if success {
-		foundPlayer.Points += g.activePigeon.activePigeon.Points
-		foundPlayer.Count++
-
-		level := foundPlayer.GetPlayerLevel()
-
-		g.ircClient.Privmsg(
+	// the kill only counts once it is stored or queued; until then the pigeon stays
+	stored, err := g.store(ctx, scoreChange{kind: changeKill, name: playerName, points: g.activePigeon.activePigeon.Points})
+	if err != nil {
+		g.irc().Privmsg(
 			g.channel,
-			fmt.Sprintf(
-				"❗⚠️ %s has shot a pigeon! - - 🐦 🔫 You are a murderer! . .  You have shot a total of %s pigeon(s)! . . 🐦 🕊️ . . You now have a total of %s points and reached the level: %s",
-				name,
-				fmtNum(foundPlayer.Count),
-				fmtNum(foundPlayer.Points),
-				level,
-			),
+			g.msg(msgShootError, name),
 		)
+		return err
+	}
+	event.Hit = true
+	event.Points = g.activePigeon.activePigeon.Points
 
-		if eggMsg, err := g.EggsAfterShot(ctx, name); err == nil && eggMsg != "" {
-			g.ircClient.Privmsg(g.channel, eggMsg)
-		}
-
-		if rareMsg, err := g.TryRareEgg(ctx, name); err == nil && rareMsg != "" {
-			g.ircClient.Privmsg(g.channel, rareMsg)
-		}
+	g.irc().Privmsg(
+		g.channel,
+		g.msg(
+			msgShootHit,
+			name,
+			fmtNum(stored.Count),
+			fmtNum(stored.Points),
+			g.LevelFor(stored.Points, stored.Count),
+		),
+	)
 
-		g.activePigeon.activePigeon = nil
-		g.activePigeon.IsMating = false
+	eggMsg, gained, cracked, err := g.matingEggs(ctx, name)
+	if err == nil && eggMsg != "" {
+		g.irc().Privmsg(g.channel, eggMsg)
+	}
+	event.EggsGained, event.EggsCracked = gained, cracked
 
-	} else {
-		g.ircClient.Privmsg(
-			g.channel,
-			fmt.Sprintf("❗⚠️ %s has shot a pigeon, but it got away! - - 🐦", name),
-		)
+	rareMsg, rare, err := g.tryRareEgg(ctx, name)
+	if err == nil && rareMsg != "" {
+		g.irc().Privmsg(g.channel, rareMsg)
 	}
+	event.RareEgg = rare
+	if rare == shot2.RareEggCollected {
+		event.Points += g.Settings().RareEggPointBoost
+		event.EggsGained++
+	}
+
+	g.activePigeon.activePigeon = nil
+	g.activePigeon.IsMating = false
 
-	return g.SavePlayers(ctx)
+	g.recordShot(event)
+	return nil
 }
 
-func (g *Game) SavePlayers(ctx context.Context) error {
-	g.players.Lock()
-	defer g.players.Unlock()
-	for _, p := range g.players.players {
-		playerEntity := player2.Player{
-			Count:   p.Count,
-			Points:  p.Points,
-			Name:    p.Name,
-			Channel: g.channel,
-			Network: g.network,
-		}
-		err := g.playerRepository.UpsertPlayer(ctx, &playerEntity)
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
+// recordShot hands e to the shot recorder, if there is one
+func (g *Game) recordShot(e *shot2.ShotEvent) {
+	if g.shots != nil {
+		g.shots.Record(e)
 	}
-	return nil
 }
 
+// HandlePoints lists every player's points, best first: !score [page N]
 func (g *Game) HandlePoints(ctx context.Context, args ...string) error {
-	// list player points in one line
-	// format: <player name>: <points>, <player name>: <points>, ...
-	g.players.Lock()
-	defer g.players.Unlock()
+	g.roster().Lock()
+	defer g.roster().Unlock()
 
-	sortedPlayers := make([]*player.Player, len(g.players.players))
-	copy(sortedPlayers, g.players.players)
+	sortedPlayers := make([]*player.Player, len(g.roster().players))
+	copy(sortedPlayers, g.roster().players)
 	sort.Slice(sortedPlayers, func(i, j int) bool {
 		return sortedPlayers[i].Points > sortedPlayers[j].Points
 	})
 
-	text := ""
+	entries := make([]string, 0, len(sortedPlayers))
 	for _, p := range sortedPlayers {
-		text += fmt.Sprintf("%s: %d, ", p.Name, p.Points)
-
+		entries = append(entries, fmt.Sprintf("%s: %d, ", p.Name, p.Points))
 	}
 
-	g.ircClient.Privmsg(g.channel, text)
+	g.sendListing(ctx, "!score", entries, args)
 	return nil
 
 }
@@ -391,96 +514,100 @@ func (g *Game) HandlePoints(ctx context.Context, args ...string) error {
 func (g *Game) HandleHelp(ctx context.Context, args ...string) error {
 	// list player points in one line
 	// format: <player name>: <points>, <player name>: <points>, ...
-	g.players.Lock()
-	defer g.players.Unlock()
+	g.roster().Lock()
+	defer g.roster().Unlock()
 
-	text := "Commands: !shoot, !score, !pigeons, !bef, !help, !level, !top5, !top10, !eggs"
-	g.ircClient.Privmsg(g.channel, text)
+	g.irc().Privmsg(g.channel, g.msg(msgHelp))
 	return nil
 
 }
 
+// HandleCount lists how many pigeons every player has shot: !pigeons [page N]
 func (g *Game) HandleCount(ctx context.Context, args ...string) error {
-	// list player points in one line
-	// format: <player name>: <points>, <player name>: <points>, ...
-	g.players.Lock()
-	defer g.players.Unlock()
+	g.roster().Lock()
+	defer g.roster().Unlock()
 
-	text := ""
 	// sort players by count
-	sortedPlayers := make([]*player.Player, len(g.players.players))
-	copy(sortedPlayers, g.players.players)
+	sortedPlayers := make([]*player.Player, len(g.roster().players))
+	copy(sortedPlayers, g.roster().players)
 	sort.Slice(sortedPlayers, func(i, j int) bool {
 		return sortedPlayers[i].Count > sortedPlayers[j].Count
 	})
-	for _, p := range sortedPlayers {
-		text += fmt.Sprintf("%s: %d, ", p.Name, p.Count)
 
+	entries := make([]string, 0, len(sortedPlayers))
+	for _, p := range sortedPlayers {
+		entries = append(entries, fmt.Sprintf("%s: %d, ", p.Name, p.Count))
 	}
 
-	g.ircClient.Privmsg(g.channel, text)
+	g.sendListing(ctx, "!pigeons", entries, args)
 	return nil
 
 }
 
 func (g *Game) HandleBef(ctx context.Context, args ...string) error {
-	g.ircClient.Privmsg(g.channel, "🕊️ ~ coo coo ~ cannot be frens with a rat of the sky ~ 🕊️")
+	g.irc().Privmsg(g.channel, g.msg(msgBef))
 
 	return nil
 }
 
+// HandleLevel lists every player's level: !level [page N]
 func (g *Game) HandleLevel(ctx context.Context, args ...string) error {
-	// list player points in one line
-	// format: <player name>: <points>, <player name>: <points>, ...
-	g.players.Lock()
-	defer g.players.Unlock()
+	g.roster().Lock()
+	defer g.roster().Unlock()
 
-	text := ""
 	// sort players by count
-	sortedPlayers := make([]*player.Player, len(g.players.players))
-	copy(sortedPlayers, g.players.players)
+	sortedPlayers := make([]*player.Player, len(g.roster().players))
+	copy(sortedPlayers, g.roster().players)
 	sort.Slice(sortedPlayers, func(i, j int) bool {
 		return sortedPlayers[i].Count > sortedPlayers[j].Count
 	})
-	for _, p := range sortedPlayers {
-		level := p.GetPlayerLevel()
-		text += fmt.Sprintf("%s: %s, ", p.Name, level)
 
+	entries := make([]string, 0, len(sortedPlayers))
+	for _, p := range sortedPlayers {
+		entries = append(entries, fmt.Sprintf("%s: %s, ", p.Name, g.translate(msgLevelPrefix, p.GetPlayerLevel())))
 	}
 
-	g.ircClient.Privmsg(g.channel, text)
+	g.sendListing(ctx, "!level", entries, args)
 	return nil
 
 }
 
-func (g *Game) handleTopN(ctx context.Context, n int) error {
+// handleTopN lists the channel's top n players, or the network's when global is set
+func (g *Game) handleTopN(ctx context.Context, n int, global bool) error {
+	header := g.msg(msgTopHeader, n)
+	fetch := g.TopByPoints
+	if global {
+		header = g.msg(msgTopHeaderNetwork, n, g.network)
+		fetch = g.TopByPointsNetwork
+	}
+
 	// 🏆 Header (gold)
-	g.ircClient.Privmsg(
+	g.listingClient().Privmsg(
 		g.channel,
 		fmt.Sprintf(
 			"%s%s%s",
 			ircBold,
-			c(fmt.Sprintf("🏆 Top %d Pigeon Hunters", n), 8),
+			c(header, 8),
 			ircReset,
 		),
 	)
 
-	topPlayers, err := g.TopByPoints(ctx, n)
+	topPlayers, err := fetch(ctx, n)
 	if err != nil {
-		g.ircClient.Privmsg(g.channel, "Error fetching top players")
+		g.listingClient().Privmsg(g.channel, g.msg(msgTopError))
 		return err
 	}
 
 	for i, p := range topPlayers {
 		rank := medal(i)
 
-		pointsText := fmt.Sprintf("%s points", fmtNum(p.Points))
-		pigeonsText := fmt.Sprintf("%s pigeons", fmtNum(p.Count))
-		levelText := fmt.Sprintf("Level: %s ", g.LevelFor(p.Points, p.Count))
-		eggsText := fmt.Sprintf("Eggs: %s", fmtNum(p.Eggs))
-		rareText := fmt.Sprintf("Rare: %s 🌟", fmtNum(p.RareEggs))
+		pointsText := g.msg(msgLabelPoints, fmtNum(p.Points))
+		pigeonsText := g.msg(msgLabelPigeons, fmtNum(p.Count))
+		levelText := g.msg(msgLabelLevel, g.LevelFor(p.Points, p.Count))
+		eggsText := g.msg(msgLabelEggs, fmtNum(p.Eggs))
+		rareText := g.msg(msgLabelRare, fmtNum(p.RareEggs))
 
-		g.ircClient.Privmsg(
+		g.listingClient().Privmsg(
 			g.channel,
 			fmt.Sprintf(
 				"%s %s :::::: %s | %s | %s | %s (%s)",
@@ -499,11 +626,21 @@ func (g *Game) handleTopN(ctx context.Context, n int) error {
 }
 
 func (g *Game) HandleTop5(ctx context.Context, args ...string) error {
-	return g.handleTopN(ctx, 5)
+	return g.handleTopFixed(ctx, 5, args)
 }
 
 func (g *Game) HandleTop10(ctx context.Context, args ...string) error {
-	return g.handleTopN(ctx, 10)
+	return g.handleTopFixed(ctx, 10, args)
+}
+
+// handleTopFixed runs !top5 / !top10 with an optional window and --global
+func (g *Game) handleTopFixed(ctx context.Context, n int, args []string) error {
+	q, ok := g.parseTop(ctx, args, n, false)
+	if !ok {
+		g.irc().Privmsg(g.channel, g.msg(msgTopFixedUsage, n))
+		return nil
+	}
+	return g.handleTop(ctx, q)
 }
 
 func c(s string, fg int) string { // foreground only
@@ -525,7 +662,7 @@ func medal(i int) string {
 
 // --- Helpers for commands.TopHandler ---
 
-// Irc exposes the IRC client (read-only)
+// Irc exposes the IRC client (read-only), unstyled; channel messages go through Styled
 func (g *Game) Irc() IRCClient { return g.ircClient }
 
 // Channel returns the current channel (read-only)
@@ -542,30 +679,33 @@ func (g *Game) TopByPoints(ctx context.Context, limit int) ([]*player2.Player, e
 	if limit > 50 {
 		limit = 50
 	}
-	return g.playerRepository.TopByPoints(ctx, g.network, g.channel, limit)
+	if err := g.writeWaitingIn(ctx, g.scope()); err != nil {
+		return nil, err
+	}
+	return g.playerRepository.TopByPoints(ctx, g.network, g.scope(), limit)
 }
 
 // LevelFor maps (points,count) to the player's level using your services/player logic.
 func (g *Game) LevelFor(points, count int) string {
 	tmp := &player.Player{Name: "", Points: points, Count: count}
-	return tmp.GetPlayerLevel()
+	return g.translate(msgLevelPrefix, tmp.GetPlayerLevel())
 }
 
 func (g *Game) HandlePingCommand(ctx context.Context, args ...string) error {
 	nick := context_manager.GetNickContext(ctx)
 
-	token := fmt.Sprintf("%d", time.Now().UnixNano())
+	token := fmt.Sprintf("%d", g.now().UnixNano())
 
 	g.pingMu.Lock()
 	g.pending[token] = pendingPing{
 		nick:    nick,
 		channel: g.channel,
-		start:   time.Now(),
+		start:   g.now(),
 	}
 	g.pingMu.Unlock()
 
 	// Send CTCP PING to the user (their client will auto-reply)
-	g.ircClient.Privmsg(nick, "\x01PING "+token+"\x01")
+	g.irc().Privmsg(nick, "\x01PING "+token+"\x01")
 
 	// Timeout fallback
 	time.AfterFunc(10*time.Second, func() {
@@ -577,7 +717,7 @@ func (g *Game) HandlePingCommand(ctx context.Context, args ...string) error {
 		g.pingMu.Unlock()
 
 		if ok {
-			g.ircClient.Privmsg(p.channel, fmt.Sprintf("%s: Pong (timeout)", p.nick))
+			g.irc().Privmsg(p.channel, g.msg(msgPongTimeout, p.nick))
 		}
 	})
 
@@ -603,6 +743,6 @@ func (g *Game) HandleCTCPReply(from string, args []string) {
 		return
 	}
 
-	secs := time.Since(p.start).Seconds()
-	g.ircClient.Privmsg(p.channel, fmt.Sprintf("%s: Pong (%.3fs)", p.nick, secs))
+	secs := g.now().Sub(p.start).Seconds()
+	g.irc().Privmsg(p.channel, g.msg(msgPong, p.nick, secs))
 }
@@ -335,7 +335,7 @@ func TestLevelFor(t *testing.T) {
 }
 
 func TestFindPlayer(t *testing.T) {
-	t.Run("FindPlayer creates new player if not exists", func(t *testing.T) {
+	t.Run("FindPlayer reads a player it has not cached", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -349,8 +349,8 @@ func TestFindPlayer(t *testing.T) {
 			Times(1)
 
 		playerRepository.EXPECT().
-			UpsertPlayer(gomock.Any(), gomock.Any()).
-			Return(nil).
+			AddPoints(gomock.Any(), "network", "channel", "newplayer", 0).
+			Return(&player.Player{Name: "newplayer", Points: 40, Count: 4}, nil).
 			Times(1)
 
 		ircClient := gameMocks.NewMockIRCClient(ctrl)
@@ -366,6 +366,8 @@ func TestFindPlayer(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, foundPlayer)
 		assert.Equal(t, "newplayer", foundPlayer.Name)
+		assert.Equal(t, 40, foundPlayer.Points)
+		assert.Equal(t, 4, foundPlayer.Count)
 	})
 
 	t.Run("FindPlayer returns existing player", func(t *testing.T) {
@@ -401,43 +403,6 @@ func TestFindPlayer(t *testing.T) {
 	})
 }
 
-func TestSavePlayers(t *testing.T) {
-	t.Run("SavePlayers persists all players", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-
-		playerRepository := mocks.NewMockPlayerRepository(ctrl)
-		playerRepository.EggsByKey = make(map[string]int)
-		playerRepository.RareEggsByKey = make(map[string]int)
-
-		playerRepository.EXPECT().
-			GetAllPlayers(gomock.Any(), "network", "channel").
-			Return([]*player.Player{
-				{ID: "1", Name: "player1", Points: 100, Count: 10},
-				{ID: "2", Name: "player2", Points: 200, Count: 20},
-			}, nil).
-			Times(1)
-
-		// Expect UpsertPlayer to be called for each player
-		playerRepository.EXPECT().
-			UpsertPlayer(gomock.Any(), gomock.Any()).
-			Return(nil).
-			Times(2)
-
-		ircClient := gameMocks.NewMockIRCClient(ctrl)
-		ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
-
-		gameInstance := game.NewGame(config.GameConfig{Interval: 3}, ircClient, playerRepository, "network", "channel")
-
-		ctx := context.Background()
-		go gameInstance.Start(ctx)
-		time.Sleep(100 * time.Millisecond)
-
-		err := gameInstance.SavePlayers(ctx)
-		assert.Nil(t, err)
-	})
-}
-
 func TestGameStart_ContextCancellation(t *testing.T) {
 	t.Run("Start stops when context is cancelled", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -3,6 +3,7 @@ package game
 import (
 	"testing"
 
+	"github.com/MyelinBots/pigeonbot-go/config"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/actions"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,6 +76,23 @@ func TestPredefinedActions(t *testing.T) {
 	assert.Len(t, matingAction.Items, 6)
 }
 
+func TestPigeonsFor(t *testing.T) {
+	cfg := config.GameConfig{
+		Pigeons: config.PigeonCatalogue{
+			Pigeons: []config.PigeonConfig{{Type: "city", Points: 5, Success: 90}},
+			Channels: map[string][]config.PigeonConfig{
+				"#vip": {{Type: "golden", Points: 1000, Success: 5}},
+			},
+		},
+	}
+
+	assert.Equal(t, "city", pigeonsFor(cfg, "#lobby")[0].Type)
+	assert.Equal(t, "golden", pigeonsFor(cfg, "#VIP")[0].Type)
+
+	// No catalogue falls back to the built-in pigeons
+	assert.Len(t, pigeonsFor(config.GameConfig{}, "#lobby"), 3)
+}
+
 func findActionByName(actionsList []actions.Action, name string) *actions.Action {
 	for i := range actionsList {
 		if actionsList[i].Action == name {
@@ -28,6 +28,18 @@ func initEggStores(repo *mocks.MockPlayerRepository) {
 	// }
 }
 
+// allowScoring lets every shot be stored, returning an empty score
+func allowScoring(repo *mocks.MockPlayerRepository) {
+	repo.EXPECT().AwardKill(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&player.Player{}, nil).AnyTimes()
+	repo.EXPECT().RecordMiss(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&player.Player{}, nil).AnyTimes()
+	repo.EXPECT().AddPoints(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&player.Player{}, nil).AnyTimes()
+	repo.EXPECT().CollectEggs(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&player.Player{}, nil).AnyTimes()
+}
+
 func TestGame(t *testing.T) {
 
 	t.Run("TestGame", func(t *testing.T) {
@@ -54,10 +66,7 @@ func TestGame(t *testing.T) {
 			}, nil).
 			Times(1)
 
-		playerRepository.EXPECT().
-			UpsertPlayer(gomock.Any(), gomock.Any()).
-			Return(nil).
-			AnyTimes()
+		allowScoring(playerRepository)
 
 		ircClient := gameMocks.NewMockIRCClient(ctrl)
 		ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
@@ -95,10 +104,7 @@ func TestGame(t *testing.T) {
 			Return([]*player.Player{}, nil).
 			Times(1)
 
-		playerRepository.EXPECT().
-			UpsertPlayer(gomock.Any(), gomock.Any()).
-			Return(nil).
-			AnyTimes()
+		allowScoring(playerRepository)
 
 		ircClient := gameMocks.NewMockIRCClient(ctrl)
 		ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
@@ -261,7 +267,7 @@ func TestGame(t *testing.T) {
 
 		ircClient := gameMocks.NewMockIRCClient(ctrl)
 		ircClient.EXPECT().Privmsg("channel", gomock.Any()).Times(1)
-		ircClient.EXPECT().Privmsg("channel", "Commands: !shoot, !score, !pigeons, !bef, !help, !level, !top5, !top10, !eggs").Times(1)
+		ircClient.EXPECT().Privmsg("channel", "Commands: !shoot, !score, !pigeons, !bef, !help, !level, !top, !top5, !top10, !stats, !season, !eggs, !set").Times(1)
 
 		gameinstance := game.NewGame(config.GameConfig{Interval: 3}, ircClient, playerRepository, "network", "channel")
 
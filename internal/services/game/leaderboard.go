@@ -0,0 +1,212 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+)
+
+const globalArg = "--global"
+
+// globalFlag removes --global from args and reports whether it was there
+func globalFlag(args []string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	global := false
+	for _, a := range args {
+		if strings.EqualFold(a, globalArg) {
+			global = true
+			continue
+		}
+		rest = append(rest, a)
+	}
+	return rest, global
+}
+
+// TopByPointsNetwork fetches the top-N players across every channel of this game's network.
+func (g *Game) TopByPointsNetwork(ctx context.Context, limit int) ([]*player2.Player, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	if err := g.writeWaitingIn(ctx, ""); err != nil {
+		return nil, err
+	}
+	return g.playerRepository.TopByPointsNetwork(ctx, g.network, limit)
+}
+
+// HandleTop lists the best players: !top [n] [today|week|month|season|from..to] [--global]
+func (g *Game) HandleTop(ctx context.Context, args ...string) error {
+	q, ok := g.parseTop(ctx, args, 5, true)
+	if !ok {
+		g.irc().Privmsg(g.channel, g.msg(msgTopUsage))
+		return nil
+	}
+	return g.handleTop(ctx, q)
+}
+
+// HandleStats shows one player's score, aim and progress here, or summed
+// over the network with --global: !stats [nick] [--global]
+func (g *Game) HandleStats(ctx context.Context, args ...string) error {
+	args, global := globalFlag(args)
+
+	nick := context_manager.GetNickContext(ctx)
+	if len(args) > 0 {
+		nick = args[0]
+	}
+	if nick == "" {
+		return nil
+	}
+	name := g.playerName(ctx, nick)
+
+	var (
+		stats *player2.Player
+		where = g.msg(msgStatsHere)
+		scope = g.scope()
+		err   error
+	)
+	if global {
+		where = g.msg(msgStatsNetwork, g.network)
+		scope = ""
+		if err = g.writeWaitingIn(ctx, ""); err == nil {
+			stats, err = g.playerRepository.GetPlayerTotals(ctx, g.network, name)
+		}
+	} else {
+		stats = g.channelStats(name)
+	}
+	if err != nil {
+		g.irc().Privmsg(g.channel, g.msg(msgStatsError))
+		return err
+	}
+	if stats == nil || (stats.Count == 0 && stats.Points == 0 && stats.Shots == 0) {
+		g.irc().Privmsg(g.channel, g.msg(msgStatsNone, nick, where))
+		return nil
+	}
+
+	text := fmt.Sprintf(
+		"📊 %s %s :::::: %s | %s | %s | %s (%s)",
+		nick,
+		where,
+		c(g.msg(msgLabelPoints, fmtNum(stats.Points)), 7),
+		c(g.msg(msgLabelPigeons, fmtNum(stats.Count)), 4),
+		c(g.msg(msgLabelLevel, g.LevelFor(stats.Points, stats.Count)), 13),
+		c(g.msg(msgLabelEggs, fmtNum(stats.Eggs)), 8),
+		c(g.msg(msgLabelRare, fmtNum(stats.RareEggs)), 8),
+	)
+	if !global {
+		// season medals are kept even after scores are reset
+		if badges, err := g.badges(ctx, name); err != nil {
+			fmt.Printf("Error loading badges for %s: %v\n", nick, err)
+		} else if badges != "" {
+			text += g.msg(msgLabelBadges, badges)
+		}
+	}
+	g.say(text)
+	g.say(g.aimLine(ctx, nick, name, scope, stats, !global))
+	return nil
+}
+
+// aimLine is the second !stats line: accuracy, streak, rank, the next level
+// and kills per pigeon type. rank is only known for a single channel.
+func (g *Game) aimLine(ctx context.Context, nick, name, scope string, stats *player2.Player, rank bool) string {
+	p := &player.Player{Name: stats.Name, Count: stats.Count, Shots: stats.Shots, Misses: stats.Misses}
+
+	parts := []string{
+		c(g.msg(msgLabelShots, fmtNum(p.Shots), p.Accuracy()), 7),
+		c(g.msg(msgLabelBestStreak, fmtNum(stats.BestStreak)), 4),
+	}
+	if rank {
+		place, of := g.rank(name)
+		parts = append(parts, c(g.msg(msgLabelRank, place, of), 8))
+	}
+	if next, remaining, ok := p.NextLevel(); ok {
+		parts = append(parts, c(g.msg(msgLabelNextLevel, fmtNum(remaining), g.translate(msgLevelPrefix, next)), 13))
+	} else {
+		parts = append(parts, c(g.msg(msgLabelTopLevel), 13))
+	}
+	if kills := g.killsByType(ctx, scope, name); kills != "" {
+		parts = append(parts, g.msg(msgLabelKills, kills))
+	}
+
+	return fmt.Sprintf("🎯 %s :::::: %s", nick, strings.Join(parts, " | "))
+}
+
+// killsByType lists name's kills per pigeon type from the shot history, or
+// "" when there is none
+func (g *Game) killsByType(ctx context.Context, scope, name string) string {
+	if g.shotRepository == nil {
+		return ""
+	}
+	kills, err := g.shotRepository.KillsByType(ctx, g.network, scope, name)
+	if err != nil {
+		fmt.Printf("Error loading kills for %s: %v\n", name, err)
+		return ""
+	}
+
+	out := make([]string, 0, len(kills))
+	for _, k := range kills {
+		out = append(out, fmt.Sprintf("%s %s", k.PigeonType, fmtNum(k.Count)))
+	}
+	return strings.Join(out, ", ")
+}
+
+// rank returns name's place in this game's pool by points then pigeons, and
+// how many players there are
+func (g *Game) rank(name string) (place, of int) {
+	roster := g.roster()
+	roster.Lock()
+	defer roster.Unlock()
+
+	var me *player.Player
+	for _, p := range roster.players {
+		if p.Name == name {
+			me = p
+			break
+		}
+	}
+	if me == nil {
+		return 0, len(roster.players)
+	}
+
+	place = 1
+	for _, p := range roster.players {
+		if p.Points > me.Points || (p.Points == me.Points && p.Count > me.Count) {
+			place++
+		}
+	}
+	return place, len(roster.players)
+}
+
+// channelStats returns name's score in this game's pool without adding them
+// as a player; the roster counts changes still waiting for the database
+func (g *Game) channelStats(name string) *player2.Player {
+
+	roster := g.roster()
+	roster.Lock()
+	var stats *player2.Player
+	for _, p := range roster.players {
+		if p.Name == name {
+			stats = &player2.Player{
+				Name:       p.Name,
+				Points:     p.Points,
+				Count:      p.Count,
+				Shots:      p.Shots,
+				Misses:     p.Misses,
+				Streak:     p.Streak,
+				BestStreak: p.BestStreak,
+				Eggs:       p.Eggs,
+				RareEggs:   p.RareEggs,
+				Network:    g.network,
+				Channel:    g.scope(),
+			}
+			break
+		}
+	}
+	roster.Unlock()
+	return stats
+}
@@ -0,0 +1,179 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	playerMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player/mocks"
+	shot2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+	shotMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/pigeon"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGlobalFlag(t *testing.T) {
+	rest, global := globalFlag([]string{"alice", "--GLOBAL"})
+	assert.True(t, global)
+	assert.Equal(t, []string{"alice"}, rest)
+
+	rest, global = globalFlag([]string{"alice"})
+	assert.False(t, global)
+	assert.Equal(t, []string{"alice"}, rest)
+}
+
+func TestHandleTop_Global(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := playerMocks.NewMockPlayerRepository(ctrl)
+	repo.EXPECT().TopByPointsNetwork(gomock.Any(), "testnet", 3).Return([]*player2.Player{
+		{Name: "alice", Points: 300, Count: 30},
+		{Name: "bob", Points: 100, Count: 10},
+	}, nil)
+
+	client := &mockIRCClientForTest{}
+	g := NewGame(config.GameConfig{}, client, repo, "testnet", "#pigeons")
+
+	require.NoError(t, g.HandleTop(context.Background(), "3", "--global"))
+	require.Len(t, client.messages, 3)
+	assert.Contains(t, client.messages[0], "Top 3 Pigeon Hunters on testnet")
+	assert.Contains(t, client.messages[1], "alice")
+	assert.Contains(t, client.messages[2], "bob")
+}
+
+func TestHandleTop_Usage(t *testing.T) {
+	client := &mockIRCClientForTest{}
+	g := &Game{ircClient: client, channel: "#pigeons"}
+
+	require.NoError(t, g.HandleTop(context.Background(), "50"))
+	assert.Equal(t, []string{"🏆 usage: !top [1-10] [today|week|month|season|YYYY-MM-DD..YYYY-MM-DD] [--global]"}, client.messages)
+}
+
+func TestHandleTop5_Global(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := playerMocks.NewMockPlayerRepository(ctrl)
+	repo.EXPECT().TopByPointsNetwork(gomock.Any(), "testnet", 5).Return(nil, errors.New("db down"))
+
+	client := &mockIRCClientForTest{}
+	g := NewGame(config.GameConfig{}, client, repo, "testnet", "#pigeons")
+
+	assert.Error(t, g.HandleTop5(context.Background(), "--global"))
+	assert.Equal(t, "Error fetching top players", client.messages[len(client.messages)-1])
+}
+
+func TestHandleStats(t *testing.T) {
+	t.Run("channel stats come from memory", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := playerMocks.NewMockPlayerRepository(ctrl)
+		// eggs still waiting to be written count too
+		_, _ = repo.AddEggs(context.Background(), "testnet", "#pigeons", "alice", 2)
+
+		client := &mockIRCClientForTest{}
+		g := NewGame(config.GameConfig{}, client, repo, "testnet", "#pigeons")
+		alice := player.NewPlayer("alice", 120, 12)
+		alice.Shots, alice.Misses, alice.BestStreak = 16, 4, 5
+		alice.Eggs, alice.RareEggs = 4, 1
+		g.players.players = []*player.Player{player.NewPlayer("bob", 300, 30), alice, player.NewPlayer("carol", 5, 1)}
+
+		ctx := context_manager.WithNick(context.Background(), "Alice")
+		require.NoError(t, g.HandleStats(ctx))
+		require.Len(t, client.messages, 2)
+		assert.True(t, strings.HasPrefix(client.messages[0], "📊 alice here"), client.messages[0])
+		assert.Contains(t, client.messages[0], "120 points")
+		assert.Contains(t, client.messages[0], "Eggs: 4")
+		assert.Contains(t, client.messages[1], "16 shots (75.0% hit)")
+		assert.Contains(t, client.messages[1], "Best streak: 5")
+		assert.Contains(t, client.messages[1], "Rank: #2 of 3")
+		assert.Contains(t, client.messages[1], "89 more to Adept 🦅")
+		assert.NotContains(t, client.messages[1], "Kills:", "no shot history is kept")
+	})
+
+	t.Run("kills per pigeon come from the shot history", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		shots := shotMocks.NewMockShotEventRepository(ctrl)
+		shots.EXPECT().KillsByType(gomock.Any(), "testnet", "#pigeons", "alice").
+			Return([]*shot2.Kills{{PigeonType: "boss", Count: 3}, {PigeonType: "white", Count: 1}}, nil)
+
+		client := &mockIRCClientForTest{}
+		g := NewGame(config.GameConfig{}, client, newMockPlayerRepoForTest(), "testnet", "#pigeons", WithShotRepository(shots))
+		g.players.players = []*player.Player{player.NewPlayer("alice", 40, 4)}
+
+		require.NoError(t, g.HandleStats(context.Background(), "alice"))
+		require.Len(t, client.messages, 2)
+		assert.True(t, strings.HasSuffix(client.messages[1], "| Kills: boss 3, white 1"), client.messages[1])
+	})
+
+	t.Run("unknown players are not added", func(t *testing.T) {
+		client := &mockIRCClientForTest{}
+		g := NewGame(config.GameConfig{}, client, newMockPlayerRepoForTest(), "testnet", "#pigeons")
+
+		require.NoError(t, g.HandleStats(context.Background(), "nobody"))
+		assert.Equal(t, []string{"📊 nobody hasn't shot any pigeons here yet"}, client.messages)
+		assert.Empty(t, g.players.players)
+	})
+
+	t.Run("global stats are summed by the repository", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := playerMocks.NewMockPlayerRepository(ctrl)
+		repo.EXPECT().GetPlayerTotals(gomock.Any(), "testnet", "bob").
+			Return(&player2.Player{Name: "bob", Points: 2500, Count: 40, Eggs: 7, Shots: 50, Misses: 10}, nil)
+
+		client := &mockIRCClientForTest{}
+		g := NewGame(config.GameConfig{}, client, repo, "testnet", "#pigeons")
+
+		require.NoError(t, g.HandleStats(context.Background(), "--global", "bob"))
+		require.Len(t, client.messages, 2)
+		assert.Contains(t, client.messages[0], "bob on testnet")
+		assert.Contains(t, client.messages[0], "2,500 points")
+		assert.Contains(t, client.messages[1], "50 shots (80.0% hit)")
+		assert.NotContains(t, client.messages[1], "Rank:")
+	})
+
+	t.Run("global stats for a new player", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := playerMocks.NewMockPlayerRepository(ctrl)
+		repo.EXPECT().GetPlayerTotals(gomock.Any(), "testnet", "carol").Return(nil, nil)
+
+		client := &mockIRCClientForTest{}
+		g := NewGame(config.GameConfig{}, client, repo, "testnet", "#pigeons")
+
+		require.NoError(t, g.HandleStats(context.Background(), "carol", "--global"))
+		assert.Equal(t, []string{"📊 carol hasn't shot any pigeons on testnet yet"}, client.messages)
+	})
+}
+
+func TestScorePool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := playerMocks.NewMockPlayerRepository(ctrl)
+
+	cfg := config.GameConfig{ScorePools: map[string]string{"#offtopic": "#pigeons"}}
+	shared := &Players{}
+	main := NewGame(cfg, &mockIRCClientForTest{}, repo, "testnet", "#pigeons", WithScorePool(shared))
+	offtopic := NewGame(cfg, &mockIRCClientForTest{}, repo, "testnet", "#offtopic", WithScorePool(shared))
+
+	assert.Equal(t, "#pigeons", offtopic.scope())
+	assert.Equal(t, "#pigeons", main.scope())
+
+	// the first game loads the pool, the second reuses it
+	repo.EXPECT().GetAllPlayers(gomock.Any(), "testnet", "#pigeons").
+		Return([]*player2.Player{{Name: "alice", Points: 10, Count: 1}}, nil).Times(1)
+	main.syncPlayers(context.Background())
+	offtopic.syncPlayers(context.Background())
+
+	// a kill in one channel is stored under the pool and shows in the other
+	offtopic.activePigeon.activePigeon = &pigeon.Pigeon{Type: "fat", Points: 5, Success: 100}
+	repo.EXPECT().AwardKill(gomock.Any(), "testnet", "#pigeons", "alice", 5).
+		Return(&player2.Player{Name: "alice", Points: 15, Count: 2}, nil)
+	require.NoError(t, offtopic.HandleShoot(context_manager.WithNick(context.Background(), "alice")))
+
+	p, err := main.FindPlayer(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 15, p.Points)
+	assert.Equal(t, 2, p.Count)
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"context"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -149,6 +150,10 @@ type mockPlayerRepositoryForTest struct {
 	findErr      error
 	getEggsErr   error
 	getRareErr   error
+	editErr      error
+	scoreErr     error
+	edits        []string
+	writes       atomic.Int32 // score writes tried, failed ones included
 }
 
 func newMockPlayerRepoForTest() *mockPlayerRepositoryForTest {
@@ -175,6 +180,14 @@ func (m *mockPlayerRepositoryForTest) TopByPoints(ctx context.Context, network,
 	return nil, nil
 }
 
+func (m *mockPlayerRepositoryForTest) TopByPointsNetwork(ctx context.Context, network string, limit int) ([]*player.Player, error) {
+	return nil, nil
+}
+
+func (m *mockPlayerRepositoryForTest) GetPlayerTotals(ctx context.Context, network, name string) (*player.Player, error) {
+	return nil, nil
+}
+
 func (m *mockPlayerRepositoryForTest) AddEggs(ctx context.Context, network, channel, name string, delta int) (int, error) {
 	if m.addEggsErr != nil {
 		return 0, m.addEggsErr
@@ -209,6 +222,76 @@ func (m *mockPlayerRepositoryForTest) GetRareEggs(ctx context.Context, network,
 	return m.rareEggs[key], nil
 }
 
+func (m *mockPlayerRepositoryForTest) CollectEggs(ctx context.Context, network, channel, name string, eggs, rareEggs int) (*player.Player, error) {
+	if m.addEggsErr != nil {
+		return nil, m.addEggsErr
+	}
+	if rareEggs > 0 && m.addRareErr != nil {
+		return nil, m.addRareErr
+	}
+	key := network + "|" + channel + "|" + name
+	m.eggs[key] += eggs
+	m.rareEggs[key] += rareEggs
+	return m.score(network, channel, name, func(p *player.Player) {})
+}
+
+func (m *mockPlayerRepositoryForTest) AwardKill(ctx context.Context, network, channel, name string, points int) (*player.Player, error) {
+	return m.score(network, channel, name, func(p *player.Player) {
+		p.Points += points
+		p.Count++
+		p.Shots++
+		p.Streak++
+		p.BestStreak = max(p.BestStreak, p.Streak)
+	})
+}
+
+func (m *mockPlayerRepositoryForTest) RecordMiss(ctx context.Context, network, channel, name string) (*player.Player, error) {
+	return m.score(network, channel, name, func(p *player.Player) {
+		p.Shots++
+		p.Misses++
+		p.Streak = 0
+	})
+}
+
+func (m *mockPlayerRepositoryForTest) AddPoints(ctx context.Context, network, channel, name string, points int) (*player.Player, error) {
+	return m.score(network, channel, name, func(p *player.Player) {
+		p.Points += points
+	})
+}
+
+// score applies change to the stored player, creating it if needed, and returns a copy
+func (m *mockPlayerRepositoryForTest) score(network, channel, name string, change func(p *player.Player)) (*player.Player, error) {
+	m.writes.Add(1)
+	if m.scoreErr != nil {
+		return nil, m.scoreErr
+	}
+	key := network + "|" + channel + "|" + name
+	p, ok := m.players[key]
+	if !ok {
+		p = &player.Player{Name: name, Network: network, Channel: channel}
+		m.players[key] = p
+	}
+	change(p)
+	p.Eggs, p.RareEggs = m.eggs[key], m.rareEggs[key]
+	stored := *p
+	return &stored, nil
+}
+
+func (m *mockPlayerRepositoryForTest) MergePlayers(ctx context.Context, network, channel, from, into string) error {
+	m.edits = append(m.edits, "merge "+channel+" "+from+" "+into)
+	return m.editErr
+}
+
+func (m *mockPlayerRepositoryForTest) RenamePlayer(ctx context.Context, network, channel, from, to string) error {
+	m.edits = append(m.edits, "rename "+channel+" "+from+" "+to)
+	return m.editErr
+}
+
+func (m *mockPlayerRepositoryForTest) DeletePlayer(ctx context.Context, network, channel, name string) error {
+	m.edits = append(m.edits, "delete "+channel+" "+name)
+	return m.editErr
+}
+
 // TestTryRareEgg_Probabilistic runs many iterations to hit random paths
 func TestTryRareEgg_Probabilistic(t *testing.T) {
 	mockRepo := newMockPlayerRepoForTest()
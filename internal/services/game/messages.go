@@ -0,0 +1,453 @@
+package game
+
+import (
+	"maps"
+	"slices"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const defaultLocale = "en"
+
+// message IDs of the game's announcements
+const (
+	msgEscape          = "escape"
+	msgShootCooldown   = "shoot.cooldown"
+	msgShootNothing    = "shoot.nothing"
+	msgShootError      = "shoot.error"
+	msgShootHit        = "shoot.hit"
+	msgShootMiss       = "shoot.miss"
+	msgShootImpostor   = "shoot.impostor"
+	msgEggsAllCracked  = "eggs.all_cracked"
+	msgEggsSomeCracked = "eggs.some_cracked"
+	msgEggsCollected   = "eggs.collected"
+	msgEggsTotal       = "eggs.total"
+	msgRareCracked     = "rare.cracked"
+	msgRareCollected   = "rare.collected"
+	msgHelp            = "help"
+	msgBef             = "bef"
+	msgPong            = "pong"
+	msgPongTimeout     = "pong.timeout"
+	msgEggsError       = "eggs.error"
+
+	// leaderboards and stats; labels are the coloured parts of a row
+	msgTopHeader              = "top.header"
+	msgTopHeaderNetwork       = "top.header.network"
+	msgTopHeaderWindow        = "top.header.window"
+	msgTopHeaderWindowNetwork = "top.header.window.network"
+	msgTopError               = "top.error"
+	msgTopUsage               = "top.usage"
+	msgTopFixedUsage          = "top.usage.fixed"
+	msgTopNoHistory           = "top.no_history"
+	msgTopEmpty               = "top.empty"
+	msgWindowToday            = "window.today"
+	msgWindowWeek             = "window.week"
+	msgWindowMonth            = "window.month"
+	msgWindowSeason           = "window.season"
+	msgWindowRange            = "window.range"
+	msgLabelPoints            = "label.points"
+	msgLabelPigeons           = "label.pigeons"
+	msgLabelLevel             = "label.level"
+	msgLabelEggs              = "label.eggs"
+	msgLabelRare              = "label.rare"
+	msgLabelBadges            = "label.badges"
+	msgLabelShots             = "label.shots"
+	msgLabelBestStreak        = "label.best_streak"
+	msgLabelRank              = "label.rank"
+	msgLabelNextLevel         = "label.next_level"
+	msgLabelTopLevel          = "label.top_level"
+	msgLabelKills             = "label.kills"
+	msgStatsError             = "stats.error"
+	msgStatsNone              = "stats.none"
+	msgStatsHere              = "stats.here"
+	msgStatsNetwork           = "stats.network"
+
+	// paginated listings such as !score
+	msgListingUsage  = "listing.usage"
+	msgListingEmpty  = "listing.empty"
+	msgListingNoPage = "listing.no_page"
+	msgListingMore   = "listing.more"
+	msgListingPage   = "listing.page"
+
+	// seasons
+	msgSeasonDisabled = "season.disabled"
+	msgSeasonError    = "season.error"
+	msgSeasonUsage    = "season.usage"
+	msgSeasonNotYet   = "season.not_yet"
+	msgSeasonFinal    = "season.final"
+	msgSeasonNobody   = "season.nobody"
+	msgSeasonCurrent  = "season.current"
+	msgSeasonOver     = "season.over"
+	msgSeasonOverNone = "season.over.nobody"
+	msgSeasonPodium   = "season.podium"
+	msgSeasonBegun    = "season.begun"
+	msgSeasonBadge    = "season.badge"
+
+	// operator commands
+	msgGameNotRunning = "game.not_running"
+	msgGameStopped    = "game.stopped"
+	msgGamePaused     = "game.paused"
+	msgGameResumed    = "game.resumed"
+	msgSpawnUnknown   = "spawn.unknown"
+	msgSetUsage       = "set.usage"
+	msgSetDone        = "set.done"
+	msgSetSaveError   = "set.save_error"
+	msgSetInvalid     = "set.invalid"
+	msgSetUnknown     = "set.unknown"
+	msgSetNumber      = "set.number"
+	msgSetSeconds     = "set.seconds"
+	msgSetTimezone    = "set.timezone"
+	msgSetOneOf       = "set.one_of"
+	msgPlayerUsage    = "player.usage"
+	msgPlayerMerged   = "player.merged"
+	msgPlayerRenamed  = "player.renamed"
+	msgPlayerDeleted  = "player.deleted"
+	msgPlayerNotFound = "player.not_found"
+	msgPlayerExists   = "player.exists"
+	msgPlayerError    = "player.error"
+
+	// spawn announcements are "action." plus the action's name, with the
+	// pigeon type, the action and the item as arguments; without a template
+	// the action's own Format is used
+	msgActionPrefix = "action."
+	// items and level titles are looked up as "item." or "level." plus the
+	// English text, which is used as is when there is no translation
+	msgItemPrefix  = "item."
+	msgLevelPrefix = "level."
+)
+
+// message IDs of replies the bot and the command controller send on a game's
+// behalf, rendered with Msg
+const (
+	MsgAlreadyRunning = "bot.already_running"
+	MsgQueueStats     = "bot.queue"
+	MsgJoinUsage      = "bot.join.usage"
+	MsgJoinFailed     = "bot.join.failed"
+	MsgJoining        = "bot.join.done"
+	MsgPartUsage      = "bot.part.usage"
+	MsgNotInChannel   = "bot.part.not_in"
+	MsgPartingHere    = "bot.part.here"
+	MsgParting        = "bot.part.done"
+	// MsgNotAllowedPrefix is followed by the permission level's name
+	MsgNotAllowedPrefix = "not_allowed."
+)
+
+// messages holds message templates by locale and message ID
+type messages map[string]map[string]string
+
+// builtinMessages are the templates shipped with the bot. English must have
+// every message ID; other locales fall back to it.
+var builtinMessages = messages{
+	"en": {
+		msgEscape:          "🕊️ ~ coo coo ~ the %s pigeon has made a clean escape ~ 🕊️",
+		msgShootCooldown:   "...%s slow down... you can shoot again in %.1f seconds ⏳🕊️",
+		msgShootNothing:    "❗⚠️ %s has shot a pigeon!, but there are no pigeons to shoot! - - 🐦",
+		msgShootError:      "❗⚠️ %s has shot a pigeon, but there was an error saving the score! - - 🐦",
+		msgShootHit:        "❗⚠️ %s has shot a pigeon! - - 🐦 🔫 You are a murderer! . .  You have shot a total of %s pigeon(s)! . . 🐦 🕊️ . . You now have a total of %s points and reached the level: %s",
+		msgShootMiss:       "❗⚠️ %s has shot a pigeon, but it got away! - - 🐦",
+		msgShootImpostor:   "🔒 %s belongs to a registered player, log in to services to shoot with it",
+		msgEggsAllCracked:  "🥚💥 Oh no... :( the eggs cracked during the chaos! - no eggs collected ... You now have %s egg(s) in total.",
+		msgEggsSomeCracked: "🥚🐣 Yay!! %s has collected %s egg(s) ... Unfortunately, %s cracked!... You now have %s egg(s) in total.",
+		msgEggsCollected:   "%s collected %s egg(s)! Total eggs: %s (Rare egg(s): %s 🌟🥚)",
+		msgEggsTotal:       "🥚 %s has %s egg(s) total — including %s rare egg(s) 🌟🥚",
+		msgRareCracked:     "✨ A mysterious rare egg appeared for %s ... but it cracked and vanished! 💥",
+		msgRareCollected:   "🌟 WOW! %s collected a LEGENDARY rare egg! 🚀🚀🚀🚀🚀 +%s points with +1 egg 🥚 | Eggs: %s (Rare: %s) | Points: %s",
+		msgHelp:            "Commands: !shoot, !score, !pigeons, !bef, !help, !level, !top, !top5, !top10, !stats, !season, !eggs, !set",
+		msgBef:             "🕊️ ~ coo coo ~ cannot be frens with a rat of the sky ~ 🕊️",
+		msgPong:            "%s: Pong (%.3fs)",
+		msgPongTimeout:     "%s: Pong (timeout)",
+		msgEggsError:       "🥚 Error fetching eggs",
+
+		msgTopHeader:              "🏆 Top %d Pigeon Hunters",
+		msgTopHeaderNetwork:       "🏆 Top %d Pigeon Hunters on %s",
+		msgTopHeaderWindow:        "🏆 Top %d Pigeon Hunters %s",
+		msgTopHeaderWindowNetwork: "🏆 Top %d Pigeon Hunters on %s %s",
+		msgTopError:               "Error fetching top players",
+		msgTopUsage:               "🏆 usage: !top [1-10] [today|week|month|season|YYYY-MM-DD..YYYY-MM-DD] [--global]",
+		msgTopFixedUsage:          "🏆 usage: !top%d [today|week|month|season|YYYY-MM-DD..YYYY-MM-DD] [--global]",
+		msgTopNoHistory:           "🏆 No shot history is kept here",
+		msgTopEmpty:               "🕊️ Nobody has scored yet, the board is yours to take!",
+		msgWindowToday:            "today",
+		msgWindowWeek:             "this week",
+		msgWindowMonth:            "this month",
+		msgWindowSeason:           "in season %d",
+		msgWindowRange:            "%s to %s",
+		msgLabelPoints:            "%s points",
+		msgLabelPigeons:           "%s pigeons",
+		msgLabelLevel:             "Level: %s ",
+		msgLabelEggs:              "Eggs: %s",
+		msgLabelRare:              "Rare: %s 🌟",
+		msgLabelBadges:            " | Badges: %s",
+		msgLabelShots:             "%s shots (%.1f%% hit)",
+		msgLabelBestStreak:        "Best streak: %s",
+		msgLabelRank:              "Rank: #%d of %d",
+		msgLabelNextLevel:         "%s more to %s",
+		msgLabelTopLevel:          "Top level reached",
+		msgLabelKills:             "Kills: %s",
+		msgStatsError:             "📊 Error fetching stats",
+		msgStatsNone:              "📊 %s hasn't shot any pigeons %s yet",
+		msgStatsHere:              "here",
+		msgStatsNetwork:           "on %s",
+
+		msgListingUsage:  "📄 usage: %s [page N]",
+		msgListingEmpty:  "🕊️ Nobody has shot a pigeon yet",
+		msgListingNoPage: "📄 %s only has %d page(s)",
+		msgListingMore:   "(page %d/%d, %s page %d for more)",
+		msgListingPage:   "(page %d/%d)",
+
+		msgSeasonDisabled: "🏁 Seasons are not enabled here",
+		msgSeasonError:    "🏁 Error loading the season",
+		msgSeasonUsage:    "🏁 usage: !season [number]",
+		msgSeasonNotYet:   "🏁 There is no season %d yet, this is season %d",
+		msgSeasonFinal:    "🏁 Season %d final standings (%s to %s)",
+		msgSeasonNobody:   "🕊️ Nobody scored that season",
+		msgSeasonCurrent:  "🏁 Season %d ends %s (%d day(s) left)",
+		msgSeasonOver:     "🏁 Season %d is over! %s",
+		msgSeasonOverNone: "🏁 Season %d is over! Nobody scored this time.",
+		msgSeasonPodium:   "%s %s (%s points)",
+		msgSeasonBegun:    "🏁 Season %d has begun and runs until %s. Scores keep %d%% - go climb the board!",
+		msgSeasonBadge:    "%sS%d",
+
+		msgGameNotRunning: "🕊️ The game is not running",
+		msgGameStopped:    "🛑 The game has been stopped. Use !start to play again",
+		msgGamePaused:     "⏸️ No new pigeons until !resume",
+		msgGameResumed:    "▶️ Pigeons are back",
+		msgSpawnUnknown:   "🕊️ Unknown pigeon type, try: %s",
+		msgSetUsage:       "⚙️ %s | usage: !set <key> <value>",
+		msgSetDone:        "⚙️ %s is now %s",
+		msgSetSaveError:   "⚙️ Error saving setting",
+		msgSetInvalid:     "⚙️ %s",
+		msgSetUnknown:     "unknown setting %q (try: %s)",
+		msgSetNumber:      "%s must be a number from %d to %d",
+		msgSetSeconds:     "%s must be a number of seconds from %d to %d",
+		msgSetTimezone:    "%s must be a time zone such as UTC or Asia/Bangkok",
+		msgSetOneOf:       "%s must be one of %s",
+		msgPlayerUsage:    "⚙️ usage: !player merge <from> <into> | !player rename <from> <to> | !player delete <nick>",
+		msgPlayerMerged:   "⚙️ Merged %s into %s",
+		msgPlayerRenamed:  "⚙️ Renamed %s to %s",
+		msgPlayerDeleted:  "⚙️ Deleted %s",
+		msgPlayerNotFound: "⚙️ %s has no score here",
+		msgPlayerExists:   "⚙️ %s already has a score here, use !player merge",
+		msgPlayerError:    "⚙️ Error updating players",
+
+		MsgAlreadyRunning:                 "🕊️ The game is already running",
+		MsgQueueStats:                     "📬 Outbound queue on %s: %d waiting, %d sent, %d coalesced, %d dropped",
+		MsgJoinUsage:                      "🕊️ usage: !join #channel",
+		MsgJoinFailed:                     "🕊️ Could not join %s",
+		MsgJoining:                        "🕊️ Flying over to %s",
+		MsgPartUsage:                      "🕊️ usage: !part [#channel]",
+		MsgNotInChannel:                   "🕊️ I'm not in %s",
+		MsgPartingHere:                    "🕊️ Coo coo, flying away!",
+		MsgParting:                        "🕊️ Leaving %s",
+		MsgNotAllowedPrefix + "voice":     "Sorry, %s is only for voiced users",
+		MsgNotAllowedPrefix + "operator":  "Sorry, %s is only for operators",
+		MsgNotAllowedPrefix + "bot owner": "Sorry, %s is only for bot owners",
+	},
+	"th": {
+		msgEscape:          "🕊️ ~ กุกกู ~ นกพิราบ %s บินหนีไปได้อย่างหมดจด ~ 🕊️",
+		msgShootCooldown:   "...%s ใจเย็น ๆ... ยิงได้อีกครั้งในอีก %.1f วินาที ⏳🕊️",
+		msgShootNothing:    "❗⚠️ %s ยิงนกพิราบ! แต่ไม่มีนกพิราบให้ยิงเลย! - - 🐦",
+		msgShootError:      "❗⚠️ %s ยิงนกพิราบ แต่บันทึกคะแนนไม่สำเร็จ! - - 🐦",
+		msgShootHit:        "❗⚠️ %s ยิงนกพิราบโดนแล้ว! - - 🐦 🔫 ฆาตกรชัด ๆ! . .  ยิงนกพิราบไปทั้งหมด %s ตัว! . . 🐦 🕊️ . . ตอนนี้มีคะแนนรวม %s คะแนน อยู่ในระดับ: %s",
+		msgShootMiss:       "❗⚠️ %s ยิงนกพิราบ แต่มันหนีไปได้! - - 🐦",
+		msgShootImpostor:   "🔒 %s เป็นชื่อของผู้เล่นที่ลงทะเบียนแล้ว ล็อกอินกับ services ก่อนจึงจะยิงด้วยชื่อนี้ได้",
+		msgEggsAllCracked:  "🥚💥 โอ้ไม่... :( ไข่แตกหมดระหว่างความวุ่นวาย! - ไม่ได้ไข่สักฟอง ... ตอนนี้มีไข่รวม %s ฟอง",
+		msgEggsSomeCracked: "🥚🐣 เย้!! %s เก็บไข่ได้ %s ฟอง ... แต่น่าเสียดาย แตกไป %s ฟอง!... ตอนนี้มีไข่รวม %s ฟอง",
+		msgEggsCollected:   "%s เก็บไข่ได้ %s ฟอง! ไข่ทั้งหมด: %s (ไข่หายาก: %s 🌟🥚)",
+		msgEggsTotal:       "🥚 %s มีไข่ทั้งหมด %s ฟอง — เป็นไข่หายาก %s ฟอง 🌟🥚",
+		msgRareCracked:     "✨ ไข่หายากลึกลับปรากฏขึ้นให้ %s ... แต่มันแตกและหายไป! 💥",
+		msgRareCollected:   "🌟 ว้าว! %s เก็บไข่หายากระดับตำนานได้! 🚀🚀🚀🚀🚀 +%s คะแนน พร้อมไข่ +1 ฟอง 🥚 | ไข่: %s (หายาก: %s) | คะแนน: %s",
+		msgHelp:            "คำสั่ง: !shoot, !score, !pigeons, !bef, !help, !level, !top, !top5, !top10, !stats, !season, !eggs, !set",
+		msgBef:             "🕊️ ~ กุกกู ~ เป็นเพื่อนกับหนูมีปีกไม่ได้หรอก ~ 🕊️",
+		msgPong:            "%s: Pong (%.3f วินาที)",
+		msgPongTimeout:     "%s: Pong (หมดเวลา)",
+		msgEggsError:       "🥚 ดึงข้อมูลไข่ไม่สำเร็จ",
+
+		msgTopHeader:              "🏆 %d อันดับนักล่านกพิราบ",
+		msgTopHeaderNetwork:       "🏆 %d อันดับนักล่านกพิราบบน %s",
+		msgTopHeaderWindow:        "🏆 %d อันดับนักล่านกพิราบ %s",
+		msgTopHeaderWindowNetwork: "🏆 %d อันดับนักล่านกพิราบบน %s %s",
+		msgTopError:               "ดึงรายชื่อผู้เล่นอันดับต้น ๆ ไม่สำเร็จ",
+		msgTopUsage:               "🏆 วิธีใช้: !top [1-10] [today|week|month|season|YYYY-MM-DD..YYYY-MM-DD] [--global]",
+		msgTopFixedUsage:          "🏆 วิธีใช้: !top%d [today|week|month|season|YYYY-MM-DD..YYYY-MM-DD] [--global]",
+		msgTopNoHistory:           "🏆 ที่นี่ไม่ได้เก็บประวัติการยิง",
+		msgTopEmpty:               "🕊️ ยังไม่มีใครทำคะแนนได้เลย กระดานนี้รอคุณอยู่!",
+		msgWindowToday:            "วันนี้",
+		msgWindowWeek:             "สัปดาห์นี้",
+		msgWindowMonth:            "เดือนนี้",
+		msgWindowSeason:           "ในฤดูกาลที่ %d",
+		msgWindowRange:            "%s ถึง %s",
+		msgLabelPoints:            "%s คะแนน",
+		msgLabelPigeons:           "นกพิราบ %s ตัว",
+		msgLabelLevel:             "ระดับ: %s ",
+		msgLabelEggs:              "ไข่: %s",
+		msgLabelRare:              "หายาก: %s 🌟",
+		msgLabelBadges:            " | เหรียญ: %s",
+		msgLabelShots:             "ยิง %s นัด (โดน %.1f%%)",
+		msgLabelBestStreak:        "ยิงโดนติดกันสูงสุด: %s",
+		msgLabelRank:              "อันดับ: #%d จาก %d",
+		msgLabelNextLevel:         "อีก %s ถึง %s",
+		msgLabelTopLevel:          "ถึงระดับสูงสุดแล้ว",
+		msgLabelKills:             "ยิงได้: %s",
+		msgStatsError:             "📊 ดึงสถิติไม่สำเร็จ",
+		msgStatsNone:              "📊 %s ยังไม่ได้ยิงนกพิราบ %s เลย",
+		msgStatsHere:              "ที่นี่",
+		msgStatsNetwork:           "บน %s",
+
+		msgListingUsage:  "📄 วิธีใช้: %s [page N]",
+		msgListingEmpty:  "🕊️ ยังไม่มีใครยิงนกพิราบเลย",
+		msgListingNoPage: "📄 %s มีแค่ %d หน้า",
+		msgListingMore:   "(หน้า %d/%d ดูต่อด้วย %s page %d)",
+		msgListingPage:   "(หน้า %d/%d)",
+
+		msgSeasonDisabled: "🏁 ที่นี่ไม่ได้เปิดใช้ฤดูกาล",
+		msgSeasonError:    "🏁 โหลดฤดูกาลไม่สำเร็จ",
+		msgSeasonUsage:    "🏁 วิธีใช้: !season [หมายเลข]",
+		msgSeasonNotYet:   "🏁 ยังไม่มีฤดูกาลที่ %d ตอนนี้เป็นฤดูกาลที่ %d",
+		msgSeasonFinal:    "🏁 อันดับสุดท้ายของฤดูกาลที่ %d (%s ถึง %s)",
+		msgSeasonNobody:   "🕊️ ฤดูกาลนั้นไม่มีใครทำคะแนนได้",
+		msgSeasonCurrent:  "🏁 ฤดูกาลที่ %d สิ้นสุด %s (เหลืออีก %d วัน)",
+		msgSeasonOver:     "🏁 ฤดูกาลที่ %d จบแล้ว! %s",
+		msgSeasonOverNone: "🏁 ฤดูกาลที่ %d จบแล้ว! รอบนี้ไม่มีใครทำคะแนนได้",
+		msgSeasonPodium:   "%s %s (%s คะแนน)",
+		msgSeasonBegun:    "🏁 ฤดูกาลที่ %d เริ่มแล้ว ไปจนถึง %s คะแนนคงเหลือ %d%% - ไต่อันดับกันเลย!",
+		msgSeasonBadge:    "%sS%d",
+
+		msgGameNotRunning: "🕊️ เกมไม่ได้เล่นอยู่",
+		msgGameStopped:    "🛑 หยุดเกมแล้ว ใช้ !start เพื่อเล่นอีกครั้ง",
+		msgGamePaused:     "⏸️ จะไม่มีนกพิราบใหม่จนกว่าจะ !resume",
+		msgGameResumed:    "▶️ นกพิราบกลับมาแล้ว",
+		msgSpawnUnknown:   "🕊️ ไม่รู้จักนกพิราบชนิดนี้ ลอง: %s",
+		msgSetUsage:       "⚙️ %s | วิธีใช้: !set <key> <value>",
+		msgSetDone:        "⚙️ ตั้ง %s เป็น %s แล้ว",
+		msgSetSaveError:   "⚙️ บันทึกการตั้งค่าไม่สำเร็จ",
+		msgSetInvalid:     "⚙️ %s",
+		msgSetUnknown:     "ไม่รู้จักการตั้งค่า %q (ลอง: %s)",
+		msgSetNumber:      "%s ต้องเป็นตัวเลขตั้งแต่ %d ถึง %d",
+		msgSetSeconds:     "%s ต้องเป็นจำนวนวินาทีตั้งแต่ %d ถึง %d",
+		msgSetTimezone:    "%s ต้องเป็นเขตเวลา เช่น UTC หรือ Asia/Bangkok",
+		msgSetOneOf:       "%s ต้องเป็นหนึ่งใน %s",
+		msgPlayerUsage:    "⚙️ วิธีใช้: !player merge <จาก> <เข้า> | !player rename <จาก> <เป็น> | !player delete <nick>",
+		msgPlayerMerged:   "⚙️ รวม %s เข้ากับ %s แล้ว",
+		msgPlayerRenamed:  "⚙️ เปลี่ยนชื่อ %s เป็น %s แล้ว",
+		msgPlayerDeleted:  "⚙️ ลบ %s แล้ว",
+		msgPlayerNotFound: "⚙️ %s ยังไม่มีคะแนนที่นี่",
+		msgPlayerExists:   "⚙️ %s มีคะแนนที่นี่อยู่แล้ว ใช้ !player merge แทน",
+		msgPlayerError:    "⚙️ แก้ไขข้อมูลผู้เล่นไม่สำเร็จ",
+
+		MsgAlreadyRunning:                 "🕊️ เกมกำลังเล่นอยู่แล้ว",
+		MsgQueueStats:                     "📬 คิวข้อความขาออกบน %s: รอส่ง %d ส่งแล้ว %d รวมซ้ำ %d ทิ้ง %d",
+		MsgJoinUsage:                      "🕊️ วิธีใช้: !join #channel",
+		MsgJoinFailed:                     "🕊️ เข้า %s ไม่สำเร็จ",
+		MsgJoining:                        "🕊️ กำลังบินไปที่ %s",
+		MsgPartUsage:                      "🕊️ วิธีใช้: !part [#channel]",
+		MsgNotInChannel:                   "🕊️ ฉันไม่ได้อยู่ใน %s",
+		MsgPartingHere:                    "🕊️ กุกกู บินไปแล้วนะ!",
+		MsgParting:                        "🕊️ กำลังออกจาก %s",
+		MsgNotAllowedPrefix + "voice":     "ขออภัย %s ใช้ได้เฉพาะผู้ที่มี voice",
+		MsgNotAllowedPrefix + "operator":  "ขออภัย %s ใช้ได้เฉพาะโอเปอเรเตอร์",
+		MsgNotAllowedPrefix + "bot owner": "ขออภัย %s ใช้ได้เฉพาะเจ้าของบอท",
+
+		msgActionPrefix + "stole":               "❗⚠️ นกพิราบ %[1]s ขโมย%[3]sของคุณไป - - 🐦",
+		msgActionPrefix + "pooped":              "❗⚠️ นกพิราบ %[1]s อึใส่%[3]sของคุณ - - 🐦",
+		msgActionPrefix + "landed":              "❗⚠️ นกพิราบ %[1]s มาเกาะบน%[3]sของคุณ - - 🐦",
+		msgActionPrefix + "mating":              "❗⚠️ นกพิราบ %[1]s กำลังผสมพันธุ์กันที่%[3]sของคุณ - - 🕊️ 💕 🕊️",
+		msgItemPrefix + "tv 📺":                  "ทีวี 📺",
+		msgItemPrefix + "wallet 💰👛":             "กระเป๋าเงิน 💰👛",
+		msgItemPrefix + "food 🍔 🍕 🍪 🌮":          "อาหาร 🍔 🍕 🍪 🌮",
+		msgItemPrefix + "car 🚗":                 "รถ 🚗",
+		msgItemPrefix + "head 👤":                "หัว 👤",
+		msgItemPrefix + "laptop 💻":              "แล็ปท็อป 💻",
+		msgItemPrefix + "balcony 🏠🌿":            "ระเบียง 🏠🌿",
+		msgItemPrefix + "house 🏠":               "บ้าน 🏠",
+		msgItemPrefix + "swimming pool 🏖️":      "สระว่ายน้ำ 🏖️",
+		msgItemPrefix + "bed 🛏️":                "เตียง 🛏️",
+		msgItemPrefix + "couch 🛋️":              "โซฟา 🛋️",
+		msgLevelPrefix + "Beginner 🐣":           "มือใหม่ 🐣",
+		msgLevelPrefix + "Initiate 🐦":           "ผู้ฝึกหัด 🐦",
+		msgLevelPrefix + "Adept 🦅":              "ผู้ชำนาญ 🦅",
+		msgLevelPrefix + "Expert 🕊️":            "ผู้เชี่ยวชาญ 🕊️",
+		msgLevelPrefix + "Master 🦜":             "ปรมาจารย์ 🦜",
+		msgLevelPrefix + "Grandmaster 🐔":        "ปรมาจารย์ใหญ่ 🐔",
+		msgLevelPrefix + "Legendary Phoenix 🐉🔥": "หงส์เพลิงในตำนาน 🐉🔥",
+		msgLevelPrefix + "Mythic Dragon 🐲✨":     "มังกรแห่งตำนาน 🐲✨",
+		msgLevelPrefix + "Cosmic Falcon 🌌🦅":     "เหยี่ยวจักรวาล 🌌🦅",
+		msgLevelPrefix + "Lord of Pigeons 👑🐦":   "เจ้าแห่งนกพิราบ 👑🐦",
+		msgLevelPrefix + "Pigeon Emperor 🏯🐦":    "จักรพรรดินกพิราบ 🏯🐦",
+		msgLevelPrefix + "Sky Tyrant ☁️🐲":       "ทรราชแห่งฟากฟ้า ☁️🐲",
+		msgLevelPrefix + "Celestial Hunter 🌠🦅":  "นักล่าแห่งสวรรค์ 🌠🦅",
+		msgLevelPrefix + "Eternal Wing 🕊️♾️":    "ปีกนิรันดร์ 🕊️♾️",
+		msgLevelPrefix + "Pigeon God ☄️👁️":      "เทพเจ้านกพิราบ ☄️👁️",
+	},
+}
+
+// locales lists the locales !set locale accepts, sorted
+func locales() []string {
+	return slices.Sorted(maps.Keys(builtinMessages))
+}
+
+// newMessages returns the built-in templates with overrides applied in every locale
+func newMessages(overrides map[string]string) messages {
+	m := make(messages, len(builtinMessages))
+	for locale, templates := range builtinMessages {
+		merged := maps.Clone(templates)
+		maps.Copy(merged, overrides)
+		m[locale] = merged
+	}
+	return m
+}
+
+// catalogue returns the game's templates; games built without NewGame use the built-in ones
+func (g *Game) catalogue() messages {
+	if g.messages == nil {
+		return builtinMessages
+	}
+	return g.messages
+}
+
+// locale returns the channel's locale
+func (g *Game) locale() string {
+	if locale := g.Settings().Locale; locale != "" {
+		return locale
+	}
+	return defaultLocale
+}
+
+// msg renders message id in the channel's locale, falling back to English
+// and then to id itself
+func (g *Game) msg(id string, args ...any) string {
+	locale := g.locale()
+	template, ok := g.catalogue()[locale][id]
+	if !ok {
+		template, ok = g.catalogue()[defaultLocale][id]
+	}
+	if !ok {
+		template = id
+	}
+	return message.NewPrinter(language.Make(locale)).Sprintf(template, args...)
+}
+
+// Msg renders message id in the channel's locale, for replies sent on the
+// game's behalf
+func (g *Game) Msg(id string, args ...any) string {
+	return g.msg(id, args...)
+}
+
+// translate returns the channel's translation of an item or level title, or
+// the text itself when there is none
+func (g *Game) translate(prefix, text string) string {
+	if translated, ok := g.catalogue()[g.locale()][prefix+text]; ok {
+		return translated
+	}
+	return text
+}
+
+// announce renders a spawn announcement in the channel's locale, falling
+// back to the action's own Format
+func (g *Game) announce(actionName, format, pigeonType, item string) string {
+	if template, ok := g.catalogue()[g.locale()][msgActionPrefix+actionName]; ok {
+		format = template
+	}
+	return message.NewPrinter(language.Make(g.locale())).Sprintf(format, pigeonType, actionName, g.translate(msgItemPrefix, item))
+}
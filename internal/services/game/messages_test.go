@@ -0,0 +1,177 @@
+package game
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinMessages_EnglishIsComplete(t *testing.T) {
+	for locale, templates := range builtinMessages {
+		for id := range templates {
+			// actions, items and levels fall back to the English text itself
+			if strings.HasPrefix(id, msgActionPrefix) || strings.HasPrefix(id, msgItemPrefix) || strings.HasPrefix(id, msgLevelPrefix) {
+				continue
+			}
+			assert.Contains(t, builtinMessages[defaultLocale], id, "%s message %q has no English template", locale, id)
+		}
+	}
+}
+
+func TestGame_Msg(t *testing.T) {
+	g := &Game{channel: "#pigeons"}
+	assert.Equal(t, "🕊️ ~ coo coo ~ the boss pigeon has made a clean escape ~ 🕊️", g.msg(msgEscape, "boss"))
+
+	g.settings = &Settings{Locale: "th"}
+	assert.Equal(t, "🕊️ ~ กุกกู ~ นกพิราบ boss บินหนีไปได้อย่างหมดจด ~ 🕊️", g.msg(msgEscape, "boss"))
+	assert.Equal(t, "มือใหม่ 🐣", g.LevelFor(0, 0))
+
+	// missing translations fall back to English, unknown IDs to the ID
+	g.messages = messages{"en": {"hello": "hello %s"}, "th": {}}
+	assert.Equal(t, "hello alice", g.msg("hello", "alice"))
+	assert.Equal(t, "nope", g.msg("nope"))
+}
+
+func TestNewGame_MessageOverrides(t *testing.T) {
+	cfg := config.GameConfig{Interval: 30, Messages: config.MessageCatalogue{
+		Messages: map[string]string{"bef": "🐀 no frens"},
+		Channels: map[string]map[string]string{"#Pigeons": {"bef": "🐀 no frens in #pigeons"}},
+	}}
+	client := &mockIRCClientForTest{}
+	g := NewGame(cfg, client, newMockPlayerRepoForTest(), "testnet", "#pigeons")
+	other := NewGame(cfg, client, newMockPlayerRepoForTest(), "testnet", "#other")
+
+	require.NoError(t, other.HandleBef(context.Background()))
+	require.NoError(t, g.HandleBef(context.Background()))
+	require.NoError(t, g.HandleSet(context.Background(), "locale", "TH"))
+	require.NoError(t, g.HandleBef(context.Background()))
+	require.NoError(t, g.HandleSet(context.Background(), "locale", "fr"))
+
+	assert.Equal(t, []string{
+		"🐀 no frens",
+		"🐀 no frens in #pigeons",
+		"⚙️ ตั้ง locale เป็น th แล้ว",
+		"🐀 no frens in #pigeons",
+		"⚙️ locale ต้องเป็นหนึ่งใน en, th",
+	}, client.messages)
+}
+
+func TestHandleShoot_Thai(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+
+	// boss, landed on the balcony, then a hit
+	g := NewGame(config.GameConfig{Interval: 30}, client, newMockPlayerRepoForTest(), "testnet", "#pigeons",
+		WithClock(clock), WithRand(&scriptedRand{t: t, rolls: []int{1, 2, 0, 0}}))
+	g.settings = &Settings{Locale: "th", MaxAttemptsPerSpawn: 5}
+	g.ActOnPlayer(context.Background())
+	require.NoError(t, g.HandleShoot(context_manager.WithNick(context.Background(), "somchai")))
+
+	require.Len(t, client.messages, 2)
+	assert.Equal(t, "❗⚠️ นกพิราบ boss มาเกาะบนระเบียง 🏠🌿ของคุณ - - 🐦", client.messages[0])
+	assert.Contains(t, client.messages[1], "somchai ยิงนกพิราบโดนแล้ว!")
+	assert.Contains(t, client.messages[1], "อยู่ในระดับ: มือใหม่ 🐣")
+}
+
+// outputCalls take text that reaches IRC users, directly or as part of a line
+var outputCalls = map[string]bool{"Privmsg": true, "Notice": true, "say": true, "c": true, "Sprintf": true}
+
+// catalogueCalls render text from the message catalogue
+var catalogueCalls = map[string]bool{"msg": true, "Msg": true, "translate": true, "announce": true}
+
+// formatVerb matches printf verbs, which are not words to translate
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*[\d.*]*[a-zA-Z%]`)
+
+func TestUserFacingTextIsInCatalogue(t *testing.T) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	for _, dir := range []string{"../commands", "../../bot"} {
+		more, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		require.NoError(t, err)
+		files = append(files, more...)
+	}
+
+	// words reports whether the literal lit holds text for people to read
+	words := func(lit *ast.BasicLit) bool {
+		if lit.Kind != token.STRING {
+			return false
+		}
+		text, err := strconv.Unquote(lit.Value)
+		if err != nil || strings.HasPrefix(text, "\x01") { // CTCP is protocol, not text
+			return false
+		}
+		return strings.ContainsFunc(formatVerb.ReplaceAllString(text, ""), unicode.IsLetter)
+	}
+	calledName := func(call *ast.CallExpr) string {
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			return fn.Name
+		case *ast.SelectorExpr:
+			return fn.Sel.Name
+		}
+		return ""
+	}
+	// literals reports the wordy literals in n outside catalogue lookups
+	literals := func(n ast.Node) {
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				return !catalogueCalls[calledName(n)]
+			case *ast.BasicLit:
+				if words(n) {
+					t.Errorf("%s: %s is not in the message catalogue", fset.Position(n.Pos()), n.Value)
+				}
+			}
+			return true
+		})
+	}
+
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == "messages.go" {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if catalogueCalls[calledName(n)] {
+					return false
+				}
+				if outputCalls[calledName(n)] {
+					for _, arg := range n.Args {
+						literals(arg)
+					}
+					return false
+				}
+			case *ast.BinaryExpr:
+				if n.Op == token.ADD {
+					literals(n)
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestBuiltinMessages_ThaiIsComplete(t *testing.T) {
+	for id := range builtinMessages[defaultLocale] {
+		assert.Contains(t, builtinMessages["th"], id, "message %q has no Thai template", id)
+	}
+}
@@ -1,7 +1,68 @@
 package game
 
-import "strings"
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+)
 
 func canonicalPlayerName(name string) string {
 	return strings.ToLower(strings.TrimSpace(name))
 }
+
+// playerName returns who nick plays as. The sender plays as the identity the
+// bot tracked for them, which follows nick changes and services logins;
+// anyone else as the account their nick was last seen with, or the nick.
+func (g *Game) playerName(ctx context.Context, nick string) string {
+	if strings.EqualFold(nick, context_manager.GetNickContext(ctx)) {
+		if id := context_manager.GetIdentityContext(ctx); id.Name != "" {
+			return id.Name
+		}
+		return canonicalPlayerName(nick)
+	}
+	if account := g.accountOf(ctx, nick); account != "" {
+		return account
+	}
+	return canonicalPlayerName(nick)
+}
+
+// accountOf returns the services account nick belongs to, or "" when it is
+// nobody's or aliases are not kept
+func (g *Game) accountOf(ctx context.Context, nick string) string {
+	if g.aliasRepository == nil {
+		return ""
+	}
+	account, err := g.aliasRepository.GetAccount(ctx, g.network, nick)
+	if err != nil {
+		fmt.Printf("Error looking up the account of %s: %v\n", nick, err)
+		return ""
+	}
+	return account
+}
+
+// isAccount reports whether name is a services account's name
+func (g *Game) isAccount(ctx context.Context, name string) bool {
+	if g.aliasRepository == nil {
+		return false
+	}
+	known, err := g.aliasRepository.IsAccount(ctx, g.network, name)
+	if err != nil {
+		fmt.Printf("Error looking up account %s: %v\n", name, err)
+		return false
+	}
+	return known
+}
+
+// impostor reports whether the sender is not logged in but plays as a name
+// that belongs to a services account, or is one. Accounts and nicks share
+// the player names, so a logged-out nick named like an account would score
+// as that account.
+func (g *Game) impostor(ctx context.Context) bool {
+	id := context_manager.GetIdentityContext(ctx)
+	if id.Name == "" || id.Account {
+		return false
+	}
+	return g.accountOf(ctx, id.Name) != "" || g.isAccount(ctx, id.Name)
+}
@@ -1,9 +1,15 @@
 package game
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	aliasMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestCanonicalPlayerName(t *testing.T) {
@@ -76,3 +82,97 @@ func TestCanonicalPlayerName(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleShoot_PlaysAsAccount(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock, 0, 0, 0, 0)
+	g.ActOnPlayer(context.Background())
+
+	ctx := context_manager.WithNick(context.Background(), "bob_away")
+	ctx = context_manager.WithIdentity(ctx, context_manager.Identity{Name: "Robert", Account: true})
+	require.NoError(t, g.HandleShoot(ctx))
+
+	assert.Contains(t, client.messages[1], "bob_away has shot a pigeon!")
+	require.Len(t, g.players.players, 1)
+	assert.Equal(t, "robert", g.players.players[0].Name)
+	assert.Equal(t, 1, g.players.players[0].Count)
+}
+
+func TestHandleShoot_AttemptsFollowTheAccount(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock, 0, 0, 0, 0)
+	g.settings = &Settings{MaxAttemptsPerSpawn: 1, ShootCooldown: time.Minute}
+	g.ActOnPlayer(context.Background())
+
+	for _, nick := range []string{"bob_away", "bob"} {
+		ctx := context_manager.WithNick(context.Background(), nick)
+		ctx = context_manager.WithIdentity(ctx, context_manager.Identity{Name: "robert", Account: true})
+		require.NoError(t, g.HandleShoot(ctx))
+	}
+
+	require.Len(t, client.messages, 3)
+	assert.Equal(t, "...bob slow down... you can shoot again in 60.0 seconds ⏳🕊️", client.messages[2], "a new nick does not get fresh attempts")
+	assert.Contains(t, g.lastShot, "robert")
+	assert.NotContains(t, g.lastShot, "bob")
+}
+
+func TestHandleShoot_RefusesLoggedOutAccountNick(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aliases := aliasMocks.NewMockAliasRepository(ctrl)
+	aliases.EXPECT().GetAccount(gomock.Any(), "testnet", "bob").Return("robert", nil)
+
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock, 0, 0, 0)
+	g.aliasRepository = aliases
+	g.ActOnPlayer(context.Background())
+
+	ctx := context_manager.WithNick(context.Background(), "bob")
+	ctx = context_manager.WithIdentity(ctx, context_manager.Identity{Name: "bob"})
+	require.NoError(t, g.HandleShoot(ctx))
+
+	assert.Equal(t, "🔒 bob belongs to a registered player, log in to services to shoot with it", client.messages[1])
+	assert.Empty(t, g.players.players)
+	assert.NotNil(t, g.activePigeon.activePigeon)
+}
+
+func TestHandleShoot_RefusesLoggedOutNickNamedLikeAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aliases := aliasMocks.NewMockAliasRepository(ctrl)
+	// alice only ever logged in under other nicks, so "alice" is no alias
+	aliases.EXPECT().GetAccount(gomock.Any(), "testnet", "alice").Return("", nil)
+	aliases.EXPECT().IsAccount(gomock.Any(), "testnet", "alice").Return(true, nil)
+
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock, 0, 0, 0)
+	g.aliasRepository = aliases
+	g.ActOnPlayer(context.Background())
+
+	ctx := context_manager.WithNick(context.Background(), "alice")
+	ctx = context_manager.WithIdentity(ctx, context_manager.Identity{Name: "alice"})
+	require.NoError(t, g.HandleShoot(ctx))
+
+	assert.Equal(t, "🔒 alice belongs to a registered player, log in to services to shoot with it", client.messages[1])
+	assert.Empty(t, g.players.players)
+	assert.NotNil(t, g.activePigeon.activePigeon)
+}
+
+func TestPlayerName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	aliases := aliasMocks.NewMockAliasRepository(ctrl)
+	aliases.EXPECT().GetAccount(gomock.Any(), "testnet", "Bob_Away").Return("robert", nil)
+	aliases.EXPECT().GetAccount(gomock.Any(), "testnet", "carol").Return("", nil)
+	aliases.EXPECT().GetAccount(gomock.Any(), "testnet", "Dave").Return("", nil)
+
+	g := &Game{network: "testnet", aliasRepository: aliases}
+	ctx := context_manager.WithNick(context.Background(), "alice")
+	ctx = context_manager.WithIdentity(ctx, context_manager.Identity{Name: "alicia", Account: true})
+
+	assert.Equal(t, "alicia", g.playerName(ctx, "Alice"))
+	assert.Equal(t, "robert", g.playerName(ctx, "Bob_Away"))
+	assert.Equal(t, "carol", g.playerName(ctx, "carol"))
+	assert.Equal(t, "dave", g.playerName(context.Background(), "Dave"))
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	rand "math/rand/v2"
+	"time"
+
+	alias2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/alias"
+	season2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	settings2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings"
+	shot2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+)
+
+// Option customises a Game built by NewGame
+type Option func(*Game)
+
+// WithClock replaces the wall clock, e.g. with a fake one in tests
+func WithClock(clock Clock) Option {
+	return func(g *Game) {
+		g.clock = clock
+	}
+}
+
+// WithRand replaces the random source
+func WithRand(r Rand) Option {
+	return func(g *Game) {
+		g.rand = &lockedRand{r: r}
+	}
+}
+
+// WithSeed makes every roll replayable from seed
+func WithSeed(seed uint64) Option {
+	return WithRand(rand.New(rand.NewPCG(seed, seed)))
+}
+
+// WithSettings applies stored channel settings on top of the defaults
+func WithSettings(values map[string]string) Option {
+	return func(g *Game) {
+		s := applySettings(DefaultSettings(), values)
+		g.settings = &s
+	}
+}
+
+// WithSettingsRepository persists changes made with !set
+func WithSettingsRepository(repo settings2.SettingsRepository) Option {
+	return func(g *Game) {
+		g.settingsRepository = repo
+	}
+}
+
+// WithShotRecorder records every !shoot for history and stats
+func WithShotRecorder(r ShotRecorder) Option {
+	return func(g *Game) {
+		g.shots = r
+	}
+}
+
+// WithShotRepository enables leaderboards over a time window, read from the shot history
+func WithShotRepository(repo shot2.ShotEventRepository) Option {
+	return func(g *Game) {
+		g.shotRepository = repo
+	}
+}
+
+// WithSeasonRepository runs seasons when GameConfig.SeasonDays is set
+func WithSeasonRepository(repo season2.SeasonRepository) Option {
+	return func(g *Game) {
+		g.seasonRepository = repo
+	}
+}
+
+// WithScorePool shares players with the other games in the same score pool
+// (see config.GameConfig.ScorePools)
+func WithScorePool(players *Players) Option {
+	return func(g *Game) {
+		g.pool = players
+	}
+}
+
+// WithListingClient sends long listings such as !score and !top through
+// client, e.g. a lower priority lane of an outbound queue
+func WithListingClient(client IRCClient) Option {
+	return func(g *Game) {
+		g.listings = client
+	}
+}
+
+// listingClient returns the client for listings, defaulting to the game's
+// own, rendering messages in the channel's style
+func (g *Game) listingClient() IRCClient {
+	if g.listings == nil {
+		return g.irc()
+	}
+	return styledClient{client: g.listings, style: g.style}
+}
+
+// roster returns the players this game scores against
+func (g *Game) roster() *Players {
+	if g.pool != nil {
+		return g.pool
+	}
+	return &g.players
+}
+
+// scope is the channel name players are stored under, the score pool if the channel is in one
+func (g *Game) scope() string {
+	return g.config.ScorePoolFor(g.channel)
+}
+
+// now returns the game clock's time; games built without NewGame use the wall clock
+func (g *Game) now() time.Time {
+	if g.clock == nil {
+		return time.Now()
+	}
+	return g.clock.Now()
+}
+
+// rng returns the game's random source, defaulting to math/rand/v2
+func (g *Game) rng() Rand {
+	if g.rand == nil {
+		return globalRand{}
+	}
+	return g.rand
+}
+
+// WithPersistence sets when score changes are written to the database; a
+// delay of 0 uses DefaultSaveDelay. Games write immediately without it.
+func WithPersistence(p Persistence, delay time.Duration) Option {
+	return func(g *Game) {
+		g.persistence = p
+		g.saveDelay = delay
+	}
+}
+
+// WithNetworkGames lists every game on the network, so queries over a score
+// pool or the whole network also see the others' scores still waiting to be
+// written
+func WithNetworkGames(games func() []*Game) Option {
+	return func(g *Game) {
+		g.networkGames = games
+	}
+}
+
+// WithAliasRepository plays nicks that belong to a services account as that account
+func WithAliasRepository(repo alias2.AliasRepository) Option {
+	return func(g *Game) {
+		g.aliasRepository = repo
+	}
+}
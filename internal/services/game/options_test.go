@@ -0,0 +1,147 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/actions"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedRand returns pre-set rolls in order so scenarios replay exactly
+type scriptedRand struct {
+	t     *testing.T
+	rolls []int
+}
+
+func (r *scriptedRand) IntN(n int) int {
+	r.t.Helper()
+	require.NotEmpty(r.t, r.rolls, "ran out of scripted rolls")
+	v := r.rolls[0]
+	r.rolls = r.rolls[1:]
+	require.Less(r.t, v, n, "scripted roll out of range")
+	return v
+}
+
+func (r *scriptedRand) Int64N(n int64) int64 {
+	return int64(r.IntN(int(n)))
+}
+
+func newScriptedGame(t *testing.T, client *mockIRCClientForTest, clock *fakeClock, rolls ...int) *Game {
+	g := NewGame(
+		config.GameConfig{Interval: 30},
+		client,
+		newMockPlayerRepoForTest(),
+		"testnet",
+		"test",
+		WithClock(clock),
+		WithRand(&scriptedRand{t: t, rolls: rolls}),
+	)
+	g.actions = []actions.Action{{Action: "landed", Items: []string{"car", "bed"}, Format: "A %s pigeon has %s on your %s"}}
+	return g
+}
+
+func TestNewGame_Options(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+
+	// pigeon roll 1 of 3 -> boss, action 0, item 1 -> bed
+	g := newScriptedGame(t, client, clock, 1, 0, 1)
+	g.ActOnPlayer(context.Background())
+
+	assert.Equal(t, []string{"A boss pigeon has landed on your bed"}, client.messages)
+	assert.Equal(t, clock.Now(), g.activePigeon.SpawnedAt)
+}
+
+func TestWithSeed_Replayable(t *testing.T) {
+	spawns := func() []string {
+		client := &mockIRCClientForTest{}
+		clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		g := NewGame(config.GameConfig{Interval: 30}, client, newMockPlayerRepoForTest(), "testnet", "test",
+			WithClock(clock), WithSeed(42))
+
+		for i := 0; i < 5; i++ {
+			g.ActOnPlayer(context.Background())
+			clock.Advance(61 * time.Second)
+			g.ActOnPlayer(context.Background()) // escape
+		}
+		return client.messages
+	}
+
+	first := spawns()
+	assert.Len(t, first, 10)
+	assert.Equal(t, first, spawns())
+}
+
+func TestHandleShoot_ScriptedRolls(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+
+	// spawn a cartel member (85% hit), then miss with 85 and hit with 84
+	g := newScriptedGame(t, client, clock, 0, 0, 0, 85, 84)
+	g.ActOnPlayer(context.Background())
+
+	ctx := context_manager.WithNick(context.Background(), "shooter")
+	require.NoError(t, g.HandleShoot(ctx))
+	assert.Contains(t, client.messages[1], "shooter has shot a pigeon, but it got away!")
+
+	require.NoError(t, g.HandleShoot(ctx))
+	assert.Contains(t, client.messages[2], "You have shot a total of 1 pigeon(s)!")
+	assert.Contains(t, client.messages[2], "a total of 10 points")
+	assert.Nil(t, g.activePigeon.activePigeon)
+}
+
+func TestCanShoot_CooldownFollowsClock(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := &Game{clock: clock, lastShot: make(map[string]*shotState)}
+
+	for i := 0; i < maxAttemptsPerSpawn; i++ {
+		ok, _ := g.canShoot("shooter", 1)
+		require.True(t, ok)
+	}
+
+	ok, wait := g.canShoot("shooter", 1)
+	assert.False(t, ok)
+	assert.Equal(t, shootCooldown, wait)
+
+	clock.Advance(2 * time.Second)
+	ok, wait = g.canShoot("shooter", 1)
+	assert.False(t, ok)
+	assert.Equal(t, shootCooldown-2*time.Second, wait)
+
+	clock.Advance(shootCooldown)
+	ok, _ = g.canShoot("shooter", 1)
+	assert.True(t, ok)
+}
+
+func TestTryRareEgg_ScriptedRolls(t *testing.T) {
+	tests := []struct {
+		name     string
+		rolls    []int
+		contains string
+	}{
+		{name: "does not appear", rolls: []int{rareEggAppearPercent}},
+		{name: "appears and cracks", rolls: []int{0, rareEggSuccessPercent}, contains: "cracked and vanished"},
+		{name: "appears and is collected", rolls: []int{rareEggAppearPercent - 1, rareEggSuccessPercent - 1}, contains: "LEGENDARY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newScriptedGame(t, &mockIRCClientForTest{}, newFakeClock(time.Now()), tt.rolls...)
+			g.activePigeon.activePigeon = g.findPigeon("boss")
+			g.activePigeon.IsMating = true
+
+			msg, err := g.TryRareEgg(context.Background(), "testuser")
+			require.NoError(t, err)
+			if tt.contains == "" {
+				assert.Empty(t, msg)
+				return
+			}
+			assert.Contains(t, msg, tt.contains)
+		})
+	}
+}
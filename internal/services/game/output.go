@@ -0,0 +1,204 @@
+package game
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+)
+
+// maxLineBytes is the most text sent in one PRIVMSG or NOTICE. The server's
+// 512-byte limit also covers our prefix, the command, the target and CRLF.
+const maxLineBytes = 400
+
+// listingPageSize is how many players one page of !score, !pigeons or !level shows
+const listingPageSize = 30
+
+const (
+	ircItalic    = "\x1D"
+	ircUnderline = "\x1F"
+	ircReverse   = "\x16"
+)
+
+// ircFormat is the colour and styles in effect at some point of a message
+type ircFormat struct {
+	color                            string
+	bold, italic, underline, reverse bool
+}
+
+// codes returns the control codes that turn f back on at the start of a line
+func (f ircFormat) codes() string {
+	var b strings.Builder
+	if f.bold {
+		b.WriteString(ircBold)
+	}
+	if f.italic {
+		b.WriteString(ircItalic)
+	}
+	if f.underline {
+		b.WriteString(ircUnderline)
+	}
+	if f.reverse {
+		b.WriteString(ircReverse)
+	}
+	b.WriteString(f.color)
+	return b.String()
+}
+
+// nextAtom returns the length of the unit at the start of s that must not be
+// split: a colour code with its digits, another control code or one rune
+func nextAtom(s string) int {
+	if s[0] != ircColor[0] {
+		_, size := utf8.DecodeRuneInString(s)
+		return size
+	}
+
+	n := 1 + digits(s[1:], 2)
+	if n < len(s) && s[n] == ',' && digits(s[n+1:], 2) > 0 {
+		n += 1 + digits(s[n+1:], 2)
+	}
+	return n
+}
+
+// digits counts the ASCII digits at the start of s, up to max
+func digits(s string, max int) int {
+	n := 0
+	for n < len(s) && n < max && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// apply updates f with the control code atom, if it is one
+func (f *ircFormat) apply(atom string) {
+	switch atom[:1] {
+	case ircColor:
+		if atom == ircColor {
+			f.color = ""
+		} else {
+			f.color = atom
+		}
+	case ircBold:
+		f.bold = !f.bold
+	case ircItalic:
+		f.italic = !f.italic
+	case ircUnderline:
+		f.underline = !f.underline
+	case ircReverse:
+		f.reverse = !f.reverse
+	case ircReset:
+		*f = ircFormat{}
+	}
+}
+
+// splitLine breaks text into lines of at most limit bytes, at the last space
+// where there is one. It never cuts through a UTF-8 character or a colour
+// code, and each new line starts with the colours and styles still in effect.
+func splitLine(text string, limit int) []string {
+	var (
+		lines  []string
+		format ircFormat
+		line   strings.Builder
+		prefix int // bytes of restored formatting at the start of line
+
+		// the last space in line and the formatting after it
+		space       = -1
+		spaceFormat ircFormat
+	)
+
+	for len(text) > 0 {
+		n := nextAtom(text)
+		atom := text[:n]
+		text = text[n:]
+
+		if line.Len()+n > limit && line.Len() > prefix {
+			current := line.String()
+			rest, restFormat := "", format
+			if space > prefix {
+				current, rest, restFormat = current[:space], current[space+1:], spaceFormat
+			}
+			lines = append(lines, current)
+
+			line.Reset()
+			line.WriteString(restFormat.codes())
+			prefix = line.Len()
+			line.WriteString(rest)
+			space = -1
+		}
+
+		format.apply(atom)
+		if atom == " " {
+			space, spaceFormat = line.Len(), format
+		}
+		line.WriteString(atom)
+	}
+
+	if line.Len() > prefix {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// say sends text to the channel, split into lines that fit
+func (g *Game) say(text string) {
+	for _, line := range splitLine(text, maxLineBytes) {
+		g.irc().Privmsg(g.channel, line)
+	}
+}
+
+// parsePage reads the page asked for as "page N" or "N"; no args is page 1
+func parsePage(args []string) (int, bool) {
+	if len(args) == 2 && strings.EqualFold(args[0], "page") {
+		args = args[1:]
+	}
+	switch len(args) {
+	case 0:
+		return 1, true
+	case 1:
+		page, err := strconv.Atoi(args[0])
+		return page, err == nil && page > 0
+	}
+	return 0, false
+}
+
+// sendListing shows one page of a player listing such as !score. A page that
+// does not fit on one line is sent by NOTICE to whoever asked for it, so the
+// channel is not flooded.
+func (g *Game) sendListing(ctx context.Context, command string, entries []string, args []string) {
+	page, ok := parsePage(args)
+	if !ok {
+		g.irc().Privmsg(g.channel, g.msg(msgListingUsage, command))
+		return
+	}
+	if len(entries) == 0 {
+		g.irc().Privmsg(g.channel, g.msg(msgListingEmpty))
+		return
+	}
+
+	pages := (len(entries) + listingPageSize - 1) / listingPageSize
+	if page > pages {
+		g.irc().Privmsg(g.channel, g.msg(msgListingNoPage, command, pages))
+		return
+	}
+
+	start := (page - 1) * listingPageSize
+	end := min(start+listingPageSize, len(entries))
+	text := strings.Join(entries[start:end], "")
+	if page < pages {
+		text += g.msg(msgListingMore, page, pages, command, page+1)
+	} else if pages > 1 {
+		text += g.msg(msgListingPage, page, pages)
+	}
+
+	lines := splitLine(text, maxLineBytes)
+	nick := context_manager.GetNickContext(ctx)
+	for _, line := range lines {
+		if len(lines) > 1 && nick != "" {
+			g.listingClient().Notice(nick, line)
+		} else {
+			g.listingClient().Privmsg(g.channel, line)
+		}
+	}
+}
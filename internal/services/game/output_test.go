@@ -0,0 +1,139 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/game/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSplitLine(t *testing.T) {
+	t.Run("short text is left alone", func(t *testing.T) {
+		assert.Equal(t, []string{"alice: 10, bob: 5, "}, splitLine("alice: 10, bob: 5, ", 400))
+	})
+
+	t.Run("breaks at the last space", func(t *testing.T) {
+		assert.Equal(t, []string{"one two", "three four"}, splitLine("one two three four", 10))
+	})
+
+	t.Run("long words are cut without breaking characters", func(t *testing.T) {
+		lines := splitLine(strings.Repeat("🕊", 10), 9)
+		require.Len(t, lines, 5)
+		for _, line := range lines {
+			assert.True(t, utf8.ValidString(line), "%q", line)
+			assert.LessOrEqual(t, len(line), 9)
+		}
+		assert.Equal(t, strings.Repeat("🕊", 10), strings.Join(lines, ""))
+	})
+
+	t.Run("colour codes are kept whole and carried over", func(t *testing.T) {
+		text := ircBold + c("aaaa bbbb", 4) + " cc"
+		lines := splitLine(text, 10)
+		assert.Equal(t, []string{
+			ircBold + ircColor + "04aaaa",
+			ircBold + ircColor + "04bbbb" + ircReset,
+			"cc",
+		}, lines)
+	})
+
+	t.Run("foreground and background digits stay together", func(t *testing.T) {
+		lines := splitLine("ab"+ircColor+"04,12cd", 8)
+		assert.Equal(t, []string{"ab" + ircColor + "04,12", ircColor + "04,12cd"}, lines)
+	})
+}
+
+func TestParsePage(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		page int
+		ok   bool
+	}{
+		{nil, 1, true},
+		{[]string{"3"}, 3, true},
+		{[]string{"Page", "2"}, 2, true},
+		{[]string{"0"}, 0, false},
+		{[]string{"page"}, 0, false},
+		{[]string{"page", "x"}, 0, false},
+	} {
+		page, ok := parsePage(tt.args)
+		assert.Equal(t, tt.ok, ok, "%v", tt.args)
+		if tt.ok {
+			assert.Equal(t, tt.page, page, "%v", tt.args)
+		}
+	}
+}
+
+// gameWithPlayers returns a game whose roster has n players, best first
+func gameWithPlayers(client IRCClient, n int) *Game {
+	g := &Game{ircClient: client, channel: "#pigeons"}
+	for i := 0; i < n; i++ {
+		g.players.players = append(g.players.players, player.NewPlayer(fmt.Sprintf("hunter%02d", i), 1000-i, 0))
+	}
+	return g
+}
+
+func TestHandlePoints_Pages(t *testing.T) {
+	t.Run("a short listing goes to the channel", func(t *testing.T) {
+		client := &mockIRCClientForTest{}
+		g := gameWithPlayers(client, 2)
+
+		require.NoError(t, g.HandlePoints(context.Background()))
+		assert.Equal(t, []string{"hunter00: 1000, hunter01: 999, "}, client.messages)
+	})
+
+	t.Run("long pages are noticed to the caller in lines that fit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		client := mocks.NewMockIRCClient(ctrl)
+		var lines []string
+		client.EXPECT().Notice("alice", gomock.Any()).Do(func(_, line string) {
+			lines = append(lines, line)
+		}).MinTimes(2)
+
+		g := gameWithPlayers(client, listingPageSize+5)
+		ctx := context_manager.WithNick(context.Background(), "alice")
+		require.NoError(t, g.HandlePoints(ctx))
+
+		for _, line := range lines {
+			assert.LessOrEqual(t, len(line), maxLineBytes)
+		}
+		all := strings.Join(lines, " ")
+		assert.Contains(t, all, "hunter00: 1000,")
+		assert.NotContains(t, all, fmt.Sprintf("hunter%02d", listingPageSize))
+		assert.True(t, strings.HasSuffix(all, "(page 1/2, !score page 2 for more)"), all)
+	})
+
+	t.Run("later pages", func(t *testing.T) {
+		client := &mockIRCClientForTest{}
+		g := gameWithPlayers(client, listingPageSize+2)
+
+		require.NoError(t, g.HandlePoints(context.Background(), "page", "2"))
+		assert.Equal(t, []string{"hunter30: 970, hunter31: 969, (page 2/2)"}, client.messages)
+
+		client.messages = nil
+		require.NoError(t, g.HandlePoints(context.Background(), "3"))
+		assert.Equal(t, []string{"📄 !score only has 2 page(s)"}, client.messages)
+
+		client.messages = nil
+		require.NoError(t, g.HandleLevel(context.Background(), "last"))
+		assert.Equal(t, []string{"📄 usage: !level [page N]"}, client.messages)
+	})
+}
+
+func TestListingClient(t *testing.T) {
+	client := &mockIRCClientForTest{}
+	listings := &mockIRCClientForTest{}
+	g := gameWithPlayers(client, 1)
+	WithListingClient(listings)(g)
+
+	require.NoError(t, g.HandleCount(context.Background()))
+	assert.Empty(t, client.messages)
+	assert.Equal(t, []string{"hunter00: 0, "}, listings.messages)
+}
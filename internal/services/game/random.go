@@ -0,0 +1,36 @@
+package game
+
+import (
+	rand "math/rand/v2"
+	"sync"
+)
+
+// Rand is the random source behind spawns, hits and egg rolls
+type Rand interface {
+	IntN(n int) int
+	Int64N(n int64) int64
+}
+
+// globalRand uses the math/rand/v2 top-level functions
+type globalRand struct{}
+
+func (globalRand) IntN(n int) int       { return rand.IntN(n) }
+func (globalRand) Int64N(n int64) int64 { return rand.Int64N(n) }
+
+// lockedRand makes a Rand safe for the game loop and command handlers to share
+type lockedRand struct {
+	mu sync.Mutex
+	r  Rand
+}
+
+func (l *lockedRand) IntN(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.IntN(n)
+}
+
+func (l *lockedRand) Int64N(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int64N(n)
+}
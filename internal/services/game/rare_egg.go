@@ -2,10 +2,11 @@ package game
 
 import (
 	"context"
-	"fmt"
-	rand "math/rand/v2"
+
+	shot2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
 )
 
+// Defaults; channels can override them with !set
 const (
 	rareEggAppearPercent  = 10 // 10% chance to appear
 	rareEggSuccessPercent = 50 // 50% chance to successfully collect if it appears
@@ -14,50 +15,52 @@ const (
 
 // Called after successful shot (and while activePigeon is locked by caller)
 func (g *Game) TryRareEgg(ctx context.Context, shooterName string) (string, error) {
+	msg, _, err := g.tryRareEgg(ctx, shooterName)
+	return msg, err
+}
+
+// tryRareEgg is TryRareEgg that also returns the outcome (shot2.RareEgg*)
+func (g *Game) tryRareEgg(ctx context.Context, shooterName string) (string, string, error) {
 	// Must be mating and must have an active pigeon object
 	if g.activePigeon == nil || g.activePigeon.activePigeon == nil || !g.activePigeon.IsMating {
-		return "", nil
+		return "", shot2.RareEggNone, nil
 	}
 
+	settings := g.Settings()
+
 	// Step 1: does it appear?
-	if rand.IntN(100) >= rareEggAppearPercent {
-		return "", nil
+	if g.rng().IntN(100) >= settings.RareEggAppearPercent {
+		return "", shot2.RareEggNone, nil
 	}
 
 	// Step 2: fail (no odds mentioned)
-	if rand.IntN(100) >= rareEggSuccessPercent {
-		return fmt.Sprintf(
-			"✨ A mysterious rare egg appeared for %s ... but it cracked and vanished! 💥",
+	if g.rng().IntN(100) >= settings.RareEggSuccessPercent {
+		return g.msg(
+			msgRareCracked,
 			shooterName,
-		), nil
+		), shot2.RareEggCracked, nil
 	}
 
 	// Step 3: success → DB updates (eggs includes rare eggs)
-	dbName := canonicalPlayerName(shooterName)
-
-	totalEggs, err := g.playerRepository.AddEggs(ctx, g.network, g.channel, dbName, 1)
-	if err != nil {
-		return "", err
-	}
+	dbName := g.playerName(ctx, shooterName)
 
-	totalRare, err := g.playerRepository.AddRareEggs(ctx, g.network, g.channel, dbName, 1)
+	eggs, err := g.store(ctx, scoreChange{kind: changeEggs, name: dbName, eggs: 1, rareEggs: 1})
 	if err != nil {
-		return "", err
+		return "", shot2.RareEggNone, err
 	}
 
-	// Step 4: points boost (in-memory player)
-	foundPlayer, err := g.FindPlayer(ctx, shooterName)
+	// Step 4: points boost
+	stored, err := g.store(ctx, scoreChange{kind: changeBonus, name: dbName, points: settings.RareEggPointBoost})
 	if err != nil {
-		return "", err
+		return "", shot2.RareEggCollected, err
 	}
-	foundPlayer.Points += rareEggPointBoost
 
-	return fmt.Sprintf(
-		"🌟 WOW! %s collected a LEGENDARY rare egg! 🚀🚀🚀🚀🚀 +%s points with +1 egg 🥚 | Eggs: %s (Rare: %s) | Points: %s",
+	return g.msg(
+		msgRareCollected,
 		shooterName,
-		fmtNum(rareEggPointBoost),
-		fmtNum(totalEggs),
-		fmtNum(totalRare),
-		fmtNum(foundPlayer.Points),
-	), nil
+		fmtNum(settings.RareEggPointBoost),
+		fmtNum(eggs.Eggs),
+		fmtNum(eggs.RareEggs),
+		fmtNum(stored.Points),
+	), shot2.RareEggCollected, nil
 }
@@ -2,105 +2,293 @@ package game
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
 )
 
+// Persistence decides when score changes are written to the database
+type Persistence string
+
 const (
-	debounceDelay = 2 * time.Second // wait 2s after last change before saving
+	// PersistImmediate writes each shot before it is announced
+	PersistImmediate Persistence = "immediate"
+	// PersistDebounced writes once no score has changed for the save delay
+	PersistDebounced Persistence = "debounced"
+	// PersistBatch writes whatever changed every save delay
+	PersistBatch Persistence = "batch"
 )
 
-type saveDebouncer struct {
-	mu           sync.Mutex
-	dirtyPlayers map[string]bool
-	timer        *time.Timer
-	game         *Game
-	ctx          context.Context
+const (
+	DefaultSaveDelay  = 2 * time.Second
+	minRetryDelay     = time.Second
+	maxRetryDelay     = time.Minute
+	maxPendingChanges = 1000 // score changes kept while the database is away before shots are refused
+)
+
+// errSavesBacklogged refuses a shot when too many scores are waiting for the database
+var errSavesBacklogged = errors.New("too many scores waiting to be saved")
+
+// ParsePersistence reads a persistence strategy from config; empty is PersistImmediate
+func ParsePersistence(s string) (Persistence, error) {
+	switch p := Persistence(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PersistImmediate, nil
+	case PersistImmediate, PersistDebounced, PersistBatch:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown persistence %q, want immediate, debounced or batch", s)
 }
 
-func newSaveDebouncer(g *Game, ctx context.Context) *saveDebouncer {
-	return &saveDebouncer{
-		dirtyPlayers: make(map[string]bool),
-		game:         g,
-		ctx:          ctx,
+type changeKind int
+
+const (
+	changeKill changeKind = iota
+	changeMiss
+	changeBonus
+	changeEggs
+)
+
+// scoreChange is a shot, bonus or eggs to add to a player's stored score
+type scoreChange struct {
+	kind     changeKind
+	name     string
+	points   int
+	eggs     int
+	rareEggs int
+}
+
+// apply makes the change to p that writing c makes to the stored score
+func (c scoreChange) apply(p *player.Player) {
+	switch c.kind {
+	case changeKill:
+		p.Points += c.points
+		p.Count++
+		p.RecordShot(true)
+	case changeMiss:
+		p.RecordShot(false)
+	case changeEggs:
+		p.Eggs += c.eggs
+		p.RareEggs += c.rareEggs
+	default:
+		p.Points += c.points
 	}
 }
 
-// MarkDirty marks a player as needing to be saved and starts/resets the debounce timer
-func (s *saveDebouncer) MarkDirty(playerName string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// saveDebouncer writes score changes as the game's persistence strategy
+// says. Changes that cannot be written yet are kept, in order, and retried
+// with backoff until the database is back.
+type saveDebouncer struct {
+	game  *Game
+	mode  Persistence
+	delay time.Duration
 
-	s.dirtyPlayers[playerName] = true
+	mu      sync.Mutex
+	pending []scoreChange
+	wake    chan struct{} // a change was queued
+
+	flushMu sync.Mutex // one flush at a time, so nothing is written twice
+}
 
-	// Reset timer on each mark
-	if s.timer != nil {
-		s.timer.Stop()
+func newSaveDebouncer(g *Game, mode Persistence, delay time.Duration) *saveDebouncer {
+	if mode == "" {
+		mode = PersistImmediate
+	}
+	if delay <= 0 {
+		delay = DefaultSaveDelay
+	}
+	return &saveDebouncer{
+		game:  g,
+		mode:  mode,
+		delay: delay,
+		wake:  make(chan struct{}, 1),
 	}
-	s.timer = time.AfterFunc(debounceDelay, s.flush)
 }
 
-// flush saves all dirty players to the database
-func (s *saveDebouncer) flush() {
-	s.mu.Lock()
-	if len(s.dirtyPlayers) == 0 {
-		s.mu.Unlock()
-		return
+// store adds c to the player's score and returns their new totals. In
+// immediate mode with nothing waiting the change is written now; otherwise,
+// or if that write fails, it is queued and the totals are the roster's.
+func (g *Game) store(ctx context.Context, c scoreChange) (*player2.Player, error) {
+	s := g.saves
+	if s == nil || (s.mode == PersistImmediate && s.Waiting() == 0) {
+		stored, err := g.writeChange(ctx, c)
+		if err == nil {
+			g.cache(stored)
+			return stored, nil
+		}
+		if s == nil {
+			return nil, err
+		}
+		fmt.Printf("Error saving score for %s, will retry: %v\n", c.name, err)
 	}
+	return s.queue(c)
+}
 
-	// Copy dirty set and clear
-	toSave := make([]string, 0, len(s.dirtyPlayers))
-	for name := range s.dirtyPlayers {
-		toSave = append(toSave, name)
+// writeChange adds c to the stored score and returns the new totals
+func (g *Game) writeChange(ctx context.Context, c scoreChange) (*player2.Player, error) {
+	switch c.kind {
+	case changeKill:
+		return g.playerRepository.AwardKill(ctx, g.network, g.scope(), c.name, c.points)
+	case changeMiss:
+		return g.playerRepository.RecordMiss(ctx, g.network, g.scope(), c.name)
+	case changeEggs:
+		return g.playerRepository.CollectEggs(ctx, g.network, g.scope(), c.name, c.eggs, c.rareEggs)
+	default:
+		return g.playerRepository.AddPoints(ctx, g.network, g.scope(), c.name, c.points)
 	}
-	s.dirtyPlayers = make(map[string]bool)
-	s.mu.Unlock()
+}
+
+// applyChange makes c's change to the cached player now and returns the
+// totals the database will have once it is written
+func (g *Game) applyChange(c scoreChange) *player2.Player {
+	g.roster().Lock()
+	defer g.roster().Unlock()
 
-	// Save only dirty players
-	s.game.savePlayers(s.ctx, toSave)
+	name := canonicalPlayerName(c.name)
+	p := findIn(g.roster().players, name)
+	if p == nil {
+		p = player.NewPlayer(name, 0, 0)
+		g.roster().players = append(g.roster().players, p)
+	}
+	c.apply(p)
+	return &player2.Player{
+		Network:    g.network,
+		Channel:    g.scope(),
+		Name:       p.Name,
+		Points:     p.Points,
+		Count:      p.Count,
+		Shots:      p.Shots,
+		Misses:     p.Misses,
+		Streak:     p.Streak,
+		BestStreak: p.BestStreak,
+		Eggs:       p.Eggs,
+		RareEggs:   p.RareEggs,
+	}
 }
 
-// FlushNow forces an immediate save (useful for shutdown)
-func (s *saveDebouncer) FlushNow() {
+// queue keeps c for the next flush and counts it in the roster straight away
+func (s *saveDebouncer) queue(c scoreChange) (*player2.Player, error) {
 	s.mu.Lock()
-	if s.timer != nil {
-		s.timer.Stop()
-		s.timer = nil
+	defer s.mu.Unlock()
+
+	if len(s.pending) >= maxPendingChanges {
+		return nil, errSavesBacklogged
+	}
+	s.pending = append(s.pending, c)
+	select {
+	case s.wake <- struct{}{}:
+	default:
 	}
-	s.mu.Unlock()
+	return s.game.applyChange(c), nil
+}
+
+// Waiting returns how many score changes are not written yet
+func (s *saveDebouncer) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	s.flush()
+	return len(s.pending)
 }
 
-// savePlayers saves only the specified players (called by debouncer)
-func (g *Game) savePlayers(ctx context.Context, playerNames []string) {
-	g.players.Lock()
-	defer g.players.Unlock()
+// flush writes the waiting changes in order. It stops at the first that
+// fails, keeping it and everything after it for the next flush.
+func (s *saveDebouncer) flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
 
-	nameSet := make(map[string]bool, len(playerNames))
-	for _, n := range playerNames {
-		nameSet[n] = true
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		c := s.pending[0]
+		s.mu.Unlock()
+
+		stored, err := s.game.writeChange(ctx, c)
+		if err != nil {
+			return fmt.Errorf("saving score for %s: %w", c.name, err)
+		}
+
+		s.mu.Lock()
+		s.pending = s.pending[1:]
+		if !s.waitingFor(c.name) {
+			// otherwise the roster already counts changes the database has not seen
+			s.game.cache(stored)
+		}
+		s.mu.Unlock()
 	}
+}
+
+// waitingFor reports whether a change for name is still queued; s.mu must be held
+func (s *saveDebouncer) waitingFor(name string) bool {
+	for _, c := range s.pending {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush writes every waiting change, retrying with backoff until it works
+// or ctx is done
+func (s *saveDebouncer) Flush(ctx context.Context) error {
+	delay := minRetryDelay
+	for {
+		err := s.flush(ctx)
+		if err == nil {
+			return nil
+		}
+		fmt.Printf("%v, retrying in %s\n", err, delay)
 
-	for _, p := range g.players.players {
-		if !nameSet[p.Name] {
-			continue
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d scores not saved: %w", s.Waiting(), err)
+		case <-s.game.clock.After(delay):
 		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
 
-		playerEntity := player2.Player{
-			Count:   p.Count,
-			Points:  p.Points,
-			Name:    p.Name,
-			Channel: g.channel,
-			Network: g.network,
+// run writes queued changes as the strategy says until ctx is done
+func (s *saveDebouncer) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
 		}
-		if err := g.playerRepository.UpsertPlayer(ctx, &playerEntity); err != nil {
-			fmt.Printf("Error saving player %s: %v\n", p.Name, err)
+		if !s.settle(ctx) || s.Flush(ctx) != nil {
+			return
 		}
 	}
+}
 
-	fmt.Printf("[debouncer] saved %d players\n", len(playerNames))
+// settle waits until the queue should be written: straight away in
+// immediate mode, where only failed writes are queued, once no change has
+// come for the delay when debounced, and the delay after the first change
+// when batched. It returns false if ctx is done first.
+func (s *saveDebouncer) settle(ctx context.Context) bool {
+	if s.mode == PersistImmediate {
+		return true
+	}
+
+	timer := s.game.clock.After(s.delay)
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-s.wake:
+			if s.mode == PersistDebounced {
+				timer = s.game.clock.After(s.delay)
+			}
+		case <-timer:
+			return true
+		}
+	}
 }
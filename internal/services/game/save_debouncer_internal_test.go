@@ -0,0 +1,237 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPersistenceGame returns a game that saves scores as p says, timed by clock
+func newPersistenceGame(p Persistence, clock *fakeClock) (*Game, *mockPlayerRepositoryForTest) {
+	repo := newMockPlayerRepoForTest()
+	g := NewGame(config.GameConfig{}, &mockIRCClientForTest{}, repo, "testnet", "#test",
+		WithClock(clock),
+		WithPersistence(p, 5*time.Second),
+	)
+	return g, repo
+}
+
+func TestParsePersistence(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Persistence
+		wantErr bool
+	}{
+		{"", PersistImmediate, false},
+		{"immediate", PersistImmediate, false},
+		{" Debounced ", PersistDebounced, false},
+		{"BATCH", PersistBatch, false},
+		{"sometimes", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePersistence(tt.in)
+		assert.Equal(t, tt.want, got, "input %q", tt.in)
+		assert.Equal(t, tt.wantErr, err != nil, "input %q", tt.in)
+	}
+}
+
+func TestStore_Immediate(t *testing.T) {
+	g, repo := newPersistenceGame(PersistImmediate, newFakeClock(time.Now()))
+
+	stored, err := g.store(context.Background(), scoreChange{kind: changeKill, name: "alice", points: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, stored.Points)
+	assert.Equal(t, 10, repo.players["testnet|#test|alice"].Points)
+	assert.Zero(t, g.saves.Waiting())
+}
+
+func TestStore_Deferred(t *testing.T) {
+	for _, mode := range []Persistence{PersistDebounced, PersistBatch} {
+		t.Run(string(mode), func(t *testing.T) {
+			g, repo := newPersistenceGame(mode, newFakeClock(time.Now()))
+			ctx := context.Background()
+
+			_, err := g.store(ctx, scoreChange{kind: changeKill, name: "alice", points: 10})
+			require.NoError(t, err)
+			stored, err := g.store(ctx, scoreChange{kind: changeMiss, name: "alice"})
+			require.NoError(t, err)
+
+			// announced and listed straight away, written later
+			assert.Equal(t, 10, stored.Points)
+			assert.Equal(t, 2, stored.Shots)
+			assert.Equal(t, 1, stored.Misses)
+			assert.Equal(t, 10, findIn(g.roster().players, "alice").Points)
+			assert.Empty(t, repo.players)
+			assert.Equal(t, 2, g.saves.Waiting())
+
+			require.NoError(t, g.saves.flush(ctx))
+			assert.Equal(t, 10, repo.players["testnet|#test|alice"].Points)
+			assert.Equal(t, 1, repo.players["testnet|#test|alice"].Misses)
+			assert.Zero(t, g.saves.Waiting())
+		})
+	}
+}
+
+func TestStore_EggsAreQueuedLikeKills(t *testing.T) {
+	g, repo := newPersistenceGame(PersistBatch, newFakeClock(time.Now()))
+	ctx := context.Background()
+
+	_, err := g.store(ctx, scoreChange{kind: changeEggs, name: "alice", eggs: 3})
+	require.NoError(t, err)
+	stored, err := g.store(ctx, scoreChange{kind: changeEggs, name: "alice", eggs: 1, rareEggs: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, stored.Eggs)
+	assert.Equal(t, 1, stored.RareEggs)
+	assert.Empty(t, repo.eggs)
+	assert.Equal(t, 2, g.saves.Waiting())
+
+	require.NoError(t, g.saves.flush(ctx))
+	assert.Equal(t, 4, repo.eggs["testnet|#test|alice"])
+	assert.Equal(t, 1, repo.rareEggs["testnet|#test|alice"])
+	assert.Equal(t, 4, findIn(g.roster().players, "alice").Eggs)
+}
+
+func TestStore_ReadsSeeWaitingChanges(t *testing.T) {
+	g, repo := newPersistenceGame(PersistBatch, newFakeClock(time.Now()))
+	client := g.ircClient.(*mockIRCClientForTest)
+	ctx := context.Background()
+
+	_, err := g.store(ctx, scoreChange{kind: changeEggs, name: "alice", eggs: 3, rareEggs: 1})
+	require.NoError(t, err)
+
+	// egg counts come from the roster
+	require.NoError(t, g.HandleEggs(ctx, "alice"))
+	assert.Equal(t, "🥚 alice has 3 egg(s) total — including 1 rare egg(s) 🌟🥚", client.messages[len(client.messages)-1])
+	assert.Equal(t, 3, g.channelStats("alice").Eggs)
+	assert.Equal(t, 1, g.saves.Waiting(), "nothing had to be written")
+
+	// listings from the database write what is waiting first
+	_, err = g.TopByPoints(ctx, 5)
+	require.NoError(t, err)
+	assert.Zero(t, g.saves.Waiting())
+	assert.Equal(t, 3, repo.eggs["testnet|#test|alice"])
+}
+
+func TestStore_NetworkReadsSeeOtherGames(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	here, _ := newPersistenceGame(PersistDebounced, clock)
+	there, repo := newPersistenceGame(PersistDebounced, clock)
+	games := func() []*Game { return []*Game{here, there} }
+	here.networkGames, there.networkGames = games, games
+	ctx := context.Background()
+
+	_, err := there.store(ctx, kill("bob"))
+	require.NoError(t, err)
+
+	_, err = here.TopByPointsNetwork(ctx, 5)
+	require.NoError(t, err)
+	assert.Zero(t, there.saves.Waiting())
+	assert.Equal(t, 10, repo.players["testnet|#test|bob"].Points)
+}
+
+func TestStore_ImmediateKeepsFailedWrites(t *testing.T) {
+	g, repo := newPersistenceGame(PersistImmediate, newFakeClock(time.Now()))
+	ctx := context.Background()
+	repo.scoreErr = errors.New("database is down")
+
+	stored, err := g.store(ctx, scoreChange{kind: changeKill, name: "alice", points: 10})
+	require.NoError(t, err, "the kill is kept for later, not lost")
+	assert.Equal(t, 1, stored.Count)
+	assert.Equal(t, 1, g.saves.Waiting())
+
+	// later shots queue behind it, so they are written in order
+	repo.scoreErr = nil
+	_, err = g.store(ctx, scoreChange{kind: changeBonus, name: "alice", points: 5})
+	require.NoError(t, err)
+	assert.Empty(t, repo.players)
+
+	require.NoError(t, g.saves.flush(ctx))
+	assert.Equal(t, 15, repo.players["testnet|#test|alice"].Points)
+}
+
+func TestStore_Backlogged(t *testing.T) {
+	g, _ := newPersistenceGame(PersistBatch, newFakeClock(time.Now()))
+	g.saves.pending = make([]scoreChange, maxPendingChanges)
+
+	_, err := g.store(context.Background(), scoreChange{kind: changeKill, name: "alice", points: 10})
+
+	assert.ErrorIs(t, err, errSavesBacklogged)
+	assert.Nil(t, findIn(g.roster().players, "alice"))
+}
+
+func TestSettle_DebouncedWaitsForQuiet(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, _ := newPersistenceGame(PersistDebounced, clock)
+
+	settled := make(chan bool)
+	go func() { settled <- g.saves.settle(context.Background()) }()
+
+	assert.Equal(t, 5*time.Second, <-clock.waits)
+	clock.Advance(3 * time.Second)
+	g.saves.wake <- struct{}{}
+
+	// another change starts the delay again
+	assert.Equal(t, 5*time.Second, <-clock.waits)
+	clock.Advance(5 * time.Second)
+	assert.True(t, <-settled)
+}
+
+func TestSettle_BatchWaitsFromFirstChange(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, _ := newPersistenceGame(PersistBatch, clock)
+
+	settled := make(chan bool)
+	go func() { settled <- g.saves.settle(context.Background()) }()
+
+	assert.Equal(t, 5*time.Second, <-clock.waits)
+	g.saves.wake <- struct{}{}
+	clock.Advance(5 * time.Second)
+
+	assert.True(t, <-settled)
+	assert.Empty(t, clock.waits, "later changes must not push the batch back")
+}
+
+func TestFlush_RetriesWithBackoff(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistImmediate, clock)
+	ctx := context.Background()
+	repo.scoreErr = errors.New("database is down")
+
+	_, err := g.store(ctx, scoreChange{kind: changeKill, name: "alice", points: 10})
+	require.NoError(t, err)
+
+	flushed := make(chan error)
+	go func() { flushed <- g.saves.Flush(ctx) }()
+
+	assert.Equal(t, time.Second, <-clock.waits)
+	clock.Advance(time.Second)
+	assert.Equal(t, 2*time.Second, <-clock.waits)
+
+	repo.scoreErr = nil
+	clock.Advance(2 * time.Second)
+
+	require.NoError(t, <-flushed)
+	assert.Equal(t, 10, repo.players["testnet|#test|alice"].Points)
+}
+
+func TestFlush_GivesUpWhenContextIsDone(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistDebounced, clock)
+	repo.scoreErr = errors.New("database is down")
+
+	_, err := g.store(context.Background(), scoreChange{kind: changeMiss, name: "alice"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorContains(t, g.Shutdown(ctx), "1 scores not saved")
+	assert.Equal(t, 1, g.saves.Waiting())
+}
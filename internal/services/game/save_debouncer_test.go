@@ -1,225 +1,136 @@
-package game_test
+package game
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
-	"github.com/MyelinBots/pigeonbot-go/config"
-	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
-	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player/mocks"
-	"github.com/MyelinBots/pigeonbot-go/internal/services/commands"
-	"github.com/MyelinBots/pigeonbot-go/internal/services/game"
-	gameMocks "github.com/MyelinBots/pigeonbot-go/internal/services/game/mocks"
-	irc "github.com/fluffle/goirc/client"
-	"go.uber.org/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestDebouncer_BatchesSaves(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	playerRepository := mocks.NewMockPlayerRepository(ctrl)
-	playerRepository.EggsByKey = make(map[string]int)
-	playerRepository.RareEggsByKey = make(map[string]int)
-	ircClient := gameMocks.NewMockIRCClient(ctrl)
-
-	// Expect GetAllPlayers for syncPlayers
-	playerRepository.EXPECT().GetAllPlayers(gomock.Any(), "network", "channel").
-		Return([]*player.Player{}, nil).
-		Times(1)
-
-	// Expect pigeon spawn message
-	ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
-
-	// Track UpsertPlayer calls - debouncing should reduce total calls
-	var upsertCount int
-	playerRepository.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, p *player.Player) error {
-			upsertCount++
-			t.Logf("UpsertPlayer called #%d for: %s (points=%d)", upsertCount, p.Name, p.Points)
-			return nil
-		}).
-		AnyTimes()
-
-	gameInstance := game.NewGame(
-		config.GameConfig{Interval: 1},
-		ircClient,
-		playerRepository,
-		"network",
-		"channel",
-	)
-
-	commandController := commands.NewCommandController(gameInstance)
-	commandController.AddCommand("!shoot", gameInstance.HandleShoot)
-
+// startSaving runs g's save loop until the test ends
+func startSaving(t *testing.T, g *Game) {
 	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	go gameInstance.Start(ctx)
-
-	// Wait for pigeon to spawn
-	time.Sleep(1500 * time.Millisecond)
-
-	// Spam 3 shots rapidly from same user
-	for i := 0; i < 3; i++ {
-		line := &irc.Line{
-			Args: []string{"channel", "!shoot"},
-			Nick: "spammer",
-		}
-		_ = commandController.HandleCommand(ctx, line)
-	}
-
-	// Wait for debounce to flush (2s delay + buffer)
-	time.Sleep(3 * time.Second)
-
-	// The key point: without debouncing, we'd have 3+ saves.
-	// With debouncing, saves are batched. addPlayer does 1 save,
-	// then debouncer does 1 save (total ~2), not 4+.
-	t.Logf("Total UpsertPlayer calls: %d (should be ~2, not 4+)", upsertCount)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.saves.run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
 }
 
-func TestDebouncer_MultiplePlayers(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	playerRepository := mocks.NewMockPlayerRepository(ctrl)
-	playerRepository.EggsByKey = make(map[string]int)
-	playerRepository.RareEggsByKey = make(map[string]int)
-	ircClient := gameMocks.NewMockIRCClient(ctrl)
-
-	// Pre-load both players so addPlayer doesn't get called
-	playerRepository.EXPECT().GetAllPlayers(gomock.Any(), "network", "channel").
-		Return([]*player.Player{
-			{ID: "1", Name: "player1", Points: 0, Count: 0},
-			{ID: "2", Name: "player2", Points: 0, Count: 0},
-		}, nil).
-		Times(1)
-
-	ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
-
-	// Track which players get saved via debouncer
-	savedPlayers := make(map[string]int)
-	playerRepository.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, p *player.Player) error {
-			savedPlayers[p.Name]++
-			t.Logf("UpsertPlayer called for: %s (count=%d)", p.Name, savedPlayers[p.Name])
-			return nil
-		}).
-		AnyTimes()
-
-	gameInstance := game.NewGame(
-		config.GameConfig{Interval: 1},
-		ircClient,
-		playerRepository,
-		"network",
-		"channel",
-	)
-
-	commandController := commands.NewCommandController(gameInstance)
-	commandController.AddCommand("!shoot", gameInstance.HandleShoot)
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	go gameInstance.Start(ctx)
+// waitUntilSaved waits for the save loop to write everything queued
+func waitUntilSaved(t *testing.T, g *Game) {
+	t.Helper()
+	require.Eventually(t, func() bool { return g.saves.Waiting() == 0 }, time.Second, time.Millisecond)
+}
 
-	// Wait for pigeon to spawn
-	time.Sleep(1500 * time.Millisecond)
+func kill(name string) scoreChange {
+	return scoreChange{kind: changeKill, name: name, points: 10}
+}
 
-	// Two different players shoot
-	line1 := &irc.Line{
-		Args: []string{"channel", "!shoot"},
-		Nick: "player1",
-	}
-	_ = commandController.HandleCommand(ctx, line1)
+func TestDebouncer_ImmediateWritesEachShot(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistImmediate, clock)
+	startSaving(t, g)
+	ctx := context.Background()
 
-	line2 := &irc.Line{
-		Args: []string{"channel", "!shoot"},
-		Nick: "player2",
+	for _, name := range []string{"alice", "alice", "bob"} {
+		_, err := g.store(ctx, kill(name))
+		require.NoError(t, err)
 	}
-	_ = commandController.HandleCommand(ctx, line2)
 
-	// Wait for debounce to flush
-	time.Sleep(3 * time.Second)
+	assert.Equal(t, int32(3), repo.writes.Load(), "every shot is written before it is announced")
+	assert.Zero(t, g.saves.Waiting())
+	assert.Empty(t, clock.waits, "nothing waits for the clock")
+}
 
-	// At least one player should have been saved (the one who shot successfully)
-	// Note: if player1 kills the pigeon, player2 may miss and not get saved
-	totalSaves := savedPlayers["player1"] + savedPlayers["player2"]
-	if totalSaves == 0 {
-		t.Errorf("Expected at least one player to be saved, got: %v", savedPlayers)
-	}
-	t.Logf("Final save counts: %v (debouncer batched saves)", savedPlayers)
+func TestDebouncer_DebouncedWritesOnceShotsStop(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistDebounced, clock)
+	startSaving(t, g)
+	ctx := context.Background()
+
+	_, err := g.store(ctx, kill("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, <-clock.waits)
+
+	clock.Advance(4 * time.Second)
+	_, err = g.store(ctx, kill("bob"))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, <-clock.waits, "a new shot starts the wait again")
+
+	clock.Advance(4 * time.Second)
+	assert.Zero(t, repo.writes.Load(), "nothing is written while shots keep coming")
+
+	clock.Advance(time.Second)
+	waitUntilSaved(t, g)
+	assert.Equal(t, int32(2), repo.writes.Load())
+	assert.Equal(t, 10, repo.players["testnet|#test|alice"].Points)
+	assert.Equal(t, 10, repo.players["testnet|#test|bob"].Points)
 }
 
-func TestDebouncer_FlushOnShutdown(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	playerRepository := mocks.NewMockPlayerRepository(ctrl)
-	playerRepository.EggsByKey = make(map[string]int)
-	playerRepository.RareEggsByKey = make(map[string]int)
-	ircClient := gameMocks.NewMockIRCClient(ctrl)
-
-	playerRepository.EXPECT().GetAllPlayers(gomock.Any(), "network", "channel").
-		Return([]*player.Player{}, nil).
-		Times(1)
-
-	ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
-
-	// Track if UpsertPlayer was called during shutdown flush
-	var flushedOnShutdown bool
-	playerRepository.EXPECT().UpsertPlayer(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, p *player.Player) error {
-			t.Logf("UpsertPlayer called for: %s", p.Name)
-			flushedOnShutdown = true
-			return nil
-		}).
-		AnyTimes()
-
-	gameInstance := game.NewGame(
-		config.GameConfig{Interval: 60}, // Long interval so debounce won't fire naturally
-		ircClient,
-		playerRepository,
-		"network",
-		"channel",
-	)
-
-	commandController := commands.NewCommandController(gameInstance)
-	commandController.AddCommand("!shoot", gameInstance.HandleShoot)
+func TestDebouncer_BatchWritesADelayAfterTheFirstShot(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistBatch, clock)
+	startSaving(t, g)
+	ctx := context.Background()
+
+	_, err := g.store(ctx, kill("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, <-clock.waits)
+
+	clock.Advance(3 * time.Second)
+	_, err = g.store(ctx, kill("alice"))
+	require.NoError(t, err)
+	assert.Zero(t, repo.writes.Load())
+
+	// the later shot does not push the batch back
+	clock.Advance(2 * time.Second)
+	waitUntilSaved(t, g)
+	assert.Equal(t, int32(2), repo.writes.Load())
+	assert.Equal(t, 20, repo.players["testnet|#test|alice"].Points)
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+func TestDebouncer_RetriesFailedWritesWithGrowingBackoff(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistImmediate, clock)
+	repo.scoreErr = errors.New("database is down")
+	startSaving(t, g)
+
+	// the write made for the shot fails, so it is queued and retried
+	_, err := g.store(context.Background(), kill("alice"))
+	require.NoError(t, err)
+
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		assert.Equal(t, delay, <-clock.waits)
+		assert.Equal(t, int32(i+2), repo.writes.Load())
+		if i < 2 {
+			clock.Advance(delay)
+		}
+	}
 
-	done := make(chan struct{})
-	go func() {
-		gameInstance.Start(ctx)
-		close(done)
-	}()
+	repo.scoreErr = nil
+	clock.Advance(4 * time.Second)
+	waitUntilSaved(t, g)
+	assert.Equal(t, int32(5), repo.writes.Load())
+	assert.Equal(t, 10, repo.players["testnet|#test|alice"].Points)
+}
 
-	// Wait for pigeon to spawn (first ActOnPlayer runs immediately)
-	time.Sleep(100 * time.Millisecond)
+func TestDebouncer_ShutdownWritesWhatIsWaiting(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	g, repo := newPersistenceGame(PersistBatch, clock)
 
-	// Shoot once
-	line := &irc.Line{
-		Args: []string{"channel", "!shoot"},
-		Nick: "shooter",
-	}
-	_ = commandController.HandleCommand(ctx, line)
-
-	// Cancel context immediately (before debounce timer fires at 2s)
-	time.Sleep(100 * time.Millisecond)
-	cancel()
-
-	// Wait for Start to return
-	select {
-	case <-done:
-		t.Log("Start returned after cancel")
-	case <-time.After(3 * time.Second):
-		t.Fatal("Start did not return after cancel")
-	}
+	_, err := g.store(context.Background(), kill("alice"))
+	require.NoError(t, err)
+	assert.Zero(t, repo.writes.Load())
 
-	// Verify flush happened
-	if !flushedOnShutdown {
-		t.Log("Note: UpsertPlayer may not have been called if shot missed")
-	}
+	require.NoError(t, g.Shutdown(context.Background()))
+	assert.Equal(t, int32(1), repo.writes.Load())
+	assert.Zero(t, g.saves.Waiting())
 }
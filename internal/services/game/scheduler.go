@@ -0,0 +1,202 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+)
+
+const defaultInterval = 120 * time.Second // used when GameConfig.Interval is 0
+
+// quietWindow is a daily range in minutes since midnight; it may wrap past midnight
+type quietWindow struct {
+	start int
+	end   int
+}
+
+func (w quietWindow) contains(minute int) bool {
+	if w.start <= w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// scheduler decides how long the game loop waits between spawns.
+// The wait is jittered, scaled by channel activity and paused for quiet hours.
+type scheduler struct {
+	mu    sync.Mutex
+	cfg   config.ScheduleConfig
+	base  time.Duration
+	clock Clock
+	rand  Rand
+	loc   *time.Location
+	quiet []quietWindow
+
+	lastActivity time.Time
+	recent       []time.Time
+}
+
+func newScheduler(cfg config.ScheduleConfig, interval int, clock Clock, rng Rand) *scheduler {
+	base := time.Duration(interval) * time.Second
+	if base <= 0 {
+		base = defaultInterval
+	}
+
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		l, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			fmt.Printf("Invalid schedule timezone %q: %v\n", cfg.Timezone, err)
+		} else {
+			loc = l
+		}
+	}
+
+	quiet, err := parseQuietHours(cfg.QuietHours)
+	if err != nil {
+		fmt.Printf("Invalid quiet hours: %v\n", err)
+	}
+
+	return &scheduler{
+		cfg:          cfg,
+		base:         base,
+		clock:        clock,
+		rand:         rng,
+		loc:          loc,
+		quiet:        quiet,
+		lastActivity: clock.Now(),
+	}
+}
+
+// parseQuietHours parses a comma separated list of "HH:MM-HH:MM" ranges
+func parseQuietHours(s string) ([]quietWindow, error) {
+	var windows []quietWindow
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("quiet hours %q: expected HH:MM-HH:MM", part)
+		}
+		start, err := parseClockMinute(from)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours %q: %w", part, err)
+		}
+		end, err := parseClockMinute(to)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours %q: %w", part, err)
+		}
+
+		windows = append(windows, quietWindow{start: start, end: end})
+	}
+	return windows, nil
+}
+
+func parseClockMinute(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// NoteActivity records a message in the channel
+func (s *scheduler) NoteActivity() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.lastActivity = now
+	if s.cfg.ActivityWindow > 0 {
+		s.recent = append(s.pruneActivity(now), now)
+	}
+}
+
+// Quiet reports whether spawns are paused for quiet hours
+func (s *scheduler) Quiet() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.quietRemaining(s.clock.Now()) > 0
+}
+
+// NextDelay returns how long to wait before the next spawn attempt
+func (s *scheduler) NextDelay() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if remaining := s.quietRemaining(now); remaining > 0 {
+		return remaining
+	}
+
+	return time.Duration(float64(s.interval()) * s.activityFactor(now))
+}
+
+// quietRemaining returns how long the quiet window containing now lasts, or 0
+func (s *scheduler) quietRemaining(now time.Time) time.Duration {
+	local := now.In(s.loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, w := range s.quiet {
+		if !w.contains(minute) {
+			continue
+		}
+		minutes := (w.end - minute + 24*60) % (24 * 60)
+		return time.Duration(minutes)*time.Minute -
+			time.Duration(local.Second())*time.Second -
+			time.Duration(local.Nanosecond())
+	}
+	return 0
+}
+
+// interval picks a random wait in [MinInterval, MaxInterval], or the base interval
+func (s *scheduler) interval() time.Duration {
+	lo := time.Duration(s.cfg.MinInterval) * time.Second
+	hi := time.Duration(s.cfg.MaxInterval) * time.Second
+	if lo <= 0 && hi <= 0 {
+		return s.base
+	}
+	if lo <= 0 {
+		lo = hi
+	}
+	if hi < lo {
+		hi = lo
+	}
+	if hi == lo {
+		return lo
+	}
+	return lo + time.Duration(s.rand.Int64N(int64(hi-lo)+1))
+}
+
+// activityFactor speeds spawns up in busy channels and slows them down in idle ones
+func (s *scheduler) activityFactor(now time.Time) float64 {
+	s.recent = s.pruneActivity(now)
+
+	if s.cfg.ActiveMessages > 0 && s.cfg.ActiveFactor > 0 && len(s.recent) >= s.cfg.ActiveMessages {
+		return s.cfg.ActiveFactor
+	}
+
+	idleAfter := time.Duration(s.cfg.IdleAfter) * time.Second
+	if idleAfter > 0 && s.cfg.IdleFactor > 0 && now.Sub(s.lastActivity) >= idleAfter {
+		return s.cfg.IdleFactor
+	}
+
+	return 1
+}
+
+// pruneActivity drops messages that fell out of the activity window
+func (s *scheduler) pruneActivity(now time.Time) []time.Time {
+	cutoff := now.Add(-time.Duration(s.cfg.ActivityWindow) * time.Second)
+	i := 0
+	for i < len(s.recent) && !s.recent[i].After(cutoff) {
+		i++
+	}
+	return s.recent[i:]
+}
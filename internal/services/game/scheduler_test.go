@@ -0,0 +1,199 @@
+package game
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/actions"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/pigeon"
+	"github.com/jinzhu/configor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced Clock; After channels fire once Advance passes them
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	waits   chan time.Duration
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, waits: make(chan time.Duration, 100)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.waits <- d
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- c.now
+			continue
+		}
+		pending = append(pending, w)
+	}
+	c.waiters = pending
+}
+
+func TestParseQuietHours(t *testing.T) {
+	windows, err := parseQuietHours("23:00-07:00, 13:30-14:00")
+	require.NoError(t, err)
+	assert.Equal(t, []quietWindow{{start: 23 * 60, end: 7 * 60}, {start: 13*60 + 30, end: 14 * 60}}, windows)
+
+	windows, err = parseQuietHours("")
+	require.NoError(t, err)
+	assert.Empty(t, windows)
+
+	_, err = parseQuietHours("23:00")
+	assert.Error(t, err)
+
+	_, err = parseQuietHours("25:00-07:00")
+	assert.Error(t, err)
+}
+
+func TestScheduler_FixedInterval(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	s := newScheduler(config.ScheduleConfig{}, 30, clock, globalRand{})
+	assert.Equal(t, 30*time.Second, s.NextDelay())
+
+	// Interval 0 falls back to the default
+	s = newScheduler(config.ScheduleConfig{}, 0, clock, globalRand{})
+	assert.Equal(t, defaultInterval, s.NextDelay())
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	s := newScheduler(config.ScheduleConfig{MinInterval: 60, MaxInterval: 90}, 10, clock, globalRand{})
+
+	for i := 0; i < 100; i++ {
+		d := s.NextDelay()
+		assert.True(t, d >= 60*time.Second && d <= 90*time.Second, "delay %v out of range", d)
+	}
+
+	// Max below min collapses to min
+	s = newScheduler(config.ScheduleConfig{MinInterval: 60, MaxInterval: 30}, 10, clock, globalRand{})
+	assert.Equal(t, 60*time.Second, s.NextDelay())
+}
+
+func TestScheduler_QuietHours(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	require.NoError(t, err)
+
+	// 23:30 in Bangkok
+	clock := newFakeClock(time.Date(2024, 1, 1, 23, 30, 0, 0, bangkok).UTC())
+	s := newScheduler(config.ScheduleConfig{QuietHours: "23:00-07:00", Timezone: "Asia/Bangkok"}, 30, clock, globalRand{})
+
+	assert.True(t, s.Quiet())
+	assert.Equal(t, 7*time.Hour+30*time.Minute, s.NextDelay())
+
+	clock.Advance(7*time.Hour + 30*time.Minute)
+	assert.False(t, s.Quiet())
+	assert.Equal(t, 30*time.Second, s.NextDelay())
+}
+
+func TestScheduler_Activity(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	s := newScheduler(config.ScheduleConfig{
+		ActiveMessages: 3,
+		ActivityWindow: 60,
+		ActiveFactor:   0.5,
+		IdleAfter:      600,
+		IdleFactor:     2,
+	}, 100, clock, globalRand{})
+
+	assert.Equal(t, 100*time.Second, s.NextDelay())
+
+	// Busy channel spawns twice as often
+	for i := 0; i < 3; i++ {
+		s.NoteActivity()
+	}
+	assert.Equal(t, 50*time.Second, s.NextDelay())
+
+	// Messages age out of the window
+	clock.Advance(61 * time.Second)
+	assert.Equal(t, 100*time.Second, s.NextDelay())
+
+	// Silent channel waits twice as long
+	clock.Advance(600 * time.Second)
+	assert.Equal(t, 200*time.Second, s.NextDelay())
+}
+
+func TestScheduler_DefaultsSlowIdleChannels(t *testing.T) {
+	var cfg config.ScheduleConfig
+	require.NoError(t, configor.New(&configor.Config{}).Load(&cfg))
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	s := newScheduler(cfg, 100, clock, globalRand{})
+
+	s.NoteActivity()
+	assert.Equal(t, 100*time.Second, s.NextDelay())
+
+	clock.Advance(time.Duration(cfg.IdleAfter) * time.Second)
+	assert.Greater(t, s.NextDelay(), 100*time.Second, "an idle channel waits longer")
+}
+
+func TestGameStart_UsesScheduler(t *testing.T) {
+	repo := newMockPlayerRepoForTest()
+	client := &mockIRCClientForTest{}
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	g := &Game{
+		activePigeon:     &ActivePigeon{},
+		actions:          []actions.Action{{Action: "landed", Items: []string{"car"}, Format: "A %s pigeon has %s on your %s"}},
+		pigeons:          pigeon.PredefinedPigeons(),
+		playerRepository: repo,
+		ircClient:        client,
+		channel:          "test",
+		network:          "testnet",
+		clock:            clock,
+		scheduler:        newScheduler(config.ScheduleConfig{}, 30, clock, globalRand{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		g.Start(ctx)
+		close(done)
+	}()
+
+	// First spawn happens straight away, then the loop waits on the clock
+	assert.Equal(t, 30*time.Second, <-clock.waits)
+	g.activePigeon.Lock()
+	assert.NotNil(t, g.activePigeon.activePigeon)
+	g.activePigeon.Unlock()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+}
@@ -0,0 +1,265 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	season2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+)
+
+// seasonsEnabled reports whether this game runs seasons
+func (g *Game) seasonsEnabled() bool {
+	return g.seasonRepository != nil && g.config.SeasonDays > 0
+}
+
+func (g *Game) seasonLength() time.Duration {
+	return time.Duration(g.config.SeasonDays) * 24 * time.Hour
+}
+
+// checkSeason starts the first season and ends the running one once it is
+// over. The game loop calls it before every spawn.
+func (g *Game) checkSeason(ctx context.Context) {
+	if !g.seasonsEnabled() {
+		return
+	}
+
+	g.seasonMu.Lock()
+	defer g.seasonMu.Unlock()
+
+	now := g.now()
+	if g.season == nil {
+		s, err := g.seasonRepository.CurrentSeason(ctx, g.network, g.scope())
+		if err != nil {
+			fmt.Printf("Error loading season for %s: %v\n", g.channel, err)
+			return
+		}
+		if s == nil {
+			s, err = g.seasonRepository.StartSeason(ctx, g.network, g.scope(), now, now.Add(g.seasonLength()))
+			if err != nil {
+				fmt.Printf("Error starting season for %s: %v\n", g.channel, err)
+				return
+			}
+		}
+		g.season = s
+	}
+
+	if now.Before(g.season.EndsAt) {
+		return
+	}
+	g.endSeason(ctx, now)
+}
+
+// endSeason archives the standings, shrinks every score and starts the next
+// season. The caller holds seasonMu.
+func (g *Game) endSeason(ctx context.Context, now time.Time) {
+	// the standings are read back from the database; try again next loop
+	if err := g.writeWaiting(ctx); err != nil {
+		fmt.Printf("Error saving scores before the season ends: %v\n", err)
+		return
+	}
+
+	roster := g.roster()
+	roster.Lock()
+	defer roster.Unlock()
+
+	players, err := g.playerRepository.GetAllPlayers(ctx, g.network, g.scope())
+	if err != nil {
+		fmt.Printf("Error loading season standings: %v\n", err)
+		return
+	}
+	results := g.seasonResults(players)
+
+	ended := g.season
+	keep := min(max(g.config.SeasonKeepPercent, 0), 100)
+	next, err := g.seasonRepository.EndSeason(ctx, ended, results, keep, now, now.Add(g.seasonLength()))
+	if errors.Is(err, season2.ErrSeasonEnded) {
+		// another game in the score pool ended it, and shrank the shared roster
+		g.season = nil
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error ending season %d: %v\n", ended.Number, err)
+		return
+	}
+
+	for _, p := range roster.players {
+		p.Points = p.Points * keep / 100
+		p.Count = p.Count * keep / 100
+		p.Eggs = p.Eggs * keep / 100
+		p.RareEggs = p.RareEggs * keep / 100
+	}
+	g.season = next
+
+	g.irc().Privmsg(g.channel, g.seasonSummary(ended.Number, results))
+	g.irc().Privmsg(g.channel, g.msg(
+		msgSeasonBegun,
+		next.Number, next.EndsAt.In(g.location()).Format(dateLayout), keep,
+	))
+}
+
+// seasonResults ranks players for the archive; players who never scored are left out
+func (g *Game) seasonResults(players []*player2.Player) []*season2.SeasonResult {
+	ranked := make([]*player2.Player, 0, len(players))
+	for _, p := range players {
+		if p.Points > 0 || p.Count > 0 {
+			ranked = append(ranked, p)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Points != ranked[j].Points {
+			return ranked[i].Points > ranked[j].Points
+		}
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+
+	results := make([]*season2.SeasonResult, 0, len(ranked))
+	for i, p := range ranked {
+		badge := ""
+		if i < 3 {
+			badge = medal(i)
+		}
+		results = append(results, &season2.SeasonResult{
+			Name:     p.Name,
+			Rank:     i + 1,
+			Points:   p.Points,
+			Count:    p.Count,
+			Eggs:     p.Eggs,
+			RareEggs: p.RareEggs,
+			Title:    g.LevelFor(p.Points, p.Count),
+			Badge:    badge,
+		})
+	}
+	return results
+}
+
+func (g *Game) seasonSummary(number int, results []*season2.SeasonResult) string {
+	if len(results) == 0 {
+		return g.msg(msgSeasonOverNone, number)
+	}
+	podium := make([]string, 0, 3)
+	for _, r := range results[:min(3, len(results))] {
+		podium = append(podium, g.msg(msgSeasonPodium, r.Badge, r.Name, fmtNum(r.Points)))
+	}
+	return g.msg(msgSeasonOver, number, strings.Join(podium, " "))
+}
+
+// badges lists the season medals name has won, e.g. "🥇S1 🥉S3"
+func (g *Game) badges(ctx context.Context, name string) (string, error) {
+	if g.seasonRepository == nil {
+		return "", nil
+	}
+	won, err := g.seasonRepository.GetBadges(ctx, g.network, g.scope(), canonicalPlayerName(name))
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, 0, len(won))
+	for _, r := range won {
+		parts = append(parts, g.msg(msgSeasonBadge, r.Badge, r.SeasonNumber))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// HandleSeason shows the running season, or a past one's final standings: !season [n]
+func (g *Game) HandleSeason(ctx context.Context, args ...string) error {
+	if !g.seasonsEnabled() {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonDisabled))
+		return nil
+	}
+	g.checkSeason(ctx)
+
+	g.seasonMu.Lock()
+	current := g.season
+	g.seasonMu.Unlock()
+	if current == nil {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonError))
+		return nil
+	}
+
+	if len(args) == 0 {
+		return g.showCurrentSeason(ctx, current)
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonUsage))
+		return nil
+	}
+	if number == current.Number {
+		return g.showCurrentSeason(ctx, current)
+	}
+
+	s, err := g.seasonRepository.GetSeason(ctx, g.network, g.scope(), number)
+	if err != nil {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonError))
+		return err
+	}
+	if s == nil || s.EndedAt == nil {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonNotYet, number, current.Number))
+		return nil
+	}
+
+	results, err := g.seasonRepository.GetResults(ctx, s.ID, 5)
+	if err != nil {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonError))
+		return err
+	}
+
+	loc := g.location()
+	g.irc().Privmsg(g.channel, fmt.Sprintf("%s%s%s", ircBold, c(g.msg(
+		msgSeasonFinal,
+		s.Number, s.StartedAt.In(loc).Format(dateLayout), s.EndedAt.In(loc).Format(dateLayout),
+	), 8), ircReset))
+	if len(results) == 0 {
+		g.irc().Privmsg(g.channel, g.msg(msgSeasonNobody))
+		return nil
+	}
+	for _, r := range results {
+		g.irc().Privmsg(g.channel, fmt.Sprintf(
+			"%s %s :::::: %s | %s | %s",
+			seasonRank(r.Rank),
+			r.Name,
+			c(g.msg(msgLabelPoints, fmtNum(r.Points)), 7),
+			c(g.msg(msgLabelPigeons, fmtNum(r.Count)), 4),
+			c(r.Title, 13),
+		))
+	}
+	return nil
+}
+
+func (g *Game) showCurrentSeason(ctx context.Context, s *season2.Season) error {
+	left := s.EndsAt.Sub(g.now())
+	days := int(left.Hours() / 24)
+	g.irc().Privmsg(g.channel, fmt.Sprintf("%s%s%s", ircBold, c(g.msg(
+		msgSeasonCurrent,
+		s.Number, s.EndsAt.In(g.location()).Format(dateLayout), max(days, 0),
+	), 8), ircReset))
+
+	top, err := g.TopByPoints(ctx, 5)
+	if err != nil {
+		g.irc().Privmsg(g.channel, g.msg(msgTopError))
+		return err
+	}
+	for i, p := range top {
+		g.irc().Privmsg(g.channel, fmt.Sprintf(
+			"%s %s :::::: %s | %s",
+			medal(i),
+			p.Name,
+			c(g.msg(msgLabelPoints, fmtNum(p.Points)), 7),
+			c(g.msg(msgLabelPigeons, fmtNum(p.Count)), 4),
+		))
+	}
+	return nil
+}
+
+func seasonRank(rank int) string {
+	return medal(rank - 1)
+}
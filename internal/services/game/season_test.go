@@ -0,0 +1,173 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	player2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player"
+	playerMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/player/mocks"
+	season2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	seasonMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season/mocks"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newSeasonGame(t *testing.T, keep int) (*Game, *mockIRCClientForTest, *fakeClock, *seasonMocks.MockSeasonRepository, *playerMocks.MockPlayerRepository) {
+	ctrl := gomock.NewController(t)
+	seasons := seasonMocks.NewMockSeasonRepository(ctrl)
+	players := playerMocks.NewMockPlayerRepository(ctrl)
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+
+	cfg := config.GameConfig{SeasonDays: 30, SeasonKeepPercent: keep}
+	g := NewGame(cfg, client, players, "testnet", "#test", WithClock(clock), WithSeasonRepository(seasons))
+	return g, client, clock, seasons, players
+}
+
+func TestCheckSeason_StartsFirstSeason(t *testing.T) {
+	g, client, clock, seasons, _ := newSeasonGame(t, 0)
+	ctx := context.Background()
+
+	first := &season2.Season{ID: "s1", Number: 1, StartedAt: clock.Now(), EndsAt: clock.Now().Add(30 * 24 * time.Hour)}
+	seasons.EXPECT().CurrentSeason(ctx, "testnet", "#test").Return(nil, nil)
+	seasons.EXPECT().StartSeason(ctx, "testnet", "#test", clock.Now(), first.EndsAt).Return(first, nil)
+
+	g.checkSeason(ctx)
+	assert.Same(t, first, g.season)
+
+	// cached until it ends
+	g.checkSeason(ctx)
+	assert.Empty(t, client.messages)
+}
+
+func TestCheckSeason_Disabled(t *testing.T) {
+	g := NewGame(config.GameConfig{}, &mockIRCClientForTest{}, newMockPlayerRepoForTest(), "testnet", "#test")
+	g.checkSeason(context.Background())
+	assert.Nil(t, g.season)
+
+	client := &mockIRCClientForTest{}
+	g.ircClient = client
+	require.NoError(t, g.HandleSeason(context.Background()))
+	assert.Equal(t, []string{"🏁 Seasons are not enabled here"}, client.messages)
+}
+
+func TestCheckSeason_Rollover(t *testing.T) {
+	g, client, clock, seasons, players := newSeasonGame(t, 50)
+	ctx := context.Background()
+
+	g.season = &season2.Season{ID: "s1", Number: 1, EndsAt: clock.Now().Add(time.Hour)}
+	g.players.players = []*player.Player{player.NewPlayer("alice", 100, 10), player.NewPlayer("bob", 51, 5)}
+	clock.Advance(time.Hour)
+
+	players.EXPECT().GetAllPlayers(ctx, "testnet", "#test").Return([]*player2.Player{
+		{Name: "bob", Points: 51, Count: 5, Eggs: 2},
+		{Name: "idle"},
+		{Name: "alice", Points: 100, Count: 10, RareEggs: 1},
+	}, nil)
+
+	next := &season2.Season{ID: "s2", Number: 2, EndsAt: clock.Now().Add(30 * 24 * time.Hour)}
+	seasons.EXPECT().EndSeason(ctx, g.season, gomock.Any(), 50, clock.Now(), next.EndsAt).
+		DoAndReturn(func(_ context.Context, _ *season2.Season, results []*season2.SeasonResult, _ int, _, _ time.Time) (*season2.Season, error) {
+			require.Len(t, results, 2, "players who never scored are not archived")
+			assert.Equal(t, "alice", results[0].Name)
+			assert.Equal(t, 1, results[0].Rank)
+			assert.Equal(t, "🥇", results[0].Badge)
+			assert.Equal(t, 1, results[0].RareEggs)
+			assert.NotEmpty(t, results[0].Title)
+			assert.Equal(t, "bob", results[1].Name)
+			assert.Equal(t, "🥈", results[1].Badge)
+			assert.Equal(t, 2, results[1].Eggs)
+			return next, nil
+		})
+
+	g.checkSeason(ctx)
+
+	assert.Same(t, next, g.season)
+	assert.Equal(t, 50, g.players.players[0].Points)
+	assert.Equal(t, 5, g.players.players[0].Count)
+	assert.Equal(t, 25, g.players.players[1].Points)
+	assert.Equal(t, 2, g.players.players[1].Count)
+
+	require.Len(t, client.messages, 2)
+	assert.Equal(t, "🏁 Season 1 is over! 🥇 alice (100 points) 🥈 bob (51 points)", client.messages[0])
+	assert.Contains(t, client.messages[1], "Season 2 has begun and runs until 2024-03-31")
+}
+
+func TestCheckSeason_EndedElsewhere(t *testing.T) {
+	g, client, clock, seasons, players := newSeasonGame(t, 0)
+	ctx := context.Background()
+
+	g.season = &season2.Season{ID: "s1", Number: 1, EndsAt: clock.Now()}
+	g.players.players = []*player.Player{player.NewPlayer("alice", 100, 10)}
+
+	players.EXPECT().GetAllPlayers(ctx, "testnet", "#test").Return(nil, nil)
+	seasons.EXPECT().EndSeason(ctx, gomock.Any(), gomock.Any(), 0, gomock.Any(), gomock.Any()).Return(nil, season2.ErrSeasonEnded)
+
+	g.checkSeason(ctx)
+
+	assert.Nil(t, g.season, "reloaded on the next check")
+	assert.Equal(t, 100, g.players.players[0].Points, "the other game already reset the scores")
+	assert.Empty(t, client.messages)
+}
+
+func TestHandleSeason(t *testing.T) {
+	g, client, clock, seasons, players := newSeasonGame(t, 0)
+	ctx := context.Background()
+
+	g.season = &season2.Season{ID: "s3", Number: 3, EndsAt: clock.Now().Add(10*24*time.Hour + time.Hour)}
+
+	t.Run("current season", func(t *testing.T) {
+		client.messages = nil
+		players.EXPECT().TopByPoints(ctx, "testnet", "#test", 5).Return([]*player2.Player{{Name: "alice", Points: 40, Count: 4}}, nil)
+
+		require.NoError(t, g.HandleSeason(ctx))
+		require.Len(t, client.messages, 2)
+		assert.Contains(t, client.messages[0], "Season 3 ends 2024-03-11 (10 day(s) left)")
+		assert.Contains(t, client.messages[1], "alice")
+	})
+
+	t.Run("past season", func(t *testing.T) {
+		client.messages = nil
+		ended := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		seasons.EXPECT().GetSeason(ctx, "testnet", "#test", 1).Return(&season2.Season{
+			ID: "s1", Number: 1, StartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndedAt: &ended,
+		}, nil)
+		seasons.EXPECT().GetResults(ctx, "s1", 5).Return([]*season2.SeasonResult{
+			{Name: "veteran", Rank: 1, Points: 9000, Count: 300, Title: "Pigeon Slayer", Badge: "🥇"},
+		}, nil)
+
+		require.NoError(t, g.HandleSeason(ctx, "1"))
+		require.Len(t, client.messages, 2)
+		assert.Contains(t, client.messages[0], "Season 1 final standings (2024-01-01 to 2024-02-01)")
+		assert.Contains(t, client.messages[1], "🥇 veteran")
+		assert.Contains(t, client.messages[1], "Pigeon Slayer")
+	})
+
+	t.Run("future season", func(t *testing.T) {
+		client.messages = nil
+		seasons.EXPECT().GetSeason(ctx, "testnet", "#test", 7).Return(nil, nil)
+
+		require.NoError(t, g.HandleSeason(ctx, "7"))
+		assert.Equal(t, []string{"🏁 There is no season 7 yet, this is season 3"}, client.messages)
+	})
+}
+
+func TestHandleStats_Badges(t *testing.T) {
+	g, client, _, seasons, players := newSeasonGame(t, 0)
+	ctx := context.Background()
+
+	players.EggsByKey = map[string]int{}
+	g.players.players = []*player.Player{player.NewPlayer("alice", 10, 1)}
+	seasons.EXPECT().GetBadges(ctx, "testnet", "#test", "alice").Return([]*season2.SeasonResult{
+		{Badge: "🥇", SeasonNumber: 1},
+		{Badge: "🥉", SeasonNumber: 3},
+	}, nil)
+
+	require.NoError(t, g.HandleStats(ctx, "Alice"))
+	require.Len(t, client.messages, 2)
+	assert.Contains(t, client.messages[0], "| Badges: 🥇S1 🥉S3")
+}
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultMinPigeonLifetime = 60 * time.Second // a pigeon cannot escape before this
+
+// Settings are the per-channel game tunables that can change at runtime with !set
+type Settings struct {
+	MaxAttemptsPerSpawn   int
+	ShootCooldown         time.Duration
+	RareEggAppearPercent  int
+	RareEggSuccessPercent int
+	RareEggPointBoost     int
+	MinPigeonLifetime     time.Duration
+	Timezone              string // IANA name for daily/weekly/monthly boards; empty uses the schedule's
+	Style                 Style  // how messages are rendered: colours, emoji or plain ASCII
+	Locale                string // language of the game's announcements, such as en or th
+}
+
+// DefaultSettings returns the settings used when a channel has none stored
+func DefaultSettings() Settings {
+	return Settings{
+		MaxAttemptsPerSpawn:   maxAttemptsPerSpawn,
+		ShootCooldown:         shootCooldown,
+		RareEggAppearPercent:  rareEggAppearPercent,
+		RareEggSuccessPercent: rareEggSuccessPercent,
+		RareEggPointBoost:     rareEggPointBoost,
+		MinPigeonLifetime:     defaultMinPigeonLifetime,
+		Style:                 StyleFull,
+		Locale:                defaultLocale,
+	}
+}
+
+// settingDef describes one !set key
+type settingDef struct {
+	name   string
+	parse  func(s *Settings, value string) error
+	format func(s Settings) string
+}
+
+var settingDefs = map[string]settingDef{}
+
+func init() {
+	for _, def := range []settingDef{
+		intSetting("max_attempts", 1, 1000, func(s *Settings) *int { return &s.MaxAttemptsPerSpawn }),
+		secondsSetting("shoot_cooldown", 0, 3600, func(s *Settings) *time.Duration { return &s.ShootCooldown }),
+		intSetting("rare_egg_appear", 0, 100, func(s *Settings) *int { return &s.RareEggAppearPercent }),
+		intSetting("rare_egg_success", 0, 100, func(s *Settings) *int { return &s.RareEggSuccessPercent }),
+		intSetting("rare_egg_boost", 0, 1_000_000, func(s *Settings) *int { return &s.RareEggPointBoost }),
+		secondsSetting("pigeon_lifetime", 0, 86400, func(s *Settings) *time.Duration { return &s.MinPigeonLifetime }),
+		timezoneSetting("timezone", func(s *Settings) *string { return &s.Timezone }),
+		styleSetting("style", func(s *Settings) *Style { return &s.Style }),
+		localeSetting("locale", func(s *Settings) *string { return &s.Locale }),
+	} {
+		settingDefs[def.name] = def
+	}
+}
+
+// settingError explains why a value was refused. It is a message ID so the
+// reply can be given in the channel's locale; Error renders it in English.
+type settingError struct {
+	id   string
+	args []any
+}
+
+func (e *settingError) Error() string {
+	return fmt.Sprintf(builtinMessages[defaultLocale][e.id], e.args...)
+}
+
+func intSetting(name string, lo, hi int, field func(*Settings) *int) settingDef {
+	return settingDef{
+		name: name,
+		parse: func(s *Settings, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < lo || n > hi {
+				return &settingError{msgSetNumber, []any{name, lo, hi}}
+			}
+			*field(s) = n
+			return nil
+		},
+		format: func(s Settings) string {
+			return strconv.Itoa(*field(&s))
+		},
+	}
+}
+
+// secondsSetting is a duration set in whole seconds
+func secondsSetting(name string, lo, hi int, field func(*Settings) *time.Duration) settingDef {
+	return settingDef{
+		name: name,
+		parse: func(s *Settings, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < lo || n > hi {
+				return &settingError{msgSetSeconds, []any{name, lo, hi}}
+			}
+			*field(s) = time.Duration(n) * time.Second
+			return nil
+		},
+		format: func(s Settings) string {
+			return strconv.Itoa(int(field(&s).Seconds()))
+		},
+	}
+}
+
+// timezoneSetting is an IANA time zone name such as Asia/Bangkok
+func timezoneSetting(name string, field func(*Settings) *string) settingDef {
+	return settingDef{
+		name: name,
+		parse: func(s *Settings, value string) error {
+			if _, err := time.LoadLocation(value); err != nil || value == "" || strings.EqualFold(value, "local") {
+				return &settingError{msgSetTimezone, []any{name}}
+			}
+			*field(s) = value
+			return nil
+		},
+		format: func(s Settings) string {
+			return *field(&s)
+		},
+	}
+}
+
+// styleSetting is one of the output styles
+func styleSetting(name string, field func(*Settings) *Style) settingDef {
+	return settingDef{
+		name: name,
+		parse: func(s *Settings, value string) error {
+			for _, style := range styles {
+				if strings.EqualFold(value, string(style)) {
+					*field(s) = style
+					return nil
+				}
+			}
+			names := make([]string, len(styles))
+			for i, style := range styles {
+				names[i] = string(style)
+			}
+			return &settingError{msgSetOneOf, []any{name, strings.Join(names, ", ")}}
+		},
+		format: func(s Settings) string {
+			return string(*field(&s))
+		},
+	}
+}
+
+// localeSetting is one of the locales with built-in messages
+func localeSetting(name string, field func(*Settings) *string) settingDef {
+	return settingDef{
+		name: name,
+		parse: func(s *Settings, value string) error {
+			value = strings.ToLower(value)
+			if _, ok := builtinMessages[value]; !ok {
+				return &settingError{msgSetOneOf, []any{name, strings.Join(locales(), ", ")}}
+			}
+			*field(s) = value
+			return nil
+		},
+		format: func(s Settings) string {
+			return *field(&s)
+		},
+	}
+}
+
+// SettingKeys lists the keys accepted by Set, sorted
+func SettingKeys() []string {
+	keys := make([]string, 0, len(settingDefs))
+	for k := range settingDefs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Set parses value into the setting named key
+func (s *Settings) Set(key, value string) error {
+	def, ok := settingDefs[strings.ToLower(strings.TrimSpace(key))]
+	if !ok {
+		return &settingError{msgSetUnknown, []any{key, strings.Join(SettingKeys(), ", ")}}
+	}
+	return def.parse(s, strings.TrimSpace(value))
+}
+
+// Get returns the setting named key formatted the way Set accepts it
+func (s Settings) Get(key string) string {
+	def, ok := settingDefs[strings.ToLower(strings.TrimSpace(key))]
+	if !ok {
+		return ""
+	}
+	return def.format(s)
+}
+
+// String lists every setting as key=value
+func (s Settings) String() string {
+	parts := make([]string, 0, len(settingDefs))
+	for _, k := range SettingKeys() {
+		parts = append(parts, k+"="+s.Get(k))
+	}
+	return strings.Join(parts, " ")
+}
+
+// applySettings overlays stored values on base, skipping ones that no longer parse
+func applySettings(base Settings, values map[string]string) Settings {
+	for key, value := range values {
+		if err := base.Set(key, value); err != nil {
+			fmt.Printf("Ignoring stored setting %s=%q: %v\n", key, value, err)
+		}
+	}
+	return base
+}
+
+// Settings returns the channel's current settings
+func (g *Game) Settings() Settings {
+	g.settingsMu.RLock()
+	defer g.settingsMu.RUnlock()
+
+	if g.settings == nil {
+		return DefaultSettings()
+	}
+	return *g.settings
+}
+
+// HandleSet changes a channel setting at runtime: !set <key> <value>
+func (g *Game) HandleSet(ctx context.Context, args ...string) error {
+	if len(args) < 2 {
+		g.irc().Privmsg(g.channel, g.msg(msgSetUsage, g.Settings()))
+		return nil
+	}
+
+	// the reply is sent after the settings lock is released, since sending
+	// reads the style setting
+	reply, err := g.set(ctx, strings.ToLower(args[0]), args[1])
+	g.irc().Privmsg(g.channel, reply)
+	return err
+}
+
+// set changes and saves one setting, returning the reply for the channel.
+// Invalid values are explained in the reply; only saving returns an error.
+// The setting is saved before the settings lock is taken, so shooting and
+// spawning never wait on the database.
+func (g *Game) set(ctx context.Context, key, value string) (string, error) {
+	updated := g.Settings()
+	if err := updated.Set(key, value); err != nil {
+		var invalid *settingError
+		if errors.As(err, &invalid) {
+			return g.msg(msgSetInvalid, g.msg(invalid.id, invalid.args...)), nil
+		}
+		return g.msg(msgSetInvalid, err), nil
+	}
+	stored := updated.Get(key)
+
+	if g.settingsRepository != nil {
+		if err := g.settingsRepository.SetSetting(ctx, g.network, g.channel, key, stored); err != nil {
+			return g.msg(msgSetSaveError), err
+		}
+	}
+
+	// apply just this key, keeping any other change made while saving
+	g.settingsMu.Lock()
+	current := DefaultSettings()
+	if g.settings != nil {
+		current = *g.settings
+	}
+	_ = current.Set(key, stored)
+	g.settings = &current
+	g.settingsMu.Unlock()
+
+	return g.msg(msgSetDone, key, stored), nil
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/settings/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSettings_Set(t *testing.T) {
+	s := DefaultSettings()
+
+	require.NoError(t, s.Set("shoot_cooldown", "30"))
+	assert.Equal(t, 30*time.Second, s.ShootCooldown)
+	assert.Equal(t, "30", s.Get("shoot_cooldown"))
+
+	require.NoError(t, s.Set("MAX_ATTEMPTS", " 3 "))
+	assert.Equal(t, 3, s.MaxAttemptsPerSpawn)
+
+	assert.Error(t, s.Set("max_attempts", "0"))
+	assert.Error(t, s.Set("rare_egg_appear", "101"))
+	assert.Error(t, s.Set("rare_egg_boost", "lots"))
+	assert.Error(t, s.Set("nope", "1"))
+	assert.Equal(t, 3, s.MaxAttemptsPerSpawn, "failed Set must not change the value")
+
+	require.NoError(t, s.Set("timezone", "Asia/Bangkok"))
+	assert.Equal(t, "Asia/Bangkok", s.Get("timezone"))
+	assert.Error(t, s.Set("timezone", "Mars/Olympus"))
+	assert.Error(t, s.Set("timezone", "Local"))
+}
+
+func TestWithSettings_SkipsInvalidValues(t *testing.T) {
+	g := NewGame(config.GameConfig{}, &mockIRCClientForTest{}, newMockPlayerRepoForTest(), "testnet", "test",
+		WithSettings(map[string]string{"pigeon_lifetime": "10", "max_attempts": "-1", "unknown": "1"}),
+	)
+
+	s := g.Settings()
+	assert.Equal(t, 10*time.Second, s.MinPigeonLifetime)
+	assert.Equal(t, maxAttemptsPerSpawn, s.MaxAttemptsPerSpawn)
+}
+
+func TestGame_SettingsDefaultWithoutNewGame(t *testing.T) {
+	g := &Game{}
+	assert.Equal(t, DefaultSettings(), g.Settings())
+}
+
+func TestHandleSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSettingsRepository(ctrl)
+	client := &mockIRCClientForTest{}
+	g := NewGame(config.GameConfig{}, client, newMockPlayerRepoForTest(), "testnet", "#test",
+		WithSettingsRepository(repo),
+	)
+	ctx := context.Background()
+
+	t.Run("valid value is persisted and applied", func(t *testing.T) {
+		repo.EXPECT().SetSetting(ctx, "testnet", "#test", "max_attempts", "2").Return(nil)
+
+		require.NoError(t, g.HandleSet(ctx, "max_attempts", "2"))
+		assert.Equal(t, 2, g.Settings().MaxAttemptsPerSpawn)
+		assert.Equal(t, "⚙️ max_attempts is now 2", client.messages[len(client.messages)-1])
+	})
+
+	t.Run("invalid value is rejected", func(t *testing.T) {
+		require.NoError(t, g.HandleSet(ctx, "max_attempts", "zero"))
+		assert.Equal(t, 2, g.Settings().MaxAttemptsPerSpawn)
+		assert.Contains(t, client.messages[len(client.messages)-1], "max_attempts must be a number")
+	})
+
+	t.Run("save error keeps the old value", func(t *testing.T) {
+		repo.EXPECT().SetSetting(ctx, "testnet", "#test", "shoot_cooldown", "60").Return(errors.New("db down"))
+
+		assert.Error(t, g.HandleSet(ctx, "shoot_cooldown", "60"))
+		assert.Equal(t, shootCooldown, g.Settings().ShootCooldown)
+	})
+
+	t.Run("no arguments lists settings", func(t *testing.T) {
+		require.NoError(t, g.HandleSet(ctx))
+		assert.Contains(t, client.messages[len(client.messages)-1], "max_attempts=2")
+	})
+
+	t.Run("changed settings drive the cooldown", func(t *testing.T) {
+		ok, _ := g.canShoot("alice", 1)
+		assert.True(t, ok)
+		ok, _ = g.canShoot("alice", 1)
+		assert.True(t, ok)
+		ok, wait := g.canShoot("alice", 1)
+		assert.False(t, ok)
+		assert.Equal(t, shootCooldown, wait)
+	})
+}
+
+func TestHandleSet_DoesNotBlockReadersWhileSaving(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSettingsRepository(ctrl)
+	g := NewGame(config.GameConfig{}, &mockIRCClientForTest{}, newMockPlayerRepoForTest(), "testnet", "#test",
+		WithSettingsRepository(repo),
+	)
+	ctx := context.Background()
+
+	saving := make(chan struct{})
+	release := make(chan struct{})
+	repo.EXPECT().SetSetting(ctx, "testnet", "#test", "max_attempts", "4").DoAndReturn(
+		func(context.Context, string, string, string, string) error {
+			close(saving)
+			<-release
+			return nil
+		})
+
+	done := make(chan error)
+	go func() { done <- g.HandleSet(ctx, "max_attempts", "4") }()
+	<-saving
+
+	read := make(chan Settings)
+	go func() { read <- g.Settings() }()
+	select {
+	case s := <-read:
+		assert.Equal(t, maxAttemptsPerSpawn, s.MaxAttemptsPerSpawn, "not applied until saved")
+	case <-time.After(time.Second):
+		t.Fatal("Settings blocked while the setting was being saved")
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 4, g.Settings().MaxAttemptsPerSpawn)
+}
@@ -2,6 +2,7 @@ package game_test
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -34,10 +35,7 @@ func TestHandleShoot_WithPigeon(t *testing.T) {
 				}, nil).
 				Times(1)
 
-			playerRepository.EXPECT().
-				UpsertPlayer(gomock.Any(), gomock.Any()).
-				Return(nil).
-				AnyTimes()
+			allowScoring(playerRepository)
 
 			ircClient := gameMocks.NewMockIRCClient(ctrl)
 			ircClient.EXPECT().Privmsg("channel", gomock.Any()).AnyTimes()
@@ -95,7 +93,7 @@ func TestActOnPlayer_WithExistingPigeon(t *testing.T) {
 	assert.NotNil(t, gameInstance)
 }
 
-func TestHandleShoot_FindPlayerError(t *testing.T) {
+func TestHandleShoot_StoreError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -103,16 +101,35 @@ func TestHandleShoot_FindPlayerError(t *testing.T) {
 	playerRepository.EggsByKey = make(map[string]int)
 	playerRepository.RareEggsByKey = make(map[string]int)
 
-	// Return empty players so FindPlayer needs to create one
+	// Return empty players so the shooter is not cached
 	playerRepository.EXPECT().
 		GetAllPlayers(gomock.Any(), "network", "channel").
 		Return([]*player.Player{}, nil).
 		Times(1)
 
-	// UpsertPlayer fails, causing FindPlayer to fail
+	// the first try at storing the shot fails, hit or miss
+	var writes atomic.Int32
 	playerRepository.EXPECT().
-		UpsertPlayer(gomock.Any(), gomock.Any()).
-		Return(assert.AnError).
+		AwardKill(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, name string, points int) (*player.Player, error) {
+			if writes.Add(1) == 1 {
+				return nil, assert.AnError
+			}
+			return &player.Player{Name: name, Points: points, Count: 1}, nil
+		}).
+		AnyTimes()
+	playerRepository.EXPECT().
+		RecordMiss(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, name string) (*player.Player, error) {
+			if writes.Add(1) == 1 {
+				return nil, assert.AnError
+			}
+			return &player.Player{Name: name}, nil
+		}).
+		AnyTimes()
+	playerRepository.EXPECT().
+		CollectEggs(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&player.Player{}, nil).
 		AnyTimes()
 
 	ircClient := gameMocks.NewMockIRCClient(ctrl)
@@ -132,7 +149,11 @@ func TestHandleShoot_FindPlayerError(t *testing.T) {
 	go gameInstance.Start(ctx)
 	time.Sleep(1500 * time.Millisecond)
 
-	err := commandController.HandleCommand(ctx, line)
-	// Should return error from FindPlayer
-	assert.NotNil(t, err)
+	// the shot is kept and written again, so it still counts
+	assert.NoError(t, commandController.HandleCommand(ctx, line))
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	assert.NoError(t, gameInstance.Shutdown(shutdownCtx))
+	assert.Equal(t, int32(2), writes.Load())
 }
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	shot2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shotsForTest keeps recorded shots in memory
+type shotsForTest struct {
+	events []*shot2.ShotEvent
+}
+
+func (s *shotsForTest) Record(e *shot2.ShotEvent) {
+	s.events = append(s.events, e)
+}
+
+func TestHandleShoot_RecordsShots(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	shots := &shotsForTest{}
+
+	// shoot at nothing, spawn a cartel member (85% hit), then miss with 85 and hit with 84
+	g := newScriptedGame(t, client, clock, 0, 0, 0, 85, 84)
+	WithShotRecorder(shots)(g)
+
+	ctx := context_manager.WithNick(context.Background(), "Shooter")
+	require.NoError(t, g.HandleShoot(ctx))
+	g.ActOnPlayer(context.Background())
+	require.NoError(t, g.HandleShoot(ctx))
+	clock.Advance(time.Second)
+	require.NoError(t, g.HandleShoot(ctx))
+
+	require.Len(t, shots.events, 3)
+
+	nothing := shots.events[0]
+	assert.Equal(t, "", nothing.PigeonType)
+	assert.False(t, nothing.Hit)
+	assert.Equal(t, int64(0), nothing.SpawnID)
+
+	miss := shots.events[1]
+	assert.Equal(t, "cartel member", miss.PigeonType)
+	assert.False(t, miss.Hit)
+	assert.Equal(t, 0, miss.Points)
+	assert.Equal(t, int64(1), miss.SpawnID)
+
+	hit := shots.events[2]
+	assert.Equal(t, shot2.ShotEvent{
+		Network:    "testnet",
+		Channel:    "test",
+		Name:       "shooter",
+		SpawnID:    1,
+		PigeonType: "cartel member",
+		Hit:        true,
+		Points:     10,
+		RareEgg:    shot2.RareEggNone,
+		ShotAt:     clock.Now(),
+	}, *hit)
+}
+
+func TestHandleShoot_CountsShotsAndStreaks(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+
+	// spawn a cartel member (85% hit): hit, then miss once it is back
+	g := newScriptedGame(t, client, clock, 0, 0, 0, 10, 0, 0, 0, 90)
+	ctx := context_manager.WithNick(context.Background(), "Shooter")
+
+	g.ActOnPlayer(context.Background())
+	require.NoError(t, g.HandleShoot(ctx))
+	g.ActOnPlayer(context.Background())
+	clock.Advance(time.Second)
+	require.NoError(t, g.HandleShoot(ctx))
+
+	p, err := g.FindPlayer(ctx, "shooter")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Shots)
+	assert.Equal(t, 1, p.Misses)
+	assert.Equal(t, 0, p.Streak)
+	assert.Equal(t, 1, p.BestStreak)
+}
+
+func TestHandleShoot_WithoutRecorder(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := newScriptedGame(t, client, clock)
+
+	// no recorder configured: shooting still works
+	require.NoError(t, g.HandleShoot(context_manager.WithNick(context.Background(), "shooter")))
+	assert.Len(t, client.messages, 1)
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Style is how a channel's messages are rendered, set with !set style
+type Style string
+
+const (
+	StyleFull    Style = "full"    // colours and emoji
+	StyleNoColor Style = "nocolor" // emoji without colour or bold codes, for +c channels
+	StyleASCII   Style = "ascii"   // plain ASCII text, for clients that render emoji badly
+)
+
+// styles lists the accepted styles in the order !set shows them
+var styles = []Style{StyleFull, StyleNoColor, StyleASCII}
+
+// Render rewrites text, written in the full style, into s
+func (s Style) Render(text string) string {
+	switch s {
+	case StyleNoColor:
+		return stripFormatting(text)
+	case StyleASCII:
+		return toASCII(stripFormatting(text))
+	}
+	return text
+}
+
+// stripFormatting removes IRC colour and style codes
+func stripFormatting(text string) string {
+	if !strings.ContainsAny(text, ircBold+ircColor+ircReset+ircItalic+ircUnderline+ircReverse) {
+		return text
+	}
+
+	var b strings.Builder
+	for len(text) > 0 {
+		n := nextAtom(text)
+		switch text[:1] {
+		case ircBold, ircColor, ircReset, ircItalic, ircUnderline, ircReverse:
+		default:
+			b.WriteString(text[:n])
+		}
+		text = text[n:]
+	}
+	return b.String()
+}
+
+// asciiFor spells out the symbols whose meaning would be lost by dropping them
+var asciiFor = strings.NewReplacer(
+	"🥇", "1st",
+	"🥈", "2nd",
+	"🥉", "3rd",
+	"•", "-",
+	"—", "-",
+	"–", "-",
+	"…", "...",
+	"’", "'",
+	"“", `"`,
+	"”", `"`,
+	"♾️", "infinity",
+	"ß", "ss",
+	"æ", "ae",
+	"Æ", "AE",
+	"œ", "oe",
+	"Œ", "OE",
+	"ø", "o",
+	"Ø", "O",
+	"ł", "l",
+	"Ł", "L",
+	"đ", "d",
+	"Đ", "D",
+)
+
+// toASCII spells out medals and punctuation, strips accents from Latin
+// letters, drops everything else outside ASCII, such as emoji and other
+// scripts, and tidies the spaces left behind
+func toASCII(text string) string {
+	// decomposing splits é into e and a combining accent, which is dropped
+	text = norm.NFD.String(asciiFor.Replace(text))
+
+	var b strings.Builder
+	for _, r := range text {
+		if r <= unicode.MaxASCII {
+			b.WriteRune(r)
+		}
+	}
+	return asciiSpacing.Replace(strings.Join(strings.Fields(b.String()), " "))
+}
+
+// asciiSpacing closes the gaps a dropped emoji leaves before a comma or bracket
+var asciiSpacing = strings.NewReplacer(" ,", ",", " )", ")", "( ", "(")
+
+// styledClient renders everything sent to a channel in its current style
+type styledClient struct {
+	client IRCClient
+	style  func() Style
+}
+
+func (c styledClient) Privmsg(target, message string) {
+	c.client.Privmsg(target, c.style().Render(message))
+}
+
+func (c styledClient) Notice(target, message string) {
+	c.client.Notice(target, c.style().Render(message))
+}
+
+func (c styledClient) Raw(message string) {
+	c.client.Raw(message)
+}
+
+// irc returns the game's client, rendering messages in the channel's style
+func (g *Game) irc() IRCClient {
+	return styledClient{client: g.ircClient, style: g.style}
+}
+
+// Styled returns the game's client, rendering messages in the channel's
+// style, for replies sent on the game's behalf by the bot and commands
+func (g *Game) Styled() IRCClient {
+	return g.irc()
+}
+
+// style returns the channel's output style
+func (g *Game) style() Style {
+	return g.Settings().Style
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/MyelinBots/pigeonbot-go/internal/services/player"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyle_Render(t *testing.T) {
+	text := medal(0) + " alice :::::: " + c("120 points", 7) + " | " + ircBold + "Level: Legendary Phoenix 🐉🔥" + ircReset
+
+	assert.Equal(t, text, StyleFull.Render(text))
+	assert.Equal(t, "🥇 alice :::::: 120 points | Level: Legendary Phoenix 🐉🔥", StyleNoColor.Render(text))
+	assert.Equal(t, "1st alice :::::: 120 points | Level: Legendary Phoenix", StyleASCII.Render(text))
+}
+
+func TestStyle_ASCII(t *testing.T) {
+	for in, want := range map[string]string{
+		"❗⚠️ A white pigeon has landed on your car 🚗 - - 🐦":     "A white pigeon has landed on your car - -",
+		"🥚 bob has 3 egg(s) total — including 1 rare egg(s) 🌟🥚": "bob has 3 egg(s) total - including 1 rare egg(s)",
+		"Eternal Wing 🕊️♾️":                                     "Eternal Wing infinity",
+		"(Rare: 2 🌟)":                                           "(Rare: 2)",
+		medal(3) + " somchai":                                   "- somchai",
+		"\x01PING 123\x01": "\x01PING 123\x01",
+	} {
+		assert.Equal(t, want, StyleASCII.Render(in), in)
+	}
+}
+
+func TestStyle_ASCIIOnly(t *testing.T) {
+	for in, want := range map[string]string{
+		"🕊️ ~ กุกกู ~ นกพิราบ boss บินหนี ~ 🕊️": "~ ~ boss ~",
+		"José shot Zoë's pigeon in Łódź":       "Jose shot Zoe's pigeon in Lodz",
+		"Straße café, naïve Ærø":               "Strasse cafe, naive AEro",
+		"e\u0301clair ñandú":                   "eclair nandu",
+	} {
+		got := StyleASCII.Render(in)
+		assert.Equal(t, want, got, in)
+		for _, r := range got {
+			assert.LessOrEqual(t, r, rune(0x7F), "%q in %q", r, got)
+		}
+	}
+}
+
+func TestGame_Styled(t *testing.T) {
+	client := &mockIRCClientForTest{}
+	g := NewGame(config.GameConfig{}, client, newMockPlayerRepoForTest(), "testnet", "#pigeons",
+		WithSettings(map[string]string{"style": "ascii"}),
+	)
+
+	g.Styled().Privmsg("#pigeons", "🕊️ Flying over to #café")
+	assert.Equal(t, []string{"Flying over to #cafe"}, client.messages)
+}
+
+func TestHandleSet_Style(t *testing.T) {
+	client := &mockIRCClientForTest{}
+	g := &Game{ircClient: client, channel: "#pigeons"}
+	g.players.players = []*player.Player{player.NewPlayer("alice", 10, 1)}
+
+	require.NoError(t, g.HandleSet(context.Background(), "style", "ASCII"))
+	assert.Equal(t, StyleASCII, g.Settings().Style)
+
+	require.NoError(t, g.HandleSet(context.Background(), "style", "sepia"))
+	require.NoError(t, g.HandleLevel(context.Background()))
+	assert.Equal(t, []string{
+		"style is now ascii",
+		"style must be one of full, nocolor, ascii",
+		"alice: Beginner,",
+	}, client.messages)
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// window is a leaderboard time range: from up to (not including) to
+type window struct {
+	from time.Time
+	to   time.Time
+	// label is the message ID naming the window in headers, with its args
+	label string
+	args  []any
+}
+
+// parseWindow reads a leaderboard window: today, week, month or a date
+// range like 2024-01-01..2024-01-31 (both days included). Calendar
+// boundaries are taken in now's location; weeks start on Monday.
+func parseWindow(arg string, now time.Time) (window, bool) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.ToLower(arg) {
+	case "today", "day", "daily":
+		return window{from: midnight, to: now, label: msgWindowToday}, true
+	case "week", "weekly":
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return window{from: midnight.AddDate(0, 0, -daysSinceMonday), to: now, label: msgWindowWeek}, true
+	case "month", "monthly":
+		first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return window{from: first, to: now, label: msgWindowMonth}, true
+	}
+
+	start, end, ok := strings.Cut(arg, "..")
+	if !ok {
+		return window{}, false
+	}
+	from, err := time.ParseInLocation(dateLayout, start, now.Location())
+	if err != nil {
+		return window{}, false
+	}
+	last, err := time.ParseInLocation(dateLayout, end, now.Location())
+	if err != nil || last.Before(from) {
+		return window{}, false
+	}
+	return window{from: from, to: last.AddDate(0, 0, 1), label: msgWindowRange, args: []any{start, end}}, true
+}
+
+// seasonWindow is the running season so far; false if seasons are off
+func (g *Game) seasonWindow(ctx context.Context) (window, bool) {
+	if !g.seasonsEnabled() {
+		return window{}, false
+	}
+	g.checkSeason(ctx)
+
+	g.seasonMu.Lock()
+	s := g.season
+	g.seasonMu.Unlock()
+	if s == nil {
+		return window{}, false
+	}
+	return window{from: s.StartedAt, to: g.now(), label: msgWindowSeason, args: []any{s.Number}}, true
+}
+
+// windowLabel names w in the channel's locale, e.g. "this week"
+func (g *Game) windowLabel(w window) string {
+	return g.msg(w.label, w.args...)
+}
+
+// location is the channel's time zone for calendar windows: the timezone
+// setting, else the schedule's, else UTC
+func (g *Game) location() *time.Location {
+	if tz := g.Settings().Timezone; tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	if g.scheduler != nil && g.scheduler.loc != nil {
+		return g.scheduler.loc
+	}
+	return time.UTC
+}
+
+// topQuery is what a !top command asked for; a nil window means all time
+type topQuery struct {
+	n      int
+	global bool
+	window *window
+}
+
+// parseTop reads [n] [window] [--global]; n is only accepted when allowN is set
+func (g *Game) parseTop(ctx context.Context, args []string, n int, allowN bool) (topQuery, bool) {
+	args, global := globalFlag(args)
+	q := topQuery{n: n, global: global}
+
+	for _, arg := range args {
+		if v, err := strconv.Atoi(arg); err == nil && allowN {
+			if v < 1 || v > 10 {
+				return q, false
+			}
+			q.n = v
+			continue
+		}
+		w, ok := parseWindow(arg, g.now().In(g.location()))
+		if strings.EqualFold(arg, "season") {
+			w, ok = g.seasonWindow(ctx)
+		}
+		if !ok || q.window != nil {
+			return q, false
+		}
+		q.window = &w
+	}
+	return q, true
+}
+
+// handleTop runs a parsed !top command
+func (g *Game) handleTop(ctx context.Context, q topQuery) error {
+	if q.window == nil {
+		return g.handleTopN(ctx, q.n, q.global)
+	}
+	return g.handleTopBetween(ctx, q.n, q.global, *q.window)
+}
+
+// handleTopBetween lists the players who scored most within w
+func (g *Game) handleTopBetween(ctx context.Context, n int, global bool, w window) error {
+	if g.shotRepository == nil {
+		g.listingClient().Privmsg(g.channel, g.msg(msgTopNoHistory))
+		return nil
+	}
+
+	header := g.msg(msgTopHeaderWindow, n, g.windowLabel(w))
+	channel := g.scope()
+	if global {
+		header = g.msg(msgTopHeaderWindowNetwork, n, g.network, g.windowLabel(w))
+		channel = ""
+	}
+
+	g.listingClient().Privmsg(g.channel, fmt.Sprintf("%s%s%s", ircBold, c(header, 8), ircReset))
+
+	scores, err := g.shotRepository.TopByPointsBetween(ctx, g.network, channel, w.from, w.to, n)
+	if err != nil {
+		g.listingClient().Privmsg(g.channel, g.msg(msgTopError))
+		return err
+	}
+	if len(scores) == 0 {
+		g.listingClient().Privmsg(g.channel, g.msg(msgTopEmpty))
+		return nil
+	}
+
+	for i, s := range scores {
+		g.listingClient().Privmsg(
+			g.channel,
+			fmt.Sprintf(
+				"%s %s :::::: %s | %s | %s (%s)",
+				medal(i),
+				s.Name,
+				c(g.msg(msgLabelPoints, fmtNum(s.Points)), 7),
+				c(g.msg(msgLabelPigeons, fmtNum(s.Hits)), 4),
+				c(g.msg(msgLabelEggs, fmtNum(s.Eggs)), 8),
+				c(g.msg(msgLabelRare, fmtNum(s.RareEggs)), 8),
+			),
+		)
+	}
+	return nil
+}
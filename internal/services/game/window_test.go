@@ -0,0 +1,151 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	season2 "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/season"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+	shotMocks "github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseWindow(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	require.NoError(t, err)
+	// a Thursday
+	now := time.Date(2024, 5, 16, 1, 30, 0, 0, bangkok)
+
+	tests := []struct {
+		arg   string
+		from  time.Time
+		to    time.Time
+		label string
+	}{
+		{"today", time.Date(2024, 5, 16, 0, 0, 0, 0, bangkok), now, "today"},
+		{"WEEK", time.Date(2024, 5, 13, 0, 0, 0, 0, bangkok), now, "this week"},
+		{"month", time.Date(2024, 5, 1, 0, 0, 0, 0, bangkok), now, "this month"},
+		{"2024-01-01..2024-01-31", time.Date(2024, 1, 1, 0, 0, 0, 0, bangkok), time.Date(2024, 2, 1, 0, 0, 0, 0, bangkok), "2024-01-01 to 2024-01-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			w, ok := parseWindow(tt.arg, now)
+			require.True(t, ok)
+			assert.True(t, tt.from.Equal(w.from), "from %s", w.from)
+			assert.True(t, tt.to.Equal(w.to), "to %s", w.to)
+			assert.Equal(t, tt.label, (&Game{}).windowLabel(w))
+		})
+	}
+
+	t.Run("a Sunday belongs to the week that started on Monday", func(t *testing.T) {
+		sunday := time.Date(2024, 5, 19, 23, 0, 0, 0, time.UTC)
+		w, ok := parseWindow("week", sunday)
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), w.from)
+	})
+
+	for _, bad := range []string{"year", "2024-01-31..2024-01-01", "2024-01-01..", "yesterday..today"} {
+		_, ok := parseWindow(bad, now)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestGame_Location(t *testing.T) {
+	assert.Equal(t, time.UTC, (&Game{}).location())
+
+	g := NewGame(config.GameConfig{Schedule: config.ScheduleConfig{Timezone: "Europe/London"}},
+		&mockIRCClientForTest{}, newMockPlayerRepoForTest(), "testnet", "#test")
+	assert.Equal(t, "Europe/London", g.location().String())
+
+	WithSettings(map[string]string{"timezone": "Asia/Bangkok"})(g)
+	assert.Equal(t, "Asia/Bangkok", g.location().String())
+}
+
+func TestParseTop(t *testing.T) {
+	g := &Game{clock: newFakeClock(time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC))}
+
+	q, ok := g.parseTop(context.Background(), []string{"week", "--global"}, 5, false)
+	require.True(t, ok)
+	assert.Equal(t, 5, q.n)
+	assert.True(t, q.global)
+	require.NotNil(t, q.window)
+	assert.Equal(t, "this week", g.windowLabel(*q.window))
+
+	q, ok = g.parseTop(context.Background(), []string{"3"}, 5, true)
+	require.True(t, ok)
+	assert.Equal(t, 3, q.n)
+	assert.Nil(t, q.window)
+
+	_, ok = g.parseTop(context.Background(), []string{"3"}, 5, false)
+	assert.False(t, ok, "!top5 takes no count")
+	_, ok = g.parseTop(context.Background(), []string{"11"}, 5, true)
+	assert.False(t, ok)
+	_, ok = g.parseTop(context.Background(), []string{"week", "month"}, 5, true)
+	assert.False(t, ok)
+}
+
+func TestParseTop_Season(t *testing.T) {
+	g, _, clock, _, _ := newSeasonGame(t, 0)
+	started := clock.Now().Add(-3 * 24 * time.Hour)
+	g.season = &season2.Season{ID: "s2", Number: 2, StartedAt: started, EndsAt: clock.Now().Add(time.Hour)}
+
+	q, ok := g.parseTop(context.Background(), []string{"season"}, 5, true)
+	require.True(t, ok)
+	require.NotNil(t, q.window)
+	assert.Equal(t, started, q.window.from)
+	assert.Equal(t, clock.Now(), q.window.to)
+	assert.Equal(t, "in season 2", g.windowLabel(*q.window))
+
+	off := &Game{clock: clock}
+	_, ok = off.parseTop(context.Background(), []string{"season"}, 5, true)
+	assert.False(t, ok, "no season window when seasons are off")
+}
+
+func TestHandleTop5_Window(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := shotMocks.NewMockShotEventRepository(ctrl)
+
+	clock := newFakeClock(time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC))
+	client := &mockIRCClientForTest{}
+	g := NewGame(config.GameConfig{}, client, newMockPlayerRepoForTest(), "testnet", "#test",
+		WithClock(clock), WithShotRepository(repo))
+
+	monday := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)
+	repo.EXPECT().TopByPointsBetween(gomock.Any(), "testnet", "#test", monday, clock.Now(), 5).
+		Return([]*shot.Score{{Name: "newbie", Points: 40, Hits: 3, Shots: 5}}, nil)
+
+	require.NoError(t, g.HandleTop5(context.Background(), "week"))
+	require.Len(t, client.messages, 2)
+	assert.Contains(t, client.messages[0], "Top 5 Pigeon Hunters this week")
+	assert.Contains(t, client.messages[1], "newbie")
+	assert.Contains(t, client.messages[1], "40 points")
+	assert.Contains(t, client.messages[1], "3 pigeons")
+
+	t.Run("global", func(t *testing.T) {
+		client.messages = nil
+		first := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		repo.EXPECT().TopByPointsBetween(gomock.Any(), "testnet", "", first, clock.Now(), 10).Return(nil, nil)
+
+		require.NoError(t, g.HandleTop10(context.Background(), "--global", "month"))
+		assert.Contains(t, client.messages[0], "Top 10 Pigeon Hunters on testnet this month")
+		assert.Contains(t, client.messages[1], "Nobody has scored yet")
+	})
+
+	t.Run("bad window", func(t *testing.T) {
+		client.messages = nil
+		require.NoError(t, g.HandleTop10(context.Background(), "fortnight"))
+		assert.Equal(t, []string{"🏆 usage: !top10 [today|week|month|season|YYYY-MM-DD..YYYY-MM-DD] [--global]"}, client.messages)
+	})
+}
+
+func TestHandleTop_WindowWithoutHistory(t *testing.T) {
+	client := &mockIRCClientForTest{}
+	g := &Game{ircClient: client, channel: "#test"}
+
+	require.NoError(t, g.HandleTop(context.Background(), "today"))
+	assert.Equal(t, []string{"🏆 No shot history is kept here"}, client.messages)
+}
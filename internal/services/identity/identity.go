@@ -0,0 +1,88 @@
+package identity
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+)
+
+// loggedOut is the account IRCv3 sends for a user who is not logged in
+const loggedOut = "*"
+
+// Tracker follows who the users on one network are as they change nicks and
+// log in or out of services. Accounts come from extended-join, account-notify
+// and account-tag; without them a user keeps the nick they were first seen
+// with, so a player who changes nick keeps their score.
+type Tracker struct {
+	mu    sync.Mutex
+	users map[string]context_manager.Identity // nick -> identity
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{users: make(map[string]context_manager.Identity)}
+}
+
+func key(nick string) string {
+	return strings.ToLower(nick)
+}
+
+// Lookup returns nick's identity; a nick never seen before is itself
+func (t *Tracker) Lookup(nick string) context_manager.Identity {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if id, ok := t.users[key(nick)]; ok {
+		return id
+	}
+	return context_manager.Identity{Name: key(nick)}
+}
+
+// SetAccount records the account nick is logged in to; "" or "*" means
+// logged out. It reports whether nick's account changed.
+func (t *Tracker) SetAccount(nick, account string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := context_manager.Identity{Name: key(nick)}
+	if account != "" && account != loggedOut {
+		id = context_manager.Identity{Name: key(account), Account: true}
+	}
+
+	old, ok := t.users[key(nick)]
+	if ok && !old.Account && !id.Account {
+		// still logged out: keep following the nick they came with
+		return false
+	}
+	t.users[key(nick)] = id
+	return !ok || old != id
+}
+
+// Rename moves oldNick's identity to newNick
+func (t *Tracker) Rename(oldNick, newNick string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id, ok := t.users[key(oldNick)]
+	if !ok {
+		id = context_manager.Identity{Name: key(oldNick)}
+	}
+	delete(t.users, key(oldNick))
+	t.users[key(newNick)] = id
+}
+
+// Quit forgets nick
+func (t *Tracker) Quit(nick string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.users, key(nick))
+}
+
+// Reset forgets everyone, e.g. after a disconnect
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clear(t.users)
+}
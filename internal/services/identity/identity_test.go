@@ -0,0 +1,40 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/context_manager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_FollowsNickChanges(t *testing.T) {
+	tr := NewTracker()
+	assert.Equal(t, context_manager.Identity{Name: "bob"}, tr.Lookup("Bob"))
+
+	tr.Rename("Bob", "bob_away")
+	tr.Rename("bob_away", "bob_lunch")
+	assert.Equal(t, context_manager.Identity{Name: "bob"}, tr.Lookup("bob_lunch"))
+	assert.Equal(t, context_manager.Identity{Name: "bob_away"}, tr.Lookup("bob_away"))
+
+	tr.Quit("bob_lunch")
+	assert.Equal(t, context_manager.Identity{Name: "bob_lunch"}, tr.Lookup("bob_lunch"))
+}
+
+func TestTracker_Accounts(t *testing.T) {
+	tr := NewTracker()
+
+	assert.True(t, tr.SetAccount("bob", "Robert"))
+	assert.False(t, tr.SetAccount("bob", "robert"))
+	tr.Rename("bob", "bob_away")
+	assert.Equal(t, context_manager.Identity{Name: "robert", Account: true}, tr.Lookup("bob_away"))
+
+	// logging out falls back to the nick, and stays there across renames
+	assert.True(t, tr.SetAccount("bob_away", "*"))
+	assert.Equal(t, context_manager.Identity{Name: "bob_away"}, tr.Lookup("bob_away"))
+	tr.Rename("bob_away", "bob")
+	assert.False(t, tr.SetAccount("bob", ""))
+	assert.Equal(t, context_manager.Identity{Name: "bob_away"}, tr.Lookup("bob"))
+
+	tr.Reset()
+	assert.Equal(t, context_manager.Identity{Name: "bob"}, tr.Lookup("bob"))
+}
@@ -0,0 +1,305 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBurst    = 5
+	DefaultInterval = time.Second
+	maxWaiting      = 500 // lines waiting to be sent before new ones are dropped
+)
+
+// Client is the IRC connection the queue sends through
+type Client interface {
+	Privmsg(target, message string)
+	Notice(target, message string)
+	Raw(message string)
+}
+
+// Priority decides which waiting line is sent first
+type Priority int
+
+const (
+	Low    Priority = iota // listings such as !score and !top
+	Normal                 // game announcements and replies
+	High                   // raw protocol commands
+)
+
+type kind int
+
+const (
+	privmsg kind = iota
+	notice
+	raw
+)
+
+// line is one message waiting to be sent
+type line struct {
+	kind    kind
+	target  string
+	message string
+}
+
+// Clock abstracts time so the rate limit can be tested without real sleeps
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Stats counts what went through a queue
+type Stats struct {
+	Sent      int64 // lines handed to the client
+	Coalesced int64 // listing lines dropped because the same listing was already waiting
+	Dropped   int64 // lines dropped because the queue was full or closed
+	Waiting   int   // lines waiting right now
+}
+
+// Queue rate-limits what the bot says on one network with a token bucket, so
+// a burst of output cannot get the bot killed for flooding. Higher priority
+// lines overtake lower ones. A listing already waiting is not queued twice,
+// but announcements always are, since two identical ones are two events.
+type Queue struct {
+	client Client
+	clock  Clock
+
+	mu     sync.Mutex
+	lanes  [High + 1][]line
+	bucket bucket
+	stats  Stats
+	closed bool
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+// New starts a queue that sends up to burst lines at once, then one line
+// every interval
+func New(client Client, burst int, interval time.Duration) *Queue {
+	return newQueue(client, burst, interval, realClock{})
+}
+
+func newQueue(client Client, burst int, interval time.Duration, clock Clock) *Queue {
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	q := &Queue{
+		client: client,
+		clock:  clock,
+		bucket: newBucket(burst, interval),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Privmsg queues a PRIVMSG at Normal priority
+func (q *Queue) Privmsg(target, message string) {
+	q.push(Normal, line{kind: privmsg, target: target, message: message})
+}
+
+// Notice queues a NOTICE at Normal priority
+func (q *Queue) Notice(target, message string) {
+	q.push(Normal, line{kind: notice, target: target, message: message})
+}
+
+// Raw queues a raw line at High priority
+func (q *Queue) Raw(message string) {
+	q.push(High, line{kind: raw, message: message})
+}
+
+// Low returns a client that queues PRIVMSG and NOTICE behind everything else
+func (q *Queue) Low() Client {
+	return lowClient{q}
+}
+
+type lowClient struct{ q *Queue }
+
+func (c lowClient) Privmsg(target, message string) {
+	c.q.push(Low, line{kind: privmsg, target: target, message: message})
+}
+
+func (c lowClient) Notice(target, message string) {
+	c.q.push(Low, line{kind: notice, target: target, message: message})
+}
+
+func (c lowClient) Raw(message string) {
+	c.q.Raw(message)
+}
+
+// Stats returns the queue's counters
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.stats
+	for _, lane := range q.lanes {
+		stats.Waiting += len(lane)
+	}
+	return stats
+}
+
+func (q *Queue) push(p Priority, l line) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		q.stats.Dropped++
+		return
+	}
+	if p == Low {
+		for _, w := range q.lanes[Low] {
+			if w == l {
+				q.stats.Coalesced++
+				return
+			}
+		}
+	}
+	waiting := 0
+	for _, lane := range q.lanes {
+		waiting += len(lane)
+	}
+	if waiting >= maxWaiting {
+		q.stats.Dropped++
+		fmt.Printf("Outbound queue full, dropping line to %s\n", l.target)
+		return
+	}
+
+	q.lanes[p] = append(q.lanes[p], l)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the oldest line of the highest priority, if any
+func (q *Queue) pop() (line, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for p := High; p >= Low; p-- {
+		if len(q.lanes[p]) > 0 {
+			l := q.lanes[p][0]
+			q.lanes[p] = q.lanes[p][1:]
+			return l, true
+		}
+	}
+	return line{}, false
+}
+
+// empty reports whether nothing is waiting, and whether the queue is closed
+func (q *Queue) empty() (empty, closed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, lane := range q.lanes {
+		if len(lane) > 0 {
+			return false, q.closed
+		}
+	}
+	return true, q.closed
+}
+
+// Close sends whatever is waiting and stops the queue. Lines queued
+// afterwards are dropped. It gives up when ctx is done.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.wake)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) run() {
+	defer close(q.done)
+
+	for {
+		empty, closed := q.empty()
+		if empty {
+			if closed {
+				return
+			}
+			<-q.wake
+			continue
+		}
+
+		// wait for a token before choosing the line, so a line queued
+		// meanwhile can still overtake
+		q.mu.Lock()
+		wait := q.bucket.take(q.clock.Now())
+		q.mu.Unlock()
+		if wait > 0 {
+			<-q.clock.After(wait)
+		}
+
+		l, ok := q.pop()
+		if !ok {
+			continue
+		}
+		q.send(l)
+	}
+}
+
+func (q *Queue) send(l line) {
+	switch l.kind {
+	case privmsg:
+		q.client.Privmsg(l.target, l.message)
+	case notice:
+		q.client.Notice(l.target, l.message)
+	case raw:
+		q.client.Raw(l.message)
+	}
+
+	q.mu.Lock()
+	q.stats.Sent++
+	q.mu.Unlock()
+}
+
+// bucket is a token bucket holding up to burst tokens, refilled with one
+// token every interval
+type bucket struct {
+	burst    int
+	interval time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(burst int, interval time.Duration) bucket {
+	return bucket{burst: burst, interval: interval, tokens: float64(burst)}
+}
+
+// take spends a token and returns how long to wait until it is really
+// there; the bucket may go into debt so that waiting callers queue up
+func (b *bucket) take(now time.Time) time.Duration {
+	if !b.last.IsZero() {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		b.tokens = min(b.tokens, float64(b.burst))
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(b.interval))
+}
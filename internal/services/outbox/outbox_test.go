@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/services/game/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBucket(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newBucket(2, time.Second)
+
+	// the burst goes out at once, then one line a second
+	assert.Equal(t, time.Duration(0), b.take(start))
+	assert.Equal(t, time.Duration(0), b.take(start))
+	assert.Equal(t, time.Second, b.take(start))
+	assert.Equal(t, 2*time.Second, b.take(start))
+
+	// after a long quiet spell the bucket is full again, but no fuller
+	later := start.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), b.take(later))
+	assert.Equal(t, time.Duration(0), b.take(later))
+	assert.Equal(t, time.Second, b.take(later))
+}
+
+// idleQueue is a queue without its sending goroutine, to inspect what waits
+func idleQueue() *Queue {
+	return &Queue{wake: make(chan struct{}, 1), done: make(chan struct{})}
+}
+
+func TestQueue_Priorities(t *testing.T) {
+	q := idleQueue()
+	q.Low().Privmsg("#pigeons", "🏆 Top 5")
+	q.Privmsg("#pigeons", "A pigeon has landed on your car")
+	q.Raw("PONG :server")
+	q.Low().Notice("alice", "alice: 10")
+
+	var order []string
+	for {
+		l, ok := q.pop()
+		if !ok {
+			break
+		}
+		order = append(order, l.message)
+	}
+	assert.Equal(t, []string{"PONG :server", "A pigeon has landed on your car", "🏆 Top 5", "alice: 10"}, order)
+}
+
+func TestQueue_CoalescesListingsOnly(t *testing.T) {
+	q := idleQueue()
+	q.Low().Privmsg("#pigeons", "🏆 Top 5")
+	q.Low().Privmsg("#pigeons", "🏆 Top 5")
+	q.Low().Privmsg("#other", "🏆 Top 5")
+	q.Low().Notice("#pigeons", "🏆 Top 5")
+
+	// two identical announcements are two spawns, so both are sent
+	q.Privmsg("#pigeons", "A pigeon has landed on your car")
+	q.Privmsg("#pigeons", "A pigeon has landed on your car")
+
+	assert.Equal(t, Stats{Coalesced: 1, Waiting: 5}, q.Stats())
+}
+
+func TestQueue_RateLimit(t *testing.T) {
+	clock := newFakeClock()
+	client := &recordingClient{sent: make(chan string, 10)}
+	q := newQueue(client, 2, time.Second, clock)
+
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		q.Privmsg("#pigeons", msg)
+	}
+
+	// the burst goes out at once, then one line a second
+	assert.Equal(t, "one", <-client.sent)
+	assert.Equal(t, "two", <-client.sent)
+	assert.Equal(t, time.Second, <-clock.waits)
+	assert.Empty(t, client.sent)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, "three", <-client.sent)
+	assert.Equal(t, time.Second, <-clock.waits)
+	clock.Advance(time.Second)
+	assert.Equal(t, "four", <-client.sent)
+
+	require.NoError(t, q.Close(context.Background()))
+	assert.Equal(t, Stats{Sent: 4}, q.Stats())
+}
+
+func TestQueue_SendsThroughClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mocks.NewMockIRCClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Raw("PING :one"),
+		client.EXPECT().Privmsg("#pigeons", "two"),
+		client.EXPECT().Notice("alice", "three"),
+	)
+
+	q := New(client, 1, 5*time.Millisecond)
+	q.Raw("PING :one")
+	q.Privmsg("#pigeons", "two")
+	q.Notice("alice", "three")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, q.Close(ctx))
+
+	// closed queues drop what they are given
+	q.Privmsg("#pigeons", "four")
+	assert.Equal(t, Stats{Sent: 3, Dropped: 1}, q.Stats())
+}
+
+// recordingClient passes on the message of every PRIVMSG it is given
+type recordingClient struct{ sent chan string }
+
+func (c *recordingClient) Privmsg(_, message string) { c.sent <- message }
+func (c *recordingClient) Notice(_, message string)  { c.sent <- message }
+func (c *recordingClient) Raw(message string)        { c.sent <- message }
+
+// fakeClock fires After channels only when the test advances it
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	waits   chan time.Duration // every duration passed to After
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), waits: make(chan time.Duration, 100)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.waits <- d
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- c.now
+			continue
+		}
+		pending = append(pending, w)
+	}
+	c.waiters = pending
+}
@@ -0,0 +1,276 @@
+package permissions
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+)
+
+// Level is what a user is allowed to do; higher levels include the lower ones
+type Level int
+
+const (
+	Anyone Level = iota
+	Voice
+	Op
+	Owner
+)
+
+func (l Level) String() string {
+	switch l {
+	case Voice:
+		return "voice"
+	case Op:
+		return "operator"
+	case Owner:
+		return "bot owner"
+	default:
+		return "anyone"
+	}
+}
+
+// channel mode bits a nick can hold
+const (
+	modeVoice uint8 = 1 << iota
+	modeHalfOp
+	modeOp
+	modeAdmin
+	modeOwner
+)
+
+// prefixModes maps NAMES prefixes to mode bits
+var prefixModes = map[byte]uint8{
+	'+': modeVoice,
+	'%': modeHalfOp,
+	'@': modeOp,
+	'&': modeAdmin,
+	'~': modeOwner,
+}
+
+// letterModes maps MODE letters to mode bits
+var letterModes = map[byte]uint8{
+	'v': modeVoice,
+	'h': modeHalfOp,
+	'o': modeOp,
+	'a': modeAdmin,
+	'q': modeOwner,
+}
+
+// Checker works out a user's level from config host masks and channel modes.
+// Channel modes are tracked from NAMES replies and MODE/JOIN/PART/KICK/QUIT/NICK events.
+type Checker struct {
+	mu       sync.RWMutex
+	owners   []string
+	trusted  []string
+	channels map[string]map[string]uint8 // channel -> nick -> modes
+}
+
+func NewChecker(cfg config.PermissionsConfig) *Checker {
+	return &Checker{
+		owners:   cfg.Owners,
+		trusted:  cfg.Trusted,
+		channels: make(map[string]map[string]uint8),
+	}
+}
+
+// LevelOf returns the level of nick!ident@host in channel
+func (c *Checker) LevelOf(channel, nick, ident, host string) Level {
+	mask := nick + "!" + ident + "@" + host
+	if matchAny(c.owners, mask) {
+		return Owner
+	}
+
+	level := Anyone
+	if matchAny(c.trusted, mask) {
+		level = Op
+	}
+
+	c.mu.RLock()
+	modes := c.channels[key(channel)][key(nick)]
+	c.mu.RUnlock()
+
+	switch {
+	case modes&(modeHalfOp|modeOp|modeAdmin|modeOwner) != 0:
+		level = max(level, Op)
+	case modes&modeVoice != 0:
+		level = max(level, Voice)
+	}
+	return level
+}
+
+// Names records a RPL_NAMREPLY (353) list, e.g. "@alice +bob carol"
+func (c *Checker) Names(channel, names string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nicks := c.channel(channel)
+	for _, name := range strings.Fields(names) {
+		var modes uint8
+		for len(name) > 0 {
+			bit, ok := prefixModes[name[0]]
+			if !ok {
+				break
+			}
+			modes |= bit
+			name = name[1:]
+		}
+		// userhost-in-names sends nick!ident@host
+		name, _, _ = strings.Cut(name, "!")
+		if name != "" {
+			nicks[key(name)] = modes
+		}
+	}
+}
+
+// Mode applies a channel MODE change, e.g. ("+ov-v", ["alice", "bob", "carol"])
+func (c *Checker) Mode(channel, modes string, params []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nicks := c.channel(channel)
+	adding := true
+	for i := 0; i < len(modes); i++ {
+		m := modes[i]
+		switch m {
+		case '+':
+			adding = true
+			continue
+		case '-':
+			adding = false
+			continue
+		}
+
+		if !takesParam(m, adding) {
+			continue
+		}
+		if len(params) == 0 {
+			return
+		}
+		param := params[0]
+		params = params[1:]
+
+		bit, ok := letterModes[m]
+		if !ok {
+			continue
+		}
+		if adding {
+			nicks[key(param)] |= bit
+		} else {
+			nicks[key(param)] &^= bit
+		}
+	}
+}
+
+// takesParam reports whether a channel mode letter consumes a MODE parameter
+func takesParam(m byte, adding bool) bool {
+	switch m {
+	case 'v', 'h', 'o', 'a', 'q', 'b', 'e', 'I', 'k':
+		return true
+	case 'l', 'j', 'f':
+		return adding
+	}
+	return false
+}
+
+// Join notes nick joined channel without any modes
+func (c *Checker) Join(channel, nick string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.channel(channel)[key(nick)] = 0
+}
+
+// Part forgets nick in channel
+func (c *Checker) Part(channel, nick string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.channels[key(channel)], key(nick))
+}
+
+// Quit forgets nick in every channel
+func (c *Checker) Quit(nick string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, nicks := range c.channels {
+		delete(nicks, key(nick))
+	}
+}
+
+// Rename moves modes held by oldNick to newNick
+func (c *Checker) Rename(oldNick, newNick string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, nicks := range c.channels {
+		if modes, ok := nicks[key(oldNick)]; ok {
+			delete(nicks, key(oldNick))
+			nicks[key(newNick)] = modes
+		}
+	}
+}
+
+// Forget drops everything known about channel, e.g. when the bot leaves it
+func (c *Checker) Forget(channel string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.channels, key(channel))
+}
+
+func (c *Checker) channel(channel string) map[string]uint8 {
+	nicks, ok := c.channels[key(channel)]
+	if !ok {
+		nicks = make(map[string]uint8)
+		c.channels[key(channel)] = nicks
+	}
+	return nicks
+}
+
+func key(s string) string {
+	return strings.ToLower(s)
+}
+
+func matchAny(masks []string, s string) bool {
+	for _, mask := range masks {
+		if MatchMask(mask, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchMask matches an IRC host mask where * matches any run of characters
+// and ? matches one character. Matching is case-insensitive.
+func MatchMask(mask, s string) bool {
+	mask, s = strings.ToLower(strings.TrimSpace(mask)), strings.ToLower(s)
+	if mask == "" {
+		return false
+	}
+
+	// iterative glob match with backtracking to the last *
+	m, i := 0, 0
+	star, mark := -1, 0
+	for i < len(s) {
+		switch {
+		case m < len(mask) && (mask[m] == '?' || mask[m] == s[i]):
+			m++
+			i++
+		case m < len(mask) && mask[m] == '*':
+			star, mark = m, i
+			m++
+		case star >= 0:
+			m = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+	for m < len(mask) && mask[m] == '*' {
+		m++
+	}
+	return m == len(mask)
+}
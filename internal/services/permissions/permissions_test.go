@@ -0,0 +1,75 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchMask(t *testing.T) {
+	tests := []struct {
+		mask string
+		s    string
+		want bool
+	}{
+		{"*!*@admin.example", "alice!al@admin.example", true},
+		{"*!*@ADMIN.example", "alice!al@admin.example", true},
+		{"*!*@admin.example", "alice!al@evil.example", false},
+		{"alice!*@*", "alice!al@anywhere", true},
+		{"alice!*@*", "malice!al@anywhere", false},
+		{"[dev]?!*@*", "[dev]1!x@y", true},
+		{"*", "anyone!a@b", true},
+		{"", "anyone!a@b", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mask+" "+tt.s, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchMask(tt.mask, tt.s))
+		})
+	}
+}
+
+func TestChecker_ConfigMasks(t *testing.T) {
+	c := NewChecker(config.PermissionsConfig{
+		Owners:  []string{"*!*@owner.example"},
+		Trusted: []string{"helper!*@*"},
+	})
+
+	assert.Equal(t, Owner, c.LevelOf("#chan", "boss", "b", "owner.example"))
+	assert.Equal(t, Op, c.LevelOf("#chan", "helper", "h", "somewhere"))
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "random", "r", "somewhere"))
+}
+
+func TestChecker_NamesAndModes(t *testing.T) {
+	c := NewChecker(config.PermissionsConfig{})
+
+	c.Names("#Chan", "@alice +bob @+carol dave ~eve")
+	assert.Equal(t, Op, c.LevelOf("#chan", "Alice", "", ""))
+	assert.Equal(t, Voice, c.LevelOf("#chan", "bob", "", ""))
+	assert.Equal(t, Op, c.LevelOf("#chan", "carol", "", ""))
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "dave", "", ""))
+	assert.Equal(t, Op, c.LevelOf("#chan", "eve", "", ""))
+	assert.Equal(t, Anyone, c.LevelOf("#other", "alice", "", ""))
+
+	// -o leaves carol's voice; bans and limits consume their own params
+	c.Mode("#chan", "-o+b+lv", []string{"carol", "*!*@spam", "10", "dave"})
+	assert.Equal(t, Voice, c.LevelOf("#chan", "carol", "", ""))
+	assert.Equal(t, Voice, c.LevelOf("#chan", "dave", "", ""))
+
+	c.Rename("dave", "david")
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "dave", "", ""))
+	assert.Equal(t, Voice, c.LevelOf("#chan", "david", "", ""))
+
+	c.Part("#chan", "alice")
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "alice", "", ""))
+
+	c.Quit("bob")
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "bob", "", ""))
+
+	c.Join("#chan", "bob")
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "bob", "", ""))
+
+	c.Forget("#chan")
+	assert.Equal(t, Anyone, c.LevelOf("#chan", "david", "", ""))
+}
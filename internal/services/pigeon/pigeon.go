@@ -1,10 +1,29 @@
 package pigeon
 
+import (
+	"strings"
+
+	"github.com/MyelinBots/pigeonbot-go/config"
+)
+
 // Pigeon struct represents a pigeon with attributes for type, points, and success rate
 type Pigeon struct {
 	Type    string
 	Points  int
 	Success int
+	// Weight is the relative chance of this pigeon being picked on spawn;
+	// 0 only spawns it with !forcespawn
+	Weight int
+	Eggs   EggRules
+}
+
+// EggRules describe how many eggs a mating pair lays and how many of them crack.
+// Cracked eggs are rolled in [CrackMin, CrackMax], clamped to the eggs laid.
+type EggRules struct {
+	Min      int
+	Max      int
+	CrackMin int
+	CrackMax int
 }
 
 func NewPigeon(pigeonType string, points, success int) *Pigeon {
@@ -12,13 +31,82 @@ func NewPigeon(pigeonType string, points, success int) *Pigeon {
 		Type:    pigeonType,
 		Points:  points,
 		Success: success,
+		Weight:  1,
 	}
 }
 
 func PredefinedPigeons() []*Pigeon {
 	return []*Pigeon{
-		NewPigeon("cartel member", 10, 85),
-		NewPigeon("boss", 100, 25),
-		NewPigeon("white", 50, 50),
+		{Type: "cartel member", Points: 10, Success: 85, Weight: 1, Eggs: EggRules{Min: 1, Max: 1, CrackMin: 1, CrackMax: 1}},
+		{Type: "boss", Points: 100, Success: 25, Weight: 1, Eggs: EggRules{Min: 5, Max: 5, CrackMin: 1, CrackMax: 5}},
+		{Type: "white", Points: 50, Success: 50, Weight: 1, Eggs: EggRules{Min: 2, Max: 2, CrackMin: 1, CrackMax: 2}},
+	}
+}
+
+// FromConfig builds pigeons from catalogue entries, skipping entries without a type
+func FromConfig(entries []config.PigeonConfig) []*Pigeon {
+	pigeons := make([]*Pigeon, 0, len(entries))
+	for _, e := range entries {
+		if strings.TrimSpace(e.Type) == "" {
+			continue
+		}
+
+		weight := 1
+		if e.Weight != nil {
+			weight = max(*e.Weight, 0)
+		}
+		eggsMax := e.EggsMax
+		if eggsMax < e.EggsMin {
+			eggsMax = e.EggsMin
+		}
+
+		pigeons = append(pigeons, &Pigeon{
+			Type:    strings.TrimSpace(e.Type),
+			Points:  e.Points,
+			Success: e.Success,
+			Weight:  weight,
+			Eggs: EggRules{
+				Min:      e.EggsMin,
+				Max:      eggsMax,
+				CrackMin: e.CrackMin,
+				CrackMax: e.CrackMax,
+			},
+		})
+	}
+	return pigeons
+}
+
+// Find returns the pigeon with the given type (case-insensitive), or nil
+func Find(pigeons []*Pigeon, pigeonType string) *Pigeon {
+	pigeonType = strings.TrimSpace(pigeonType)
+	for _, p := range pigeons {
+		if strings.EqualFold(p.Type, pigeonType) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Pick chooses a pigeon by spawn weight. intN must return a value in [0, n).
+func Pick(pigeons []*Pigeon, intN func(n int) int) *Pigeon {
+	total := 0
+	for _, p := range pigeons {
+		total += p.weight()
 	}
+	if total == 0 {
+		return nil
+	}
+
+	roll := intN(total)
+	for _, p := range pigeons {
+		roll -= p.weight()
+		if roll < 0 {
+			return p
+		}
+	}
+	return nil
+}
+
+func (p *Pigeon) weight() int {
+	return max(p.Weight, 0)
 }
@@ -3,6 +3,7 @@ package pigeon_test
 import (
 	"testing"
 
+	"github.com/MyelinBots/pigeonbot-go/config"
 	"github.com/MyelinBots/pigeonbot-go/internal/services/pigeon"
 	"github.com/stretchr/testify/assert"
 )
@@ -116,6 +117,69 @@ func TestPredefinedPigeons_PointsVsSuccess(t *testing.T) {
 	}
 }
 
+func TestPredefinedPigeons_Eggs(t *testing.T) {
+	pigeons := pigeon.PredefinedPigeons()
+
+	assert.Equal(t, pigeon.EggRules{Min: 1, Max: 1, CrackMin: 1, CrackMax: 1}, findPigeonByType(pigeons, "cartel member").Eggs)
+	assert.Equal(t, pigeon.EggRules{Min: 2, Max: 2, CrackMin: 1, CrackMax: 2}, findPigeonByType(pigeons, "white").Eggs)
+	assert.Equal(t, pigeon.EggRules{Min: 5, Max: 5, CrackMin: 1, CrackMax: 5}, findPigeonByType(pigeons, "boss").Eggs)
+}
+
+func TestFromConfig(t *testing.T) {
+	pigeons := pigeon.FromConfig([]config.PigeonConfig{
+		{Type: " golden ", Points: 1000, Success: 5, Weight: weight(3), EggsMin: 2, EggsMax: 1, CrackMin: 0, CrackMax: 1},
+		{Type: "", Points: 1},
+		{Type: "plain", Points: 1, Success: 90},
+		{Type: "retired", Points: 1, Success: 90, Weight: weight(0)},
+	})
+
+	assert.Len(t, pigeons, 3)
+
+	assert.Equal(t, "golden", pigeons[0].Type)
+	assert.Equal(t, 3, pigeons[0].Weight)
+	assert.Equal(t, pigeon.EggRules{Min: 2, Max: 2, CrackMin: 0, CrackMax: 1}, pigeons[0].Eggs)
+
+	// Weight defaults to 1; 0 turns the pigeon off
+	assert.Equal(t, 1, pigeons[1].Weight)
+	assert.Equal(t, 0, pigeons[2].Weight)
+}
+
+func weight(w int) *int { return &w }
+
+func TestFind(t *testing.T) {
+	pigeons := pigeon.PredefinedPigeons()
+
+	assert.Equal(t, "boss", pigeon.Find(pigeons, " BOSS ").Type)
+	assert.Nil(t, pigeon.Find(pigeons, "unknown"))
+	assert.Nil(t, pigeon.Find(nil, "boss"))
+}
+
+func TestPick(t *testing.T) {
+	pigeons := []*pigeon.Pigeon{
+		{Type: "common", Weight: 3},
+		{Type: "rare", Weight: 1},
+	}
+
+	// Rolls 0..2 land on common, 3 on rare
+	for roll := 0; roll < 3; roll++ {
+		assert.Equal(t, "common", pigeon.Pick(pigeons, func(n int) int {
+			assert.Equal(t, 4, n)
+			return roll
+		}).Type)
+	}
+	assert.Equal(t, "rare", pigeon.Pick(pigeons, func(int) int { return 3 }).Type)
+
+	assert.Nil(t, pigeon.Pick(nil, func(int) int { return 0 }))
+
+	// weight 0 never spawns
+	pigeons = append(pigeons, &pigeon.Pigeon{Type: "retired", Weight: 0})
+	assert.Equal(t, "rare", pigeon.Pick(pigeons, func(n int) int {
+		assert.Equal(t, 4, n)
+		return 3
+	}).Type)
+	assert.Nil(t, pigeon.Pick(pigeons[2:], func(int) int { return 0 }))
+}
+
 // Helper function to find a pigeon by type
 func findPigeonByType(pigeons []*pigeon.Pigeon, pigeonType string) *pigeon.Pigeon {
 	for _, p := range pigeons {
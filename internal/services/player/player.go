@@ -7,6 +7,14 @@ type Player struct {
 	Name   string
 	Points int
 	Count  int
+
+	Shots      int // shots fired at a pigeon
+	Misses     int
+	Streak     int // hits in a row so far
+	BestStreak int
+
+	Eggs     int
+	RareEggs int // rare eggs are counted in Eggs too
 }
 
 // StartingPoints is the default initial points for players
@@ -26,38 +34,72 @@ func (p *Player) String() string {
 	return fmt.Sprintf("%s has %d points.", p.Name, p.Points)
 }
 
+// level is reached once a player has shot MinCount pigeons
+type level struct {
+	MinCount int
+	Name     string
+}
+
+// levels are ordered by MinCount
+var levels = []level{
+	{0, "Beginner 🐣"},
+	{10, "Initiate 🐦"},
+	{101, "Adept 🦅"},
+	{200, "Expert 🕊️"},
+	{500, "Master 🦜"},
+	{800, "Grandmaster 🐔"},
+	{1000, "Legendary Phoenix 🐉🔥"},
+	{3000, "Mythic Dragon 🐲✨"},
+	{5000, "Cosmic Falcon 🌌🦅"},
+	{10000, "Lord of Pigeons 👑🐦"},
+	{15000, "Pigeon Emperor 🏯🐦"},
+	{25000, "Sky Tyrant ☁️🐲"},
+	{40000, "Celestial Hunter 🌠🦅"},
+	{60000, "Eternal Wing 🕊️♾️"},
+	{100000, "Pigeon God ☄️👁️"},
+}
+
 // Helper function to determine the player's level
 func (p *Player) GetPlayerLevel() string {
-	switch {
-	case p.Count < 10:
-		return "Beginner 🐣"
-	case p.Count <= 100:
-		return "Initiate 🐦"
-	case p.Count <= 199:
-		return "Adept 🦅"
-	case p.Count <= 499:
-		return "Expert 🕊️"
-	case p.Count <= 799:
-		return "Master 🦜"
-	case p.Count <= 999:
-		return "Grandmaster 🐔"
-	case p.Count <= 2999:
-		return "Legendary Phoenix 🐉🔥"
-	case p.Count <= 4999:
-		return "Mythic Dragon 🐲✨"
-	case p.Count <= 9999:
-		return "Cosmic Falcon 🌌🦅"
-	case p.Count <= 14999:
-		return "Lord of Pigeons 👑🐦"
-	case p.Count <= 24999:
-		return "Pigeon Emperor 🏯🐦"
-	case p.Count <= 39999:
-		return "Sky Tyrant ☁️🐲"
-	case p.Count <= 59999:
-		return "Celestial Hunter 🌠🦅"
-	case p.Count <= 99999:
-		return "Eternal Wing 🕊️♾️"
-	default:
-		return "Pigeon God ☄️👁️"
+	name := levels[0].Name
+	for _, l := range levels {
+		if p.Count < l.MinCount {
+			break
+		}
+		name = l.Name
+	}
+	return name
+}
+
+// NextLevel returns the level after the player's current one and the number of
+// pigeons still to shoot to reach it. ok is false at the top level.
+func (p *Player) NextLevel() (name string, remaining int, ok bool) {
+	for _, l := range levels {
+		if p.Count < l.MinCount {
+			return l.Name, l.MinCount - p.Count, true
+		}
+	}
+	return "", 0, false
+}
+
+// RecordShot counts a shot at a pigeon and keeps the hit streak
+func (p *Player) RecordShot(hit bool) {
+	p.Shots++
+	if !hit {
+		p.Misses++
+		p.Streak = 0
+		return
+	}
+	p.Streak++
+	if p.Streak > p.BestStreak {
+		p.BestStreak = p.Streak
+	}
+}
+
+// Accuracy is the share of shots that hit, in percent
+func (p *Player) Accuracy() float64 {
+	if p.Shots == 0 {
+		return 0
 	}
+	return float64(p.Shots-p.Misses) * 100 / float64(p.Shots)
 }
@@ -146,3 +146,33 @@ func TestPlayer_GetPlayerLevel(t *testing.T) {
 func TestStartingPoints(t *testing.T) {
 	assert.Equal(t, 0, player.StartingPoints)
 }
+
+func TestPlayer_NextLevel(t *testing.T) {
+	next, remaining, ok := player.NewPlayer("alice", 0, 7).NextLevel()
+	assert.True(t, ok)
+	assert.Equal(t, "Initiate 🐦", next)
+	assert.Equal(t, 3, remaining)
+
+	next, remaining, ok = player.NewPlayer("alice", 0, 100).NextLevel()
+	assert.True(t, ok)
+	assert.Equal(t, "Adept 🦅", next)
+	assert.Equal(t, 1, remaining)
+
+	_, _, ok = player.NewPlayer("alice", 0, 100000).NextLevel()
+	assert.False(t, ok)
+}
+
+func TestPlayer_RecordShot(t *testing.T) {
+	p := player.NewPlayer("alice", 0, 0)
+	assert.Equal(t, 0.0, p.Accuracy())
+
+	for _, hit := range []bool{true, true, false, true, true, true, false} {
+		p.RecordShot(hit)
+	}
+
+	assert.Equal(t, 7, p.Shots)
+	assert.Equal(t, 2, p.Misses)
+	assert.Equal(t, 0, p.Streak)
+	assert.Equal(t, 3, p.BestStreak)
+	assert.InDelta(t, 71.43, p.Accuracy(), 0.01)
+}
@@ -0,0 +1,184 @@
+package shotlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+)
+
+const (
+	DefaultBatchSize     = 50
+	DefaultFlushInterval = 5 * time.Second
+	queueSize            = 1000  // shots waiting to be written before new ones are dropped
+	maxPendingShots      = 10000 // shots kept while the database is away before new ones are dropped
+	// failed writes are retried after minRetryDelay, doubling up to
+	// maxRetryDelay, as the game does for scores
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// Clock abstracts time so batching and retries can be tested without real sleeps
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Writer stores shot events in the background, in batches, so recording a
+// shot never waits on the database. Batches that fail are kept and retried
+// with backoff until the database is back.
+type Writer struct {
+	repo       shot.ShotEventRepository
+	batchSize  int
+	flushEvery time.Duration
+	clock      Clock
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *shot.ShotEvent
+	done   chan struct{}
+
+	giveUp   chan struct{} // closed when Close stops waiting for unsaved shots
+	giveUpMu sync.Once
+}
+
+// NewWriter starts a writer that saves a batch once batchSize shots are
+// queued or flushEvery has passed, whichever comes first
+func NewWriter(repo shot.ShotEventRepository, batchSize int, flushEvery time.Duration) *Writer {
+	return newWriter(repo, batchSize, flushEvery, realClock{})
+}
+
+func newWriter(repo shot.ShotEventRepository, batchSize int, flushEvery time.Duration, clock Clock) *Writer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if flushEvery <= 0 {
+		flushEvery = DefaultFlushInterval
+	}
+
+	w := &Writer{
+		repo:       repo,
+		batchSize:  batchSize,
+		flushEvery: flushEvery,
+		clock:      clock,
+		queue:      make(chan *shot.ShotEvent, queueSize),
+		done:       make(chan struct{}),
+		giveUp:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Record queues e; it is dropped if the queue is full or the writer is closed
+func (w *Writer) Record(e *shot.ShotEvent) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- e:
+	default:
+		fmt.Printf("Shot log queue full, dropping shot by %s in %s\n", e.Name, e.Channel)
+	}
+}
+
+// Close writes whatever is queued, retrying failed batches, and stops the
+// writer. Shots recorded afterwards are dropped. It gives up when ctx is
+// done, losing any shots still unsaved.
+func (w *Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		w.giveUpMu.Do(func() { close(w.giveUp) })
+		return ctx.Err()
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	var (
+		pending []*shot.ShotEvent
+		queue   = w.queue
+		tick    = w.clock.After(w.flushEvery)
+		retry   <-chan time.Time // set while waiting to retry a failed write
+		delay   = minRetryDelay
+	)
+	flush := func() {
+		if err := w.write(&pending); err != nil {
+			fmt.Printf("Error saving %d shots, retrying in %s: %v\n", len(pending), delay, err)
+			retry = w.clock.After(delay)
+			delay = min(delay*2, maxRetryDelay)
+			return
+		}
+		retry, delay = nil, minRetryDelay
+	}
+
+	for {
+		select {
+		case e, ok := <-queue:
+			if !ok {
+				queue = nil
+				if retry == nil {
+					flush()
+				}
+				break
+			}
+			if len(pending) >= maxPendingShots {
+				fmt.Printf("Too many shots waiting to be saved, dropping shot by %s in %s\n", e.Name, e.Channel)
+				break
+			}
+			pending = append(pending, e)
+			if retry == nil && len(pending) >= w.batchSize {
+				flush()
+			}
+		case <-tick:
+			tick = w.clock.After(w.flushEvery)
+			if retry == nil {
+				flush()
+			}
+		case <-retry:
+			flush()
+		case <-w.giveUp:
+			if len(pending) > 0 {
+				fmt.Printf("%d shots not saved\n", len(pending))
+			}
+			return
+		}
+
+		if queue == nil && len(pending) == 0 {
+			return
+		}
+	}
+}
+
+// write saves pending in batches, in order, removing each batch once it is
+// saved. It stops at the first batch that fails.
+func (w *Writer) write(pending *[]*shot.ShotEvent) error {
+	for len(*pending) > 0 {
+		n := min(w.batchSize, len(*pending))
+		if err := w.repo.AddShots(context.Background(), (*pending)[:n]); err != nil {
+			return err
+		}
+		*pending = (*pending)[n:]
+	}
+	*pending = nil
+	return nil
+}
@@ -0,0 +1,202 @@
+package shotlog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot"
+	"github.com/MyelinBots/pigeonbot-go/internal/db/repositories/shot/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWriter_FlushesFullBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShotEventRepository(ctrl)
+
+	saved := make(chan []*shot.ShotEvent, 2)
+	repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, events []*shot.ShotEvent) error {
+		saved <- events
+		return nil
+	}).Times(2)
+
+	w := NewWriter(repo, 2, time.Hour)
+	w.Record(&shot.ShotEvent{Name: "alice"})
+	w.Record(&shot.ShotEvent{Name: "bob"})
+	w.Record(&shot.ShotEvent{Name: "carol"})
+
+	select {
+	case batch := <-saved:
+		require.Len(t, batch, 2)
+		assert.Equal(t, "alice", batch[0].Name)
+		assert.Equal(t, "bob", batch[1].Name)
+	case <-time.After(time.Second):
+		t.Fatal("full batch was not written")
+	}
+
+	// the partial batch is written on close
+	require.NoError(t, w.Close(context.Background()))
+	batch := <-saved
+	require.Len(t, batch, 1)
+	assert.Equal(t, "carol", batch[0].Name)
+}
+
+func TestWriter_FlushesOnInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShotEventRepository(ctrl)
+
+	saved := make(chan []*shot.ShotEvent, 1)
+	repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, events []*shot.ShotEvent) error {
+		saved <- events
+		return nil
+	}).Times(1)
+
+	w := NewWriter(repo, 100, 10*time.Millisecond)
+	defer w.Close(context.Background())
+	w.Record(&shot.ShotEvent{Name: "alice"})
+
+	select {
+	case batch := <-saved:
+		assert.Len(t, batch, 1)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not written on the interval")
+	}
+}
+
+func TestWriter_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShotEventRepository(ctrl)
+	clock := newFakeClock()
+
+	saved := make(chan []*shot.ShotEvent, 1)
+	gomock.InOrder(
+		repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).Return(errors.New("db down")),
+		repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, events []*shot.ShotEvent) error {
+			saved <- events
+			return nil
+		}),
+	)
+
+	w := newWriter(repo, 100, time.Hour, clock)
+	assert.Equal(t, time.Hour, <-clock.waits)
+	w.Record(&shot.ShotEvent{Name: "alice"})
+
+	// a failed write on close is retried until it is saved
+	closed := make(chan error)
+	go func() { closed <- w.Close(context.Background()) }()
+	assert.Equal(t, time.Second, <-clock.waits)
+	clock.Advance(time.Second)
+
+	require.NoError(t, <-closed)
+	assert.Equal(t, "alice", (<-saved)[0].Name)
+	require.NoError(t, w.Close(context.Background()), "closing twice is a no-op")
+
+	// dropped without another AddShots call
+	w.Record(&shot.ShotEvent{Name: "bob"})
+}
+
+func TestWriter_RetriesFailedBatchesWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShotEventRepository(ctrl)
+	clock := newFakeClock()
+
+	saved := make(chan string, 2)
+	save := func(_ context.Context, events []*shot.ShotEvent) error {
+		saved <- events[0].Name
+		return nil
+	}
+	gomock.InOrder(
+		repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).Return(errors.New("db down")).Times(2),
+		repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).DoAndReturn(save).Times(2),
+	)
+
+	w := newWriter(repo, 1, time.Hour, clock)
+	assert.Equal(t, time.Hour, <-clock.waits)
+
+	w.Record(&shot.ShotEvent{Name: "alice"})
+	assert.Equal(t, time.Second, <-clock.waits)
+	clock.Advance(time.Second)
+	assert.Equal(t, 2*time.Second, <-clock.waits, "the delay doubles")
+
+	// shots recorded meanwhile wait behind the failed batch
+	w.Record(&shot.ShotEvent{Name: "bob"})
+	clock.Advance(2 * time.Second)
+
+	assert.Equal(t, "alice", <-saved)
+	assert.Equal(t, "bob", <-saved)
+	require.NoError(t, w.Close(context.Background()))
+}
+
+func TestWriter_CloseGivesUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShotEventRepository(ctrl)
+
+	release := make(chan struct{})
+	repo.EXPECT().AddShots(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, []*shot.ShotEvent) error {
+		<-release
+		return nil
+	}).Times(1)
+
+	w := NewWriter(repo, 1, time.Hour)
+	w.Record(&shot.ShotEvent{Name: "alice"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, w.Close(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, w.Close(context.Background()))
+}
+
+// fakeClock fires After channels only when the test advances it
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	waits   chan time.Duration // every duration passed to After
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Now(), waits: make(chan time.Duration, 100)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.waits <- d
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- c.now
+			continue
+		}
+		pending = append(pending, w)
+	}
+	c.waiters = pending
+}